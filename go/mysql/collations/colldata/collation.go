@@ -382,6 +382,28 @@ coerceToRight:
 		}, nil, nil
 }
 
+// CanCoerce returns the collation that two operands with collations a and b,
+// e.g. two columns, are compared with. Both operands have the IMPLICIT
+// coercibility of a column, and they are merged with Merge. An error is
+// returned if MySQL would reject the comparison with an illegal mix of
+// collations, which includes merges that result in a NONE coercibility.
+func CanCoerce(env *collations.Environment, a, b collations.ID) (collations.ID, error) {
+	left := collations.TypedCollation{Collation: a, Coercibility: collations.CoerceImplicit, Repertoire: collations.RepertoireUnicode}
+	right := collations.TypedCollation{Collation: b, Coercibility: collations.CoerceImplicit, Repertoire: collations.RepertoireUnicode}
+	merged, _, _, err := Merge(env, left, right, CoercionOptions{
+		ConvertToSuperset:   true,
+		ConvertWithCoercion: true,
+	})
+	if err != nil {
+		return collations.Unknown, err
+	}
+	if merged.Coercibility == collations.CoerceNone {
+		return collations.Unknown, fmt.Errorf("Illegal mix of collations (%s,%s) and (%s,%s)",
+			env.LookupName(a), left.Coercibility, env.LookupName(b), right.Coercibility)
+	}
+	return merged.Collation, nil
+}
+
 func Index(col Collation, str, sub []byte, offset int) int {
 	cs := col.Charset()
 	if offset > 0 {
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package colldata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql/collations"
+)
+
+func TestCanCoerce(t *testing.T) {
+	env := collations.MySQL8()
+	lookup := func(name string) collations.ID {
+		id := env.LookupByName(name)
+		assert.NotEqual(t, collations.Unknown, id, name)
+		return id
+	}
+
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"utf8mb4_general_ci", "utf8mb4_general_ci", "utf8mb4_general_ci"},
+		{"utf8mb4_general_ci", "utf8mb4_bin", "utf8mb4_bin"},
+		{"utf8mb4_bin", "utf8mb4_general_ci", "utf8mb4_bin"},
+		{"utf8mb4_0900_ai_ci", "utf8mb3_general_ci", "utf8mb4_0900_ai_ci"},
+		{"utf8mb3_general_ci", "utf8mb4_0900_ai_ci", "utf8mb4_0900_ai_ci"},
+		{"latin1_swedish_ci", "utf8mb3_general_ci", "utf8mb3_general_ci"},
+		{"binary", "latin1_swedish_ci", "binary"},
+		{"utf8mb4_0900_ai_ci", "binary", "binary"},
+	}
+	for _, tc := range tests {
+		got, err := CanCoerce(env, lookup(tc.a), lookup(tc.b))
+		if assert.NoError(t, err, "%s / %s", tc.a, tc.b) {
+			assert.Equal(t, tc.want, env.LookupName(got), "%s / %s", tc.a, tc.b)
+		}
+	}
+
+	_, err := CanCoerce(env, lookup("utf8mb4_general_ci"), lookup("utf8mb4_unicode_ci"))
+	assert.EqualError(t, err, "Illegal mix of collations (utf8mb4_general_ci,IMPLICIT) and (utf8mb4_unicode_ci,IMPLICIT)")
+
+	_, err = CanCoerce(env, lookup("latin1_swedish_ci"), lookup("latin2_general_ci"))
+	assert.Error(t, err)
+
+	_, err = CanCoerce(env, collations.Unknown, lookup("utf8mb4_bin"))
+	assert.Error(t, err)
+}
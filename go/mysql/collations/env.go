@@ -17,10 +17,14 @@ limitations under the License.
 package collations
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"slices"
 	"strings"
 	"sync"
+
+	"vitess.io/vitess/go/mysql/collations/charset"
 )
 
 type colldefaults struct {
@@ -64,7 +68,7 @@ func (env *Environment) LookupName(id ID) string {
 
 // DefaultCollationForCharset returns the default collation for a charset
 func (env *Environment) DefaultCollationForCharset(charset string) ID {
-	if defaults, ok := env.byCharset[charset]; ok {
+	if defaults, ok := env.charsetDefaults(charset); ok {
 		return defaults.Default
 	}
 	return Unknown
@@ -72,12 +76,41 @@ func (env *Environment) DefaultCollationForCharset(charset string) ID {
 
 // BinaryCollationForCharset returns the default binary collation for a charset
 func (env *Environment) BinaryCollationForCharset(charset string) ID {
-	if defaults, ok := env.byCharset[charset]; ok {
+	if defaults, ok := env.charsetDefaults(charset); ok {
 		return defaults.Binary
 	}
 	return Unknown
 }
 
+// normalizeCharsetName returns the given charset or collation name as it is
+// known to the Environment: charset names sent by clients can have surrounding
+// whitespace or a different casing.
+func normalizeCharsetName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// charsetDefaults returns the defaults of the given charset, after normalizing
+// its name. A name that is neither a charset nor a collation, but a charset
+// followed by a single collation region such as `utf8mb4_de`, falls back to
+// that charset.
+func (env *Environment) charsetDefaults(charset string) (*colldefaults, bool) {
+	charset = normalizeCharsetName(charset)
+	if defaults, ok := env.byCharset[charset]; ok {
+		return defaults, true
+	}
+	if _, ok := env.byName[charset]; ok {
+		return nil, false
+	}
+	if _, ok := env.unsupported[charset]; ok {
+		return nil, false
+	}
+	if region := strings.LastIndexByte(charset, '_'); region > 0 {
+		defaults, ok := env.byCharset[charset[:region]]
+		return defaults, ok
+	}
+	return nil, false
+}
+
 var globalEnvironments = make(map[collver]*Environment)
 var globalEnvironmentsMu sync.Mutex
 
@@ -131,6 +164,34 @@ func NewEnvironment(serverVersion string) *Environment {
 	return fetchCacheEnvironment(version)
 }
 
+// ServerVersion identifies a MySQL or MariaDB release that has its own set
+// of supported collations and charset defaults.
+type ServerVersion byte
+
+const (
+	ServerVersionMariaDB100 = ServerVersion(collverMariaDB100)
+	ServerVersionMariaDB101 = ServerVersion(collverMariaDB101)
+	ServerVersionMariaDB102 = ServerVersion(collverMariaDB102)
+	ServerVersionMariaDB103 = ServerVersion(collverMariaDB103)
+	ServerVersionMySQL56    = ServerVersion(collverMySQL56)
+	ServerVersionMySQL57    = ServerVersion(collverMySQL57)
+	ServerVersionMySQL8     = ServerVersion(collverMySQL8)
+)
+
+// NewEnvironmentForVersion returns the collation Environment for the given
+// server release, without having to build and parse a version string like
+// NewEnvironment does. Environments are cached, so repeated calls for the same
+// version return the same instance. Unknown versions fall back to MySQL 5.7,
+// like NewEnvironment does for unknown version strings.
+func NewEnvironmentForVersion(version ServerVersion) *Environment {
+	switch version {
+	case ServerVersionMariaDB100, ServerVersionMariaDB101, ServerVersionMariaDB102, ServerVersionMariaDB103,
+		ServerVersionMySQL56, ServerVersionMySQL57, ServerVersionMySQL8:
+		return fetchCacheEnvironment(collver(version))
+	}
+	return fetchCacheEnvironment(collverMySQL57)
+}
+
 func makeEnv(version collver) *Environment {
 	env := &Environment{
 		version:       version,
@@ -187,6 +248,105 @@ func makeEnv(version collver) *Environment {
 	return env
 }
 
+// TranslateCollation returns the ID of the collation in the to Environment that
+// has the same name as the given collation in the from Environment, taking into
+// account all the names a collation is known by (e.g. utf8_general_ci and
+// utf8mb3_general_ci). It returns false if the collation is not supported in
+// the from Environment, or if no collation with the same name is supported in
+// the to Environment.
+func TranslateCollation(from *Environment, to *Environment, id ID) (ID, bool) {
+	if !from.IsSupported(id) {
+		return Unknown, false
+	}
+	for _, alias := range globalVersionInfo[id].alias {
+		if alias.mask&from.version == 0 {
+			continue
+		}
+		if translated, ok := to.byName[alias.name]; ok {
+			return translated, true
+		}
+	}
+	return Unknown, false
+}
+
+// DefaultChange describes a charset whose default collation differs between
+// two Environments. Old or New is Unknown if the charset is not supported by
+// the corresponding Environment.
+type DefaultChange struct {
+	Charset string
+	Old     ID
+	New     ID
+}
+
+// DefaultChangesVsVersion returns the charsets whose default collation in this
+// Environment differs from the one of the given server release, sorted by
+// charset name. Charset aliases such as `utf8` are not reported separately.
+func (env *Environment) DefaultChangesVsVersion(version ServerVersion) []DefaultChange {
+	target := NewEnvironmentForVersion(version)
+	aliases := charsetAliases()
+
+	defaultFor := func(env *Environment, charset string) ID {
+		if defaults := env.byCharset[charset]; defaults != nil {
+			return defaults.Default
+		}
+		return Unknown
+	}
+
+	var changes []DefaultChange
+	addChange := func(charset string) {
+		if _, alias := aliases[charset]; alias {
+			return
+		}
+		oldID, newID := defaultFor(env, charset), defaultFor(target, charset)
+		if oldID != newID {
+			changes = append(changes, DefaultChange{Charset: charset, Old: oldID, New: newID})
+		}
+	}
+	for charset := range env.byCharset {
+		addChange(charset)
+	}
+	for charset := range target.byCharset {
+		if _, ok := env.byCharset[charset]; !ok {
+			addChange(charset)
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b DefaultChange) int {
+		return strings.Compare(a.Charset, b.Charset)
+	})
+	return changes
+}
+
+// CatalogFingerprint returns a hex encoded hash over every collation name
+// supported by this Environment, together with its ID, its charset and whether
+// it is the default and binary collation of that charset. It does not depend on
+// map iteration order, so it can be compared against a known value to detect a
+// build that uses a different collation catalog.
+func (env *Environment) CatalogFingerprint() string {
+	var entries []string
+	for collid, vi := range globalVersionInfo {
+		if !env.IsSupported(collid) {
+			continue
+		}
+		isDefault := vi.isdefault&env.version != 0
+		for _, alias := range vi.alias {
+			if alias.mask&env.version == 0 {
+				continue
+			}
+			isBinary := env.BinaryCollationForCharset(alias.charset) == collid
+			entries = append(entries, fmt.Sprintf("%d\t%s\t%s\t%t\t%t", collid, alias.name, alias.charset, isDefault, isBinary))
+		}
+	}
+	slices.Sort(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // A few interesting character set values.
 // See http://dev.mysql.com/doc/internals/en/character-set.html#packet-Protocol::CharacterSet
 const (
@@ -212,7 +372,7 @@ var SystemCollation = TypedCollation{
 // this mapping will change, so it's important to use this helper so that
 // Vitess code has a consistent mapping for the active collations environment.
 func (env *Environment) CharsetAlias(charset string) (alias string, ok bool) {
-	alias, ok = charsetAliases()[charset]
+	alias, ok = charsetAliases()[normalizeCharsetName(charset)]
 	return
 }
 
@@ -257,6 +417,17 @@ func (env *Environment) DefaultConnectionCharset() ID {
 	}
 }
 
+// DefaultConnectionTypedCollation returns the default connection charset for this
+// environment as a TypedCollation, with the same coercibility and repertoire that
+// MySQL assigns to string literals in a connection.
+func (env *Environment) DefaultConnectionTypedCollation() TypedCollation {
+	return TypedCollation{
+		Collation:    env.DefaultConnectionCharset(),
+		Coercibility: CoerceCoercible,
+		Repertoire:   RepertoireUnicode,
+	}
+}
+
 // ParseConnectionCharset parses the given charset name and returns its numerical
 // identifier to be used in a MySQL connection handshake. The charset name can be:
 // - the name of a character set, in which case the default collation ID for the
@@ -265,24 +436,64 @@ func (env *Environment) DefaultConnectionCharset() ID {
 // UNLESS the collation itself has an ID greater than 255; such collations are not
 // supported because they cannot be negotiated in a single byte in our connection
 // handshake.
+// - the name of a character set followed by a single collation region, such as
+// `utf8mb4_de`, which is handled like the character set.
 // - empty, in which case the default connection charset for this MySQL version
 // is returned.
+// Names are trimmed and lowercased first.
 func (env *Environment) ParseConnectionCharset(csname string) (ID, error) {
-	if csname == "" {
-		return env.DefaultConnectionCharset(), nil
+	collid, ok, reason := env.ConnectionCharsetDiagnostic(csname)
+	if !ok {
+		return 0, fmt.Errorf("unsupported connection charset: %q: %s", normalizeCharsetName(csname), reason)
 	}
+	return collid, nil
+}
+
+// NegotiatePreferred returns the ID of the first charset or collation name in
+// offered that can be used as a connection charset, following the same rules as
+// ParseConnectionCharset, so the caller's priority order is respected. If none
+// of them can be used, the returned error gives the reason for each of them.
+func (env *Environment) NegotiatePreferred(offered []string) (ID, error) {
+	if len(offered) == 0 {
+		return 0, fmt.Errorf("unsupported connection charset: no charset offered")
+	}
+	reasons := make([]string, 0, len(offered))
+	for _, name := range offered {
+		collid, ok, reason := env.ConnectionCharsetDiagnostic(name)
+		if ok {
+			return collid, nil
+		}
+		reasons = append(reasons, fmt.Sprintf("%q: %s", normalizeCharsetName(name), reason))
+	}
+	return 0, fmt.Errorf("unsupported connection charset: %s", strings.Join(reasons, "; "))
+}
 
-	var collid ID = 0
-	csname = strings.ToLower(csname)
-	if defaults, ok := env.byCharset[csname]; ok {
+// ConnectionCharsetDiagnostic checks whether the given charset or collation name
+// can be used as a connection charset, following the same rules as
+// ParseConnectionCharset. If it can't, ok is false and reason explains why in a
+// human readable form.
+func (env *Environment) ConnectionCharsetDiagnostic(name string) (collid ID, ok bool, reason string) {
+	name = normalizeCharsetName(name)
+	if name == "" {
+		return env.DefaultConnectionCharset(), true, ""
+	}
+
+	if defaults, found := env.charsetDefaults(name); found {
 		collid = defaults.Default
-	} else if coll, ok := env.byName[csname]; ok {
+		if collid == Unknown {
+			return Unknown, false, fmt.Sprintf("charset %q has no default collation in %s", name, env.version)
+		}
+	} else if coll, found := env.byName[name]; found {
 		collid = coll
+	} else if _, found := env.unsupported[name]; found {
+		return Unknown, false, fmt.Sprintf("collation %q is not supported", name)
+	} else {
+		return Unknown, false, fmt.Sprintf("unknown charset or collation %q", name)
 	}
-	if collid == 0 || collid > 255 {
-		return 0, fmt.Errorf("unsupported connection charset: %q", csname)
+	if collid > 255 {
+		return Unknown, false, fmt.Sprintf("collation ID %d exceeds 255, cannot be negotiated in handshake", collid)
 	}
-	return collid, nil
+	return collid, true, ""
 }
 
 func (env *Environment) AllCollationIDs() []ID {
@@ -294,8 +505,63 @@ func (env *Environment) AllCollationIDs() []ID {
 	return all
 }
 
+// AllCharsets returns the sorted names of all the charsets known to this
+// environment, including charset aliases such as `utf8`.
+func (env *Environment) AllCharsets() []string {
+	all := make([]string, 0, len(env.byCharset))
+	for cs, defaults := range env.byCharset {
+		if defaults != nil {
+			all = append(all, cs)
+		}
+	}
+	slices.Sort(all)
+	return all
+}
+
+// CollationsForCharset returns the sorted IDs of all the collations of the
+// given charset that are supported by this environment. Charset aliases are
+// resolved, so `utf8` and `utf8mb3` return the same collations.
+func (env *Environment) CollationsForCharset(charset string) []ID {
+	resolve := func(cs string) string {
+		if alias, ok := env.CharsetAlias(cs); ok {
+			return alias
+		}
+		return cs
+	}
+	charset = resolve(normalizeCharsetName(charset))
+
+	var all []ID
+	for collid, cs := range env.byCharsetName {
+		if resolve(cs) == charset {
+			all = append(all, collid)
+		}
+	}
+	slices.Sort(all)
+	return all
+}
+
+// UnsupportedCollations returns the sorted names of the collations that exist
+// in this environment's MySQL version but that this package cannot handle,
+// e.g. to sort or compare strings.
+func (env *Environment) UnsupportedCollations() []string {
+	all := make([]string, 0, len(env.unsupported))
+	for name := range env.unsupported {
+		all = append(all, name)
+	}
+	slices.Sort(all)
+	return all
+}
+
+// IsUnsupported returns true if the collation with the given name exists in
+// this environment's MySQL version but is not supported by this package. The
+// name is normalized like the charset names.
+func (env *Environment) IsUnsupported(name string) bool {
+	_, ok := env.unsupported[normalizeCharsetName(name)]
+	return ok
+}
+
 func (env *Environment) LookupByCharset(name string) *colldefaults {
-	return env.byCharset[name]
+	return env.byCharset[normalizeCharsetName(name)]
 }
 
 func (env *Environment) LookupCharsetName(coll ID) string {
@@ -306,3 +572,287 @@ func (env *Environment) IsSupported(coll ID) bool {
 	_, supported := env.byID[coll]
 	return supported
 }
+
+// UnionCompatible returns true if the two collations can be combined in the
+// branches of a UNION: they either belong to the same character set (after
+// resolving charset aliases such as `utf8`), or one of them is binary, to which
+// any other collation can be coerced.
+func (env *Environment) UnionCompatible(a, b ID) bool {
+	if a == b {
+		return true
+	}
+	if a == CollationBinaryID || b == CollationBinaryID {
+		return true
+	}
+	csa, csb := env.LookupCharsetName(a), env.LookupCharsetName(b)
+	if csa == "" || csb == "" {
+		return false
+	}
+	if alias, ok := env.CharsetAlias(csa); ok {
+		csa = alias
+	}
+	if alias, ok := env.CharsetAlias(csb); ok {
+		csb = alias
+	}
+	return csa == csb
+}
+
+// charsetMaxBytesPerChar is the maximum length in bytes of a character for
+// all the charsets known to MySQL, as reported by SHOW CHARACTER SET.
+var charsetMaxBytesPerChar = map[string]int{
+	"armscii8": 1,
+	"ascii":    1,
+	"big5":     2,
+	"binary":   1,
+	"cp1250":   1,
+	"cp1251":   1,
+	"cp1256":   1,
+	"cp1257":   1,
+	"cp850":    1,
+	"cp852":    1,
+	"cp866":    1,
+	"cp932":    2,
+	"dec8":     1,
+	"eucjpms":  3,
+	"euckr":    2,
+	"gb18030":  4,
+	"gb2312":   2,
+	"gbk":      2,
+	"geostd8":  1,
+	"greek":    1,
+	"hebrew":   1,
+	"hp8":      1,
+	"keybcs2":  1,
+	"koi8r":    1,
+	"koi8u":    1,
+	"latin1":   1,
+	"latin2":   1,
+	"latin5":   1,
+	"latin7":   1,
+	"macce":    1,
+	"macroman": 1,
+	"sjis":     2,
+	"swe7":     1,
+	"tis620":   1,
+	"ucs2":     2,
+	"ujis":     3,
+	"utf16":    4,
+	"utf16le":  4,
+	"utf32":    4,
+	"utf8mb3":  3,
+	"utf8mb4":  4,
+}
+
+// MaxBytesPerChar returns the maximum number of bytes that a single character
+// takes in the given charset, after resolving charset aliases such as `utf8`.
+// It returns false if the charset is not known to this environment.
+func (env *Environment) MaxBytesPerChar(charset string) (int, bool) {
+	charset = normalizeCharsetName(charset)
+	if alias, ok := env.CharsetAlias(charset); ok {
+		charset = alias
+	}
+	if env.LookupByCharset(charset) == nil {
+		return 0, false
+	}
+	maxLen, ok := charsetMaxBytesPerChar[charset]
+	return maxLen, ok
+}
+
+// MaxBytesPerCharForCollation returns the maximum number of bytes that a single
+// character takes in the charset of the given collation, e.g. to compute the
+// worst-case size of a VARCHAR column. It returns false if the collation is not
+// known to this environment.
+func (env *Environment) MaxBytesPerCharForCollation(id ID) (int, bool) {
+	charset, ok := env.byCharsetName[id]
+	if !ok {
+		return 0, false
+	}
+	return env.MaxBytesPerChar(charset)
+}
+
+// IsMultibyteCharset returns true if a single character of the given charset
+// can take more than one byte, after resolving charset aliases such as `utf8`.
+// It returns false for both values if the charset is not known to this environment.
+func (env *Environment) IsMultibyteCharset(charset string) (multibyte bool, ok bool) {
+	maxLen, ok := env.MaxBytesPerChar(charset)
+	return maxLen > 1, ok
+}
+
+// defaultSortKeyMultiplier is the conservative multiplier used by SortKeyMultiplier
+// for the collations that are not known to this environment.
+const defaultSortKeyMultiplier = 8
+
+// SortKeyMultiplier returns an approximate number of bytes that the weight string
+// of a single character takes in the given collation. It is meant to be used by
+// the planner to estimate the size of weight string columns, e.g. when sorting:
+//   - binary and 8-bit collations use a byte per character
+//   - other _bin collations use the encoded character
+//   - the `general_ci` unicode collations use 2 bytes per character
+//   - UCA 9.0.0 collations use weights for every level they compare (primary,
+//     secondary for accent sensitivity, tertiary for case sensitivity)
+//   - legacy UCA collations get the same conservative default as unknown collations
+func (env *Environment) SortKeyMultiplier(id ID) int {
+	if id == CollationBinaryID {
+		return 1
+	}
+	name := env.LookupName(id)
+	if name == "" {
+		return defaultSortKeyMultiplier
+	}
+	csname := env.LookupCharsetName(id)
+	unicode := charset.IsUnicodeByName(csname)
+	switch {
+	case !unicode && !charset.IsMultibyteByName(csname):
+		return 1
+	case strings.HasSuffix(name, "_bin"):
+		return 4
+	case strings.Contains(name, "_0900_"):
+		// two bytes per weight, and room for one expansion per character on every level
+		return 4 * ucaLevels(name)
+	case strings.HasSuffix(name, "_general_ci"):
+		return 2
+	case unicode:
+		return defaultSortKeyMultiplier
+	default:
+		return 2
+	}
+}
+
+// ucaLevels returns the number of levels compared by an UCA 9.0.0 collation, based
+// on its accent and case sensitivity suffixes.
+func ucaLevels(name string) int {
+	switch {
+	case strings.HasSuffix(name, "_ai_ci"):
+		return 1
+	case strings.HasSuffix(name, "_as_ci"):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// PadAttribute tells how trailing spaces are handled when comparing strings
+// in a collation, as shown in the PAD_ATTRIBUTE column of
+// INFORMATION_SCHEMA.COLLATIONS.
+type PadAttribute byte
+
+const (
+	// PadSpace collations compare strings as if the shorter one was padded
+	// with spaces, so trailing spaces are ignored.
+	PadSpace PadAttribute = iota
+	// NoPad collations compare trailing spaces like any other character.
+	NoPad
+)
+
+func (pa PadAttribute) String() string {
+	switch pa {
+	case PadSpace:
+		return "PAD SPACE"
+	case NoPad:
+		return "NO PAD"
+	default:
+		panic("invalid PadAttribute value")
+	}
+}
+
+// PadAttribute returns the pad attribute of the given collation, or false if
+// the collation is not known to this environment. The `binary` collation, the
+// UCA 9.0.0 collations and the MariaDB `_nopad_` collations are NO PAD, all the
+// other ones are PAD SPACE.
+func (env *Environment) PadAttribute(id ID) (PadAttribute, bool) {
+	name := env.LookupName(id)
+	switch {
+	case name == "":
+		return PadSpace, false
+	case id == CollationBinaryID, strings.Contains(name, "_0900_"), strings.Contains(name, "_nopad_"):
+		return NoPad, true
+	default:
+		return PadSpace, true
+	}
+}
+
+// collationSensitivity is the case and accent sensitivity of a collation.
+type collationSensitivity struct {
+	caseInsensitive   bool
+	accentInsensitive bool
+}
+
+// sensitivityOverrides holds the sensitivity of the collations whose name
+// does not follow the suffix conventions used by sensitivityByName, or
+// whose sensitivity is easy to get wrong from their name.
+var sensitivityOverrides = map[string]collationSensitivity{
+	"binary": {},
+}
+
+// sensitivitySuffixOverrides is like sensitivityOverrides, for the families
+// of collations sharing a name suffix. It is checked in order.
+var sensitivitySuffixOverrides = []struct {
+	suffix      string
+	sensitivity collationSensitivity
+}{
+	// the binary collations, including the MariaDB `_nopad_bin` ones,
+	// compare bytes or code points.
+	{"_bin", collationSensitivity{}},
+	// the MariaDB `_nopad_ci` collations, such as `utf8mb4_general_nopad_ci`,
+	// only differ from their PAD SPACE counterpart in trailing spaces.
+	{"_nopad_ci", collationSensitivity{caseInsensitive: true, accentInsensitive: true}},
+	// the legacy UCA 9.0.0 collations spell out both sensitivities.
+	{"_0900_as_cs", collationSensitivity{}},
+	{"_0900_as_ci", collationSensitivity{caseInsensitive: true}},
+	// the MariaDB `_thai_520_w2` collations compare up to the secondary
+	// weight, which holds accents but not case.
+	{"_thai_520_w2", collationSensitivity{caseInsensitive: true}},
+}
+
+// sensitivityByName returns the sensitivity of a collation from the suffixes
+// of its name: `_ci`/`_cs` for case, `_ai`/`_as` for accents and `_bin` for
+// both. As documented by MySQL, a collation whose name does not specify its
+// accent sensitivity is accent insensitive if and only if it is case
+// insensitive. The names listed in sensitivityOverrides and
+// sensitivitySuffixOverrides take precedence over these rules.
+func sensitivityByName(name string) collationSensitivity {
+	if s, ok := sensitivityOverrides[name]; ok {
+		return s
+	}
+	for _, override := range sensitivitySuffixOverrides {
+		if strings.HasSuffix(name, override.suffix) {
+			return override.sensitivity
+		}
+	}
+	var s collationSensitivity
+	accentSpecified := false
+	// the first part of the name is the charset
+	parts := strings.Split(name, "_")
+	for _, part := range parts[1:] {
+		switch part {
+		case "ci":
+			s.caseInsensitive = true
+		case "cs", "bin":
+			s.caseInsensitive = false
+		case "ai":
+			s.accentInsensitive, accentSpecified = true, true
+		case "as":
+			s.accentInsensitive, accentSpecified = false, true
+		}
+	}
+	if !accentSpecified {
+		s.accentInsensitive = s.caseInsensitive
+	}
+	return s
+}
+
+// IsCaseInsensitive returns true if the given collation compares strings
+// without regard to letter case, e.g. 'x' = 'X'. It returns false for
+// collations that are not known to this environment.
+func (env *Environment) IsCaseInsensitive(id ID) bool {
+	name := env.LookupName(id)
+	return name != "" && sensitivityByName(name).caseInsensitive
+}
+
+// IsAccentInsensitive returns true if the given collation compares strings
+// without regard to accents, e.g. 'e' = 'é'. It returns false for collations
+// that are not known to this environment.
+func (env *Environment) IsAccentInsensitive(id ID) bool {
+	name := env.LookupName(id)
+	return name != "" && sensitivityByName(name).accentInsensitive
+}
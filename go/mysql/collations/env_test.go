@@ -0,0 +1,518 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConnectionTypedCollation(t *testing.T) {
+	testCases := []struct {
+		version string
+		want    ID
+	}{
+		{"8.0.31", CollationUtf8mb4ID},
+		{"5.7.40", 45},
+		{"10.3.38-MariaDB", 45},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			env := NewEnvironment(tc.version)
+			typed := env.DefaultConnectionTypedCollation()
+			assert.Equal(t, tc.want, typed.Collation)
+			assert.Equal(t, CoerceCoercible, typed.Coercibility)
+			assert.Equal(t, RepertoireUnicode, typed.Repertoire)
+			assert.True(t, typed.Valid())
+		})
+	}
+}
+
+func TestNewEnvironmentForVersion(t *testing.T) {
+	testCases := []struct {
+		version       ServerVersion
+		serverVersion string
+	}{
+		{ServerVersionMySQL8, "8.0.31"},
+		{ServerVersionMySQL57, "5.7.40"},
+		{ServerVersionMySQL56, "5.6.51"},
+		{ServerVersionMariaDB103, "10.3.38-MariaDB"},
+		{ServerVersionMariaDB100, "10.0.38-MariaDB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.serverVersion, func(t *testing.T) {
+			env := NewEnvironmentForVersion(tc.version)
+			assert.Same(t, NewEnvironment(tc.serverVersion), env)
+			assert.Same(t, env, NewEnvironmentForVersion(tc.version))
+		})
+	}
+
+	assert.Same(t, MySQL8(), NewEnvironmentForVersion(ServerVersionMySQL8))
+	assert.Same(t, NewEnvironmentForVersion(ServerVersionMySQL57), NewEnvironmentForVersion(ServerVersion(0)))
+}
+
+func TestUnionCompatible(t *testing.T) {
+	env := MySQL8()
+	utf8mb4General := env.LookupByName("utf8mb4_general_ci")
+	utf8mb4Bin := env.LookupByName("utf8mb4_bin")
+	utf8mb3General := env.LookupByName("utf8mb3_general_ci")
+	utf8Bin := env.LookupByName("utf8_bin")
+	latin1 := env.LookupByName("latin1_swedish_ci")
+
+	assert.True(t, env.UnionCompatible(utf8mb4General, utf8mb4General))
+	assert.True(t, env.UnionCompatible(utf8mb4General, utf8mb4Bin))
+	assert.True(t, env.UnionCompatible(utf8mb3General, utf8Bin))
+	assert.True(t, env.UnionCompatible(latin1, CollationBinaryID))
+	assert.False(t, env.UnionCompatible(utf8mb4General, latin1))
+	assert.False(t, env.UnionCompatible(utf8mb4General, utf8mb3General))
+	assert.False(t, env.UnionCompatible(utf8mb4General, Unknown))
+}
+
+func TestSortKeyMultiplier(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		collation string
+		want      int
+	}{
+		{"binary", 1},
+		{"latin1_swedish_ci", 1},
+		{"utf8mb4_bin", 4},
+		{"utf8mb4_general_ci", 2},
+		{"utf8mb4_0900_ai_ci", 4},
+		{"utf8mb4_0900_as_cs", 12},
+		{"utf8mb4_unicode_ci", 8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.collation, func(t *testing.T) {
+			id, ok := env.LookupID(tc.collation)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, env.SortKeyMultiplier(id))
+		})
+	}
+
+	assert.Greater(t, env.SortKeyMultiplier(env.LookupByName("utf8mb4_0900_ai_ci")), env.SortKeyMultiplier(CollationBinaryID))
+	assert.Equal(t, defaultSortKeyMultiplier, env.SortKeyMultiplier(Unknown))
+}
+
+func TestMaxBytesPerChar(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		charset string
+		want    int
+		ok      bool
+	}{
+		{"utf8mb4", 4, true},
+		{"utf8", 3, true},
+		{"utf8mb3", 3, true},
+		{"latin1", 1, true},
+		{"binary", 1, true},
+		{"unknown", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.charset, func(t *testing.T) {
+			maxLen, ok := env.MaxBytesPerChar(tc.charset)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, maxLen)
+		})
+	}
+}
+
+func TestMaxBytesPerCharForCollation(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		collation string
+		want      int
+	}{
+		{"utf8mb4_0900_ai_ci", 4},
+		{"utf8mb4_general_ci", 4},
+		{"utf8mb3_general_ci", 3},
+		{"utf8_bin", 3},
+		{"ucs2_general_ci", 2},
+		{"utf16_general_ci", 4},
+		{"utf32_general_ci", 4},
+		{"sjis_japanese_ci", 2},
+		{"latin1_swedish_ci", 1},
+		{"binary", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.collation, func(t *testing.T) {
+			id, ok := env.LookupID(tc.collation)
+			assert.True(t, ok)
+			maxLen, ok := env.MaxBytesPerCharForCollation(id)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, maxLen)
+		})
+	}
+
+	maxLen, ok := env.MaxBytesPerCharForCollation(Unknown)
+	assert.False(t, ok)
+	assert.Zero(t, maxLen)
+}
+
+func TestIsMultibyteCharset(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		charset   string
+		multibyte bool
+		ok        bool
+	}{
+		{"utf8mb4", true, true},
+		{"utf8mb3", true, true},
+		{"utf8", true, true},
+		{"latin1", false, true},
+		{"binary", false, true},
+		{"unknown", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.charset, func(t *testing.T) {
+			multibyte, ok := env.IsMultibyteCharset(tc.charset)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.multibyte, multibyte)
+		})
+	}
+}
+
+func TestConnectionCharsetDiagnostic(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		name   string
+		want   ID
+		ok     bool
+		reason string
+	}{
+		{"", env.DefaultConnectionCharset(), true, ""},
+		{"utf8mb4", CollationUtf8mb4ID, true, ""},
+		{"latin1_swedish_ci", 8, true, ""},
+		{"UTF8MB4_BIN", 46, true, ""},
+		{" utf8mb4\t", CollationUtf8mb4ID, true, ""},
+		{"UTF8MB4_DE", CollationUtf8mb4ID, true, ""},
+		{"Latin1_Swedish_CI ", 8, true, ""},
+		{"  ", env.DefaultConnectionCharset(), true, ""},
+		{" Unknown ", Unknown, false, `unknown charset or collation "unknown"`},
+		{"unknown", Unknown, false, `unknown charset or collation "unknown"`},
+		{"utf8mb4_ja_0900_as_cs", Unknown, false, "collation ID 303 exceeds 255, cannot be negotiated in handshake"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collid, ok, reason := env.ConnectionCharsetDiagnostic(tc.name)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, collid)
+			assert.Equal(t, tc.reason, reason)
+
+			_, err := env.ParseConnectionCharset(tc.name)
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestCharsetNameNormalization(t *testing.T) {
+	env := MySQL8()
+
+	for _, name := range []string{"utf8mb4", "UTF8MB4", "Utf8mb4", " utf8mb4", "utf8mb4\n", "\tUTF8MB4 "} {
+		assert.Equal(t, ID(CollationUtf8mb4ID), env.DefaultCollationForCharset(name), "%q", name)
+		assert.Equal(t, env.LookupByName("utf8mb4_0900_bin"), env.BinaryCollationForCharset(name), "%q", name)
+	}
+	assert.Equal(t, env.LookupByName("utf8mb3_general_ci"), env.DefaultCollationForCharset(" UTF8 "))
+	assert.Equal(t, Unknown, env.DefaultCollationForCharset("utf8 mb4"))
+	assert.Equal(t, Unknown, env.BinaryCollationForCharset(""))
+
+	// a single trailing collation region is stripped from unknown names,
+	// but collation names are not taken for charsets.
+	for _, name := range []string{"utf8mb4_de", " UTF8MB4_JA\t"} {
+		assert.Equal(t, ID(CollationUtf8mb4ID), env.DefaultCollationForCharset(name), "%q", name)
+		assert.Equal(t, env.LookupByName("utf8mb4_0900_bin"), env.BinaryCollationForCharset(name), "%q", name)
+	}
+	assert.Equal(t, Unknown, env.DefaultCollationForCharset("utf8mb4_de_pb"))
+	assert.Equal(t, Unknown, env.DefaultCollationForCharset("utf8mb4_bin"))
+	assert.Equal(t, Unknown, env.DefaultCollationForCharset("latin2_czech_cs"))
+	assert.Equal(t, Unknown, env.DefaultCollationForCharset("_de"))
+
+	// the other charset accessors normalize the names the same way
+	for _, name := range []string{"UTF8MB4", " utf8mb4\t"} {
+		maxLen, ok := env.MaxBytesPerChar(name)
+		assert.True(t, ok, "%q", name)
+		assert.Equal(t, 4, maxLen, "%q", name)
+
+		multibyte, ok := env.IsMultibyteCharset(name)
+		assert.True(t, ok, "%q", name)
+		assert.True(t, multibyte, "%q", name)
+
+		assert.Equal(t, env.CollationsForCharset("utf8mb4"), env.CollationsForCharset(name), "%q", name)
+		assert.Same(t, env.LookupByCharset("utf8mb4"), env.LookupByCharset(name), "%q", name)
+	}
+	alias, ok := env.CharsetAlias(" UTF8")
+	assert.True(t, ok)
+	assert.Equal(t, "utf8mb3", alias)
+	assert.Equal(t, env.CollationsForCharset("utf8mb3"), env.CollationsForCharset("UTF8"))
+	assert.NotEmpty(t, env.CollationsForCharset("UTF8"))
+}
+
+func TestNegotiatePreferred(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		name    string
+		offered []string
+		want    ID
+		err     string
+	}{
+		{"first supported", []string{"utf8mb4", "latin1"}, CollationUtf8mb4ID, ""},
+		{"first unknown", []string{"unknown", "latin1_swedish_ci"}, 8, ""},
+		{"first exceeds 255", []string{"utf8mb4_ja_0900_as_cs", "UTF8MB4_BIN"}, 46, ""},
+		{"empty name", []string{""}, env.DefaultConnectionCharset(), ""},
+		{"none supported", []string{"unknown", "utf8mb4_ja_0900_as_cs"}, 0, `unsupported connection charset: "unknown": unknown charset or collation "unknown"; "utf8mb4_ja_0900_as_cs": collation ID 303 exceeds 255, cannot be negotiated in handshake`},
+		{"nothing offered", nil, 0, "unsupported connection charset: no charset offered"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collid, err := env.NegotiatePreferred(tc.offered)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, collid)
+		})
+	}
+}
+
+func TestTranslateCollation(t *testing.T) {
+	mysql8 := NewEnvironment("8.0.31")
+	mariadb := NewEnvironment("10.3.38-MariaDB")
+
+	testCases := []struct {
+		name     string
+		from, to *Environment
+		ok       bool
+	}{
+		{"utf8mb4_general_ci", mysql8, mariadb, true},
+		{"utf8mb4_general_ci", mariadb, mysql8, true},
+		{"utf8mb4_bin", mariadb, mysql8, true},
+		{"latin1_swedish_ci", mysql8, mariadb, true},
+		{"utf8mb3_general_ci", mysql8, mariadb, true},
+		{"utf8mb4_0900_ai_ci", mysql8, mariadb, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := tc.from.LookupByName(tc.name)
+			assert.NotEqual(t, Unknown, id)
+
+			translated, ok := TranslateCollation(tc.from, tc.to, id)
+			assert.Equal(t, tc.ok, ok)
+			if !tc.ok {
+				assert.Equal(t, Unknown, translated)
+				return
+			}
+			assert.True(t, tc.to.IsSupported(translated))
+			back, ok := TranslateCollation(tc.to, tc.from, translated)
+			assert.True(t, ok)
+			assert.Equal(t, id, back)
+		})
+	}
+
+	_, ok := TranslateCollation(mysql8, mariadb, Unknown)
+	assert.False(t, ok)
+}
+
+func TestDefaultChangesVsVersion(t *testing.T) {
+	mysql57 := NewEnvironment("5.7.40")
+	mysql8 := NewEnvironment("8.0.31")
+
+	assert.Equal(t, []DefaultChange{
+		{Charset: "utf8mb4", Old: 45, New: CollationUtf8mb4ID},
+	}, mysql57.DefaultChangesVsVersion(ServerVersionMySQL8))
+
+	assert.Equal(t, []DefaultChange{
+		{Charset: "utf8mb4", Old: CollationUtf8mb4ID, New: 45},
+	}, mysql8.DefaultChangesVsVersion(ServerVersionMySQL57))
+
+	assert.Empty(t, mysql8.DefaultChangesVsVersion(ServerVersionMySQL8))
+}
+
+func TestCatalogFingerprint(t *testing.T) {
+	mysql57 := NewEnvironment("5.7.40")
+	mysql8 := NewEnvironment("8.0.31")
+
+	fingerprint := mysql8.CatalogFingerprint()
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, mysql8.CatalogFingerprint())
+	assert.Equal(t, fingerprint, NewEnvironment("8.0.35").CatalogFingerprint())
+	assert.Equal(t, fingerprint, makeEnv(collverMySQL8).CatalogFingerprint())
+	assert.NotEqual(t, fingerprint, mysql57.CatalogFingerprint())
+}
+
+func TestPadAttribute(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		collation string
+		want      PadAttribute
+	}{
+		{"utf8mb4_0900_ai_ci", NoPad},
+		{"utf8mb4_0900_bin", NoPad},
+		{"binary", NoPad},
+		{"utf8mb4_general_ci", PadSpace},
+		{"utf8mb4_bin", PadSpace},
+		{"latin1_swedish_ci", PadSpace},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.collation, func(t *testing.T) {
+			id, ok := env.LookupID(tc.collation)
+			assert.True(t, ok)
+			pad, ok := env.PadAttribute(id)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, pad)
+		})
+	}
+
+	_, ok := env.PadAttribute(Unknown)
+	assert.False(t, ok)
+	assert.Equal(t, "NO PAD", NoPad.String())
+	assert.Equal(t, "PAD SPACE", PadSpace.String())
+}
+
+func TestAllCharsets(t *testing.T) {
+	for _, version := range []string{"8.0.31", "5.7.40", "10.3.38-MariaDB"} {
+		t.Run(version, func(t *testing.T) {
+			env := NewEnvironment(version)
+			all := env.AllCharsets()
+			assert.IsIncreasing(t, all)
+			assert.Contains(t, all, "utf8")
+			assert.Contains(t, all, "utf8mb3")
+			assert.Contains(t, all, "utf8mb4")
+			assert.Contains(t, all, "binary")
+			for _, cs := range all {
+				assert.NotNil(t, env.LookupByCharset(cs), cs)
+			}
+		})
+	}
+}
+
+func TestCaseAndAccentInsensitive(t *testing.T) {
+	env := MySQL8()
+
+	testCases := []struct {
+		collation         string
+		caseInsensitive   bool
+		accentInsensitive bool
+	}{
+		{"utf8mb4_0900_ai_ci", true, true},
+		{"utf8mb4_0900_as_ci", true, false},
+		{"utf8mb4_0900_as_cs", false, false},
+		{"utf8mb4_ja_0900_as_cs_ks", false, false},
+		{"utf8mb4_0900_bin", false, false},
+		{"utf8mb4_bin", false, false},
+		{"utf8mb4_general_ci", true, true},
+		{"utf8_general_ci", true, true},
+		{"latin1_general_cs", false, false},
+		{"latin1_swedish_ci", true, true},
+		{"binary", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.collation, func(t *testing.T) {
+			id, ok := env.LookupID(tc.collation)
+			assert.True(t, ok)
+			assert.Equal(t, tc.caseInsensitive, env.IsCaseInsensitive(id))
+			assert.Equal(t, tc.accentInsensitive, env.IsAccentInsensitive(id))
+		})
+	}
+
+	assert.False(t, env.IsCaseInsensitive(Unknown))
+	assert.False(t, env.IsAccentInsensitive(Unknown))
+
+	// the irregular names, including MariaDB collations that are not
+	// supported, and so cannot be looked up
+	for name, want := range map[string]collationSensitivity{
+		"binary":                      {},
+		"latin1_bin":                  {},
+		"utf8mb4_bin":                 {},
+		"latin1_nopad_bin":            {},
+		"utf8mb4_nopad_bin":           {},
+		"utf8mb4_general_nopad_ci":    {caseInsensitive: true, accentInsensitive: true},
+		"utf8mb3_general_nopad_ci":    {caseInsensitive: true, accentInsensitive: true},
+		"latin1_swedish_nopad_ci":     {caseInsensitive: true, accentInsensitive: true},
+		"utf8mb4_uca1400_nopad_ai_ci": {caseInsensitive: true, accentInsensitive: true},
+		"utf8mb4_0900_as_cs":          {},
+		"utf8mb4_de_pb_0900_as_cs":    {},
+		"utf8mb4_0900_as_ci":          {caseInsensitive: true},
+		"utf8mb4_thai_520_w2":         {caseInsensitive: true},
+	} {
+		assert.Equal(t, want, sensitivityByName(name), name)
+	}
+}
+
+func TestCollationsForCharset(t *testing.T) {
+	env := MySQL8()
+
+	utf8mb3 := env.CollationsForCharset("utf8mb3")
+	assert.Equal(t, utf8mb3, env.CollationsForCharset("utf8"))
+	assert.IsIncreasing(t, utf8mb3)
+	assert.Contains(t, utf8mb3, env.LookupByName("utf8mb3_general_ci"))
+	assert.Contains(t, utf8mb3, env.LookupByName("utf8mb3_bin"))
+	assert.NotContains(t, utf8mb3, env.LookupByName("utf8mb4_general_ci"))
+
+	utf8mb4 := env.CollationsForCharset("utf8mb4")
+	assert.Contains(t, utf8mb4, env.LookupByName("utf8mb4_0900_ai_ci"))
+	assert.Contains(t, utf8mb4, env.LookupByName("utf8mb4_bin"))
+	for _, id := range utf8mb4 {
+		assert.Equal(t, "utf8mb4", env.LookupCharsetName(id))
+	}
+
+	assert.Equal(t, []ID{CollationBinaryID}, env.CollationsForCharset("binary"))
+	assert.Empty(t, env.CollationsForCharset("unknown"))
+}
+
+func TestUnsupportedCollations(t *testing.T) {
+	env := MySQL8()
+
+	unsupported := env.UnsupportedCollations()
+	assert.NotEmpty(t, unsupported)
+	assert.IsIncreasing(t, unsupported)
+	assert.Contains(t, unsupported, "latin2_czech_cs")
+	assert.NotContains(t, unsupported, "utf8mb4_0900_ai_ci")
+	for _, name := range unsupported {
+		assert.True(t, env.IsUnsupported(name), name)
+		id, ok := env.LookupID(name)
+		assert.False(t, ok, name)
+		assert.NotEqual(t, Unknown, id, name)
+	}
+
+	assert.True(t, env.IsUnsupported(" LATIN2_CZECH_CS\n"))
+	assert.False(t, env.IsUnsupported("utf8mb4_0900_ai_ci"))
+	assert.False(t, env.IsUnsupported("unknown"))
+}
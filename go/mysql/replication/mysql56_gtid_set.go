@@ -688,3 +688,27 @@ func Subtract(lhs, rhs string) (string, error) {
 	diffSet := lhsSet.Difference(rhsSet)
 	return diffSet.String(), nil
 }
+
+// GTIDDiff returns the transactions that are only in a, and the ones that are
+// only in b. An empty position is treated as an empty set. An error is returned
+// if either position isn't a MySQL 5.6 GTID based position, as they can't be
+// compared.
+func GTIDDiff(a, b Position) (onlyA, onlyB GTIDSet, err error) {
+	setA, ok := mysql56GTIDSetOrEmpty(a)
+	if !ok {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot diff position %v: not a MySQL 5.6 GTID position", a)
+	}
+	setB, ok := mysql56GTIDSetOrEmpty(b)
+	if !ok {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot diff position %v: not a MySQL 5.6 GTID position", b)
+	}
+	return setA.Difference(setB), setB.Difference(setA), nil
+}
+
+func mysql56GTIDSetOrEmpty(pos Position) (Mysql56GTIDSet, bool) {
+	if pos.GTIDSet == nil {
+		return Mysql56GTIDSet{}, true
+	}
+	set, ok := pos.GTIDSet.(Mysql56GTIDSet)
+	return set, ok
+}
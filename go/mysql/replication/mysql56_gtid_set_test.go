@@ -704,3 +704,78 @@ func BenchmarkMySQL56GTIDParsing(b *testing.B) {
 		}
 	}
 }
+
+func TestGTIDDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		onlyA string
+		onlyB string
+	}{
+		{
+			name:  "overlapping sets",
+			a:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			b:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:5-10",
+			onlyA: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-4",
+			onlyB: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:9-10",
+		}, {
+			name:  "disjoint sets",
+			a:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			b:     "8bc65cca-3fe4-11ed-bbfb-091034d48b3e:1-3",
+			onlyA: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			onlyB: "8bc65cca-3fe4-11ed-bbfb-091034d48b3e:1-3",
+		}, {
+			name:  "one side is a superset",
+			a:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8,8bc65cca-3fe4-11ed-bbfb-091034d48b3e:1",
+			b:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			onlyA: "8bc65cca-3fe4-11ed-bbfb-091034d48b3e:1",
+			onlyB: "",
+		}, {
+			name:  "equal sets",
+			a:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			b:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			onlyA: "",
+			onlyB: "",
+		}, {
+			name:  "empty position",
+			a:     "",
+			b:     "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+			onlyA: "",
+			onlyB: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b Position
+			if tt.a != "" {
+				a = MustParsePosition(Mysql56FlavorID, tt.a)
+			}
+			if tt.b != "" {
+				b = MustParsePosition(Mysql56FlavorID, tt.b)
+			}
+			onlyA, onlyB, err := GTIDDiff(a, b)
+			require.NoError(t, err)
+			require.NotNil(t, onlyA)
+			require.NotNil(t, onlyB)
+			assert.Equal(t, tt.onlyA, onlyA.String())
+			assert.Equal(t, tt.onlyB, onlyB.String())
+		})
+	}
+
+	t.Run("non GTID position", func(t *testing.T) {
+		a := MustParsePosition(Mysql56FlavorID, "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8")
+		b := Position{GTIDSet: FilePosGTID{File: "binlog.000001", Pos: 4}}
+		onlyA, onlyB, err := GTIDDiff(a, b)
+		assert.EqualError(t, err, "cannot diff position binlog.000001:4: not a MySQL 5.6 GTID position")
+		assert.Nil(t, onlyA)
+		assert.Nil(t, onlyB)
+	})
+
+	t.Run("MariaDB position", func(t *testing.T) {
+		a := MustParsePosition(MariadbFlavorID, "0-1-10")
+		b := MustParsePosition(Mysql56FlavorID, "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-8")
+		_, _, err := GTIDDiff(a, b)
+		assert.EqualError(t, err, "cannot diff position 0-1-10: not a MySQL 5.6 GTID position")
+	})
+}
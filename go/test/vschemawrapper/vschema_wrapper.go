@@ -49,6 +49,7 @@ type VSchemaWrapper struct {
 	ForeignKeyChecksState *bool
 	Version               plancontext.PlannerVersion
 	EnableViews           bool
+	PreferVTGateEval      bool
 	TestBuilder           func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
 	Env                   *vtenv.Environment
 }
@@ -342,3 +343,7 @@ func (vw *VSchemaWrapper) FindRoutedShard(keyspace, shard string) (string, error
 func (vw *VSchemaWrapper) IsViewsEnabled() bool {
 	return vw.EnableViews
 }
+
+func (vw *VSchemaWrapper) PreferVTGateEvaluation() bool {
+	return vw.PreferVTGateEval
+}
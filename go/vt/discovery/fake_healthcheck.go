@@ -82,8 +82,10 @@ func (fhc *FakeHealthCheck) GetHealthyTabletStats(target *querypb.Target) []*Tab
 	result := make([]*TabletHealth, 0)
 	fhc.mu.Lock()
 	defer fhc.mu.Unlock()
+	// like HealthCheckImpl, ignore the cell of the target.
+	key := KeyFromTarget(target)
 	for _, item := range fhc.items {
-		if proto.Equal(item.ts.Target, target) && item.ts.Serving && item.ts.LastError == nil {
+		if KeyFromTarget(item.ts.Target) == key && item.ts.Serving && item.ts.LastError == nil {
 			result = append(result, item.ts)
 		}
 	}
@@ -125,6 +125,40 @@ func (d DestinationExactKeyRange) String() string {
 	return "DestinationExactKeyRange(" + KeyRangeString(d.KeyRange) + ")"
 }
 
+//
+// DestinationExactKeyRanges
+//
+
+// DestinationExactKeyRanges is the destination for multiple KeyRanges.
+// Each KeyRange must map exactly to one or more shards, and cannot
+// start or end in the middle of a shard.
+// It implements the Destination interface.
+type DestinationExactKeyRanges []*topodatapb.KeyRange
+
+// Resolve is part of the Destination interface.
+func (d DestinationExactKeyRanges) Resolve(allShards []*topodatapb.ShardReference, addShard func(shard string) error) error {
+	for _, kr := range d {
+		if err := processExactKeyRange(allShards, kr, addShard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String is part of the Destination interface.
+func (d DestinationExactKeyRanges) String() string {
+	var buffer strings.Builder
+	buffer.WriteString("DestinationExactKeyRanges(")
+	for i, kr := range d {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(KeyRangeString(kr))
+	}
+	buffer.WriteByte(')')
+	return buffer.String()
+}
+
 func processExactKeyRange(allShards []*topodatapb.ShardReference, kr *topodatapb.KeyRange, addShard func(shard string) error) error {
 	sort.SliceStable(allShards, func(i, j int) bool {
 		return KeyRangeLess(allShards[i].GetKeyRange(), allShards[j].GetKeyRange())
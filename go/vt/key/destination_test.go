@@ -154,6 +154,32 @@ func TestDestinationExactKeyRange(t *testing.T) {
 	}
 }
 
+func TestDestinationExactKeyRanges(t *testing.T) {
+	allShards := initShardArray(t, "-20-40-60-80-a0-c0-e0-")
+	krs, err := ParseShardingSpec("20-40-60")
+	require.NoError(t, err)
+	krs80, err := ParseShardingSpec("80-")
+	require.NoError(t, err)
+
+	var gotShards []string
+	err = DestinationExactKeyRanges{krs[0], krs80[0]}.Resolve(allShards, func(shard string) error {
+		gotShards = append(gotShards, shard)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20-40", "80-a0", "a0-c0", "c0-e0", "e0-"}, gotShards)
+
+	// every keyrange must exactly match shards
+	krs, err = ParseShardingSpec("20-40")
+	require.NoError(t, err)
+	krs18, err := ParseShardingSpec("10-18")
+	require.NoError(t, err)
+	err = DestinationExactKeyRanges{krs[0], krs18[0]}.Resolve(allShards, func(shard string) error {
+		return nil
+	})
+	assert.ErrorContains(t, err, "keyrange 10-18 does not exactly match shards")
+}
+
 func TestDestinationKeyRange(t *testing.T) {
 	var testCases = []struct {
 		keyspace string
@@ -262,6 +288,7 @@ func TestDestinationsString(t *testing.T) {
 		DestinationShard("2"),
 		DestinationShards{"2", "3"},
 		DestinationExactKeyRange{KeyRange: kr2040},
+		DestinationExactKeyRanges{kr2040, kr2040},
 		DestinationKeyRange{KeyRange: kr2040},
 		DestinationKeyspaceID{1, 2},
 		DestinationKeyspaceIDs{
@@ -272,7 +299,7 @@ func TestDestinationsString(t *testing.T) {
 		DestinationNone{},
 		DestinationAnyShard{},
 	})
-	want := "Destinations:DestinationShard(2),DestinationShards(2,3),DestinationExactKeyRange(20-40),DestinationKeyRange(20-40),DestinationKeyspaceID(0102),DestinationKeyspaceIDs(0102,0203),DestinationAllShards(),DestinationNone(),DestinationAnyShard()"
+	want := "Destinations:DestinationShard(2),DestinationShards(2,3),DestinationExactKeyRange(20-40),DestinationExactKeyRanges(20-40,20-40),DestinationKeyRange(20-40),DestinationKeyspaceID(0102),DestinationKeyspaceIDs(0102,0203),DestinationAllShards(),DestinationNone(),DestinationAnyShard()"
 	assert.Equal(t, want, got)
 }
 
@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo/events"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -81,7 +82,7 @@ func (ki *KeyspaceInfo) SetKeyspaceName(name string) {
 // ValidateKeyspaceName checks if the provided name is a valid name for a
 // keyspace.
 func ValidateKeyspaceName(name string) error {
-	return validateObjectName(name)
+	return topoproto.ValidateKeyspaceName(name)
 }
 
 // CreateKeyspace wraps the underlying Conn.Create
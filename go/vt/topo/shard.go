@@ -86,7 +86,7 @@ func IsShardUsingRangeBasedSharding(shard string) bool {
 // ValidateShardName takes a shard name and sanitizes it, and also returns
 // the KeyRange.
 func ValidateShardName(shard string) (string, *topodatapb.KeyRange, error) {
-	if err := validateObjectName(shard); err != nil {
+	if err := topoproto.ValidateObjectName(shard); err != nil {
 		return "", nil, err
 	}
 
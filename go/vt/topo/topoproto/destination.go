@@ -29,11 +29,17 @@ import (
 
 // ParseDestination parses the string representation of a Destination
 // of the form keyspace:shard@tablet_type. You can use a / instead of a :.
+// The shard can also be given as keyspace[range]@tablet_type, with a
+// comma-separated list of ranges such as ks[10-20,40-60] to target several.
+// Likewise, ks/[-80,80-] targets a list of shards.
+// A preferred cell, as in ks@replica|zone1, is ignored: use SplitTargetCell
+// to read it.
 func ParseDestination(targetString string, defaultTabletType topodatapb.TabletType) (string, topodatapb.TabletType, key.Destination, error) {
 	var dest key.Destination
 	var keyspace string
 	tabletType := defaultTabletType
 
+	targetString, _ = SplitTargetCell(targetString)
 	last := strings.LastIndexAny(targetString, "@")
 	if last != -1 {
 		// No need to check the error. UNKNOWN will be returned on
@@ -43,7 +49,22 @@ func ParseDestination(targetString string, defaultTabletType topodatapb.TabletTy
 	}
 	last = strings.LastIndexAny(targetString, "/:")
 	if last != -1 {
-		dest = key.DestinationShard(targetString[last+1:])
+		shard := targetString[last+1:]
+		if strings.HasPrefix(shard, "[") {
+			// Parse as a list of shards
+			if !strings.HasSuffix(shard, "]") {
+				return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid shard list provided. Couldn't find list end ']'")
+			}
+			shards := strings.Split(shard[1:len(shard)-1], ",")
+			for _, s := range shards {
+				if s == "" {
+					return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty shard in shard list %s", shard)
+				}
+			}
+			dest = key.DestinationShards(shards)
+		} else {
+			dest = key.DestinationShard(shard)
+		}
 		targetString = targetString[:last]
 	}
 	// Try to parse it as a keyspace id or range
@@ -54,16 +75,30 @@ func ParseDestination(targetString string, defaultTabletType topodatapb.TabletTy
 			return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid key range provided. Couldn't find range end ']'")
 		}
 		rangeString := targetString[last+1 : rangeEnd]
-		if strings.Contains(rangeString, "-") {
+		if strings.Contains(rangeString, ",") {
+			// Parse as a list of ranges
+			var keyRanges key.DestinationExactKeyRanges
+			for _, spec := range strings.Split(rangeString, ",") {
+				if spec == "" {
+					return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty keyrange in keyrange list %s", rangeString)
+				}
+				if !strings.Contains(spec, "-") {
+					return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected keyrange %s in keyrange list %s", spec, rangeString)
+				}
+				keyRange, err := parseExactKeyRange(spec)
+				if err != nil {
+					return keyspace, tabletType, dest, err
+				}
+				keyRanges = append(keyRanges, keyRange)
+			}
+			dest = keyRanges
+		} else if strings.Contains(rangeString, "-") {
 			// Parse as range
-			keyRange, err := key.ParseShardingSpec(rangeString)
+			keyRange, err := parseExactKeyRange(rangeString)
 			if err != nil {
 				return keyspace, tabletType, dest, err
 			}
-			if len(keyRange) != 1 {
-				return keyspace, tabletType, dest, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "single keyrange expected in %s", rangeString)
-			}
-			dest = key.DestinationExactKeyRange{KeyRange: keyRange[0]}
+			dest = key.DestinationExactKeyRange{KeyRange: keyRange}
 		} else {
 			// Parse as keyspace id
 			destBytes, err := hex.DecodeString(rangeString)
@@ -77,3 +112,117 @@ func ParseDestination(targetString string, defaultTabletType topodatapb.TabletTy
 	keyspace = targetString
 	return keyspace, tabletType, dest, nil
 }
+
+// targetCellSeparator separates the preferred cell from the rest of a target
+// string.
+const targetCellSeparator = "|"
+
+// SplitTargetCell splits a target string of the form target|cell, such as
+// ks@replica|zone1, into the target and the cell whose tablets its queries
+// should prefer. The cell is empty if the target string does not name one.
+func SplitTargetCell(targetString string) (target string, cell string) {
+	target, cell, _ = strings.Cut(targetString, targetCellSeparator)
+	return target, cell
+}
+
+// TargetWithCell returns target with its preferred cell set to cell, replacing
+// the one it may already have. An empty cell removes the preferred cell.
+func TargetWithCell(targetString, cell string) string {
+	target, _ := SplitTargetCell(targetString)
+	if cell == "" {
+		return target
+	}
+	return target + targetCellSeparator + cell
+}
+
+// DestinationToString is the inverse of ParseDestination: it returns the
+// canonical target string for the keyspace, tablet type and destination.
+// A nil destination targets the keyspace as a whole, and an UNKNOWN tablet
+// type is left out. Destinations that cannot be expressed in a target string,
+// or would be parsed back as a different destination, return an error: this
+// includes empty shard and keyrange lists, and keyrange lists with a single
+// keyrange, which parse back as a DestinationExactKeyRange.
+func DestinationToString(keyspace string, tabletType topodatapb.TabletType, dest key.Destination) (string, error) {
+	var target strings.Builder
+	target.WriteString(keyspace)
+	switch dest := dest.(type) {
+	case nil:
+	case key.DestinationShard:
+		target.WriteString(":" + string(dest))
+	case key.DestinationShards:
+		if len(dest) == 0 {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty shard list cannot be expressed as a target string")
+		}
+		target.WriteString(":[" + strings.Join(dest, ",") + "]")
+	case key.DestinationExactKeyRange:
+		target.WriteString("[" + key.KeyRangeString(dest.KeyRange) + "]")
+	case key.DestinationExactKeyRanges:
+		if len(dest) < 2 {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyrange list %v must have at least two keyranges to be expressed as a target string", dest)
+		}
+		keyRanges := make([]string, 0, len(dest))
+		for _, kr := range dest {
+			keyRanges = append(keyRanges, key.KeyRangeString(kr))
+		}
+		target.WriteString("[" + strings.Join(keyRanges, ",") + "]")
+	case key.DestinationKeyspaceID:
+		target.WriteString("[" + hex.EncodeToString(dest) + "]")
+	default:
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "destination %v cannot be expressed as a target string", dest)
+	}
+	if tabletType != topodatapb.TabletType_UNKNOWN {
+		target.WriteString("@" + TabletTypeLString(tabletType))
+	}
+	return target.String(), nil
+}
+
+// parseExactKeyRange parses spec as a single keyrange.
+func parseExactKeyRange(spec string) (*topodatapb.KeyRange, error) {
+	keyRange, err := key.ParseShardingSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyRange) != 1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "single keyrange expected in %s", spec)
+	}
+	return keyRange[0], nil
+}
+
+// SplitTarget splits a target string of the form keyspace[range]:shard@tablet_type into its
+// raw textual components, without interpreting them. destExpr contains the destination as it
+// appears in the target string, including its `[...]`, `:...` or `/...` delimiters, and
+// tabletType is the text following the `@`. Joining the three parts back together, with an `@`
+// before a non-empty tablet type, results in the original target string.
+func SplitTarget(target string) (keyspace, destExpr, tabletType string, err error) {
+	if last := strings.LastIndexAny(target, "@"); last != -1 {
+		tabletType = target[last+1:]
+		target = target[:last]
+	}
+	end := len(target)
+	if last := strings.LastIndexAny(target, "/:"); last != -1 {
+		end = last
+	}
+	if last := strings.LastIndexAny(target[:end], "["); last != -1 {
+		if !strings.Contains(target[last:end], "]") {
+			return "", "", "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid key range provided. Couldn't find range end ']'")
+		}
+		end = last
+	}
+	return target[:end], target[end:], tabletType, nil
+}
+
+// ParseDestinationStrict is like ParseDestination, but it also rejects target
+// strings whose keyspace name contains characters that are not allowed in a
+// keyspace name. An empty keyspace is still accepted.
+func ParseDestinationStrict(targetString string, defaultTabletType topodatapb.TabletType) (string, topodatapb.TabletType, key.Destination, error) {
+	keyspace, tabletType, dest, err := ParseDestination(targetString, defaultTabletType)
+	if err != nil {
+		return keyspace, tabletType, dest, err
+	}
+	if keyspace != "" {
+		if err := ValidateKeyspaceName(keyspace); err != nil {
+			return keyspace, tabletType, dest, vterrors.Wrapf(err, "invalid target string %q", targetString)
+		}
+	}
+	return keyspace, tabletType, dest, nil
+}
@@ -21,6 +21,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/vt/key"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -29,6 +32,8 @@ import (
 func TestParseDestination(t *testing.T) {
 	tenHexBytes, _ := hex.DecodeString("10")
 	twentyHexBytes, _ := hex.DecodeString("20")
+	fortyHexBytes, _ := hex.DecodeString("40")
+	sixtyHexBytes, _ := hex.DecodeString("60")
 
 	testcases := []struct {
 		targetString string
@@ -40,6 +45,27 @@ func TestParseDestination(t *testing.T) {
 		keyspace:     "ks",
 		tabletType:   topodatapb.TabletType_PRIMARY,
 		dest:         key.DestinationExactKeyRange{KeyRange: &topodatapb.KeyRange{Start: tenHexBytes, End: twentyHexBytes}},
+	}, {
+		targetString: "ks:-80@replica|zone1",
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+		dest:         key.DestinationShard("-80"),
+	}, {
+		targetString: "ks[10-20,40-60]@primary",
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		dest: key.DestinationExactKeyRanges{
+			{Start: tenHexBytes, End: twentyHexBytes},
+			{Start: fortyHexBytes, End: sixtyHexBytes},
+		},
+	}, {
+		targetString: "ks[-20,60-]@replica",
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+		dest: key.DestinationExactKeyRanges{
+			{End: twentyHexBytes},
+			{Start: sixtyHexBytes},
+		},
 	}, {
 		targetString: "ks[-]@primary",
 		keyspace:     "ks",
@@ -87,6 +113,21 @@ func TestParseDestination(t *testing.T) {
 		keyspace:     "ks",
 		dest:         key.DestinationShard("-80"),
 		tabletType:   topodatapb.TabletType_PRIMARY,
+	}, {
+		targetString: "ks/[-80,80-]",
+		keyspace:     "ks",
+		dest:         key.DestinationShards{"-80", "80-"},
+		tabletType:   topodatapb.TabletType_PRIMARY,
+	}, {
+		targetString: "ks:[-80,80-]@replica",
+		keyspace:     "ks",
+		dest:         key.DestinationShards{"-80", "80-"},
+		tabletType:   topodatapb.TabletType_REPLICA,
+	}, {
+		targetString: "ks/[0]@primary",
+		keyspace:     "ks",
+		dest:         key.DestinationShards{"0"},
+		tabletType:   topodatapb.TabletType_PRIMARY,
 	}}
 
 	for _, tcase := range testcases {
@@ -115,9 +156,270 @@ func TestParseDestination(t *testing.T) {
 		t.Errorf("executorExec error: %v, want %s", err, want)
 	}
 
+	_, _, _, err = ParseDestination("ks[10-20,]@primary", topodatapb.TabletType_PRIMARY)
+	want = "empty keyrange in keyrange list 10-20,"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
+	_, _, _, err = ParseDestination("ks[10-20,,40-60]@primary", topodatapb.TabletType_PRIMARY)
+	want = "empty keyrange in keyrange list 10-20,,40-60"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
+	_, _, _, err = ParseDestination("ks[10-20,deadbeef]@primary", topodatapb.TabletType_PRIMARY)
+	want = "expected keyrange deadbeef in keyrange list 10-20,deadbeef"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
+	_, _, _, err = ParseDestination("ks[10-20,40-60-80]@primary", topodatapb.TabletType_PRIMARY)
+	want = "single keyrange expected in 40-60-80"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
+	_, _, _, err = ParseDestination("ks/[-80,,80-]@primary", topodatapb.TabletType_PRIMARY)
+	want = "empty shard in shard list [-80,,80-]"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
+	_, _, _, err = ParseDestination("ks/[-80,80-@primary", topodatapb.TabletType_PRIMARY)
+	want = "invalid shard list provided. Couldn't find list end ']'"
+	if err == nil || err.Error() != want {
+		t.Errorf("executorExec error: %v, want %s", err, want)
+	}
+
 	_, _, _, err = ParseDestination("ks[qrnqorrs]@primary", topodatapb.TabletType_PRIMARY)
 	want = "expected valid hex in keyspace id qrnqorrs"
 	if err == nil || err.Error() != want {
 		t.Errorf("executorExec error: %v, want %s", err, want)
 	}
 }
+
+func TestSplitTargetCell(t *testing.T) {
+	testcases := []struct {
+		targetString string
+		target       string
+		cell         string
+	}{{
+		targetString: "ks@replica|zone1",
+		target:       "ks@replica",
+		cell:         "zone1",
+	}, {
+		targetString: "ks:-80|zone1",
+		target:       "ks:-80",
+		cell:         "zone1",
+	}, {
+		targetString: "ks@replica",
+		target:       "ks@replica",
+	}, {
+		targetString: "|zone1",
+		cell:         "zone1",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.targetString, func(t *testing.T) {
+			target, cell := SplitTargetCell(tcase.targetString)
+			assert.Equal(t, tcase.target, target)
+			assert.Equal(t, tcase.cell, cell)
+			assert.Equal(t, tcase.targetString, TargetWithCell(target, cell))
+		})
+	}
+
+	assert.Equal(t, "ks@replica|zone2", TargetWithCell("ks@replica|zone1", "zone2"))
+	assert.Equal(t, "ks@replica", TargetWithCell("ks@replica|zone1", ""))
+}
+
+func TestParseDestinationStrict(t *testing.T) {
+	keyspace, tabletType, dest, err := ParseDestinationStrict("valid_ks-1:-80@replica", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.Equal(t, "valid_ks-1", keyspace)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, tabletType)
+	assert.Equal(t, key.DestinationShard("-80"), dest)
+
+	keyspace, _, _, err = ParseDestinationStrict("@primary", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.Empty(t, keyspace)
+
+	_, _, _, err = ParseDestinationStrict("my ks@primary", topodatapb.TabletType_PRIMARY)
+	assert.EqualError(t, err, `invalid target string "my ks@primary": invalid character   in name my ks`)
+
+	_, _, _, err = ParseDestinationStrict("ks/x:-80@primary", topodatapb.TabletType_PRIMARY)
+	assert.EqualError(t, err, `invalid target string "ks/x:-80@primary": invalid character / in name ks/x`)
+
+	// the lenient parser still accepts these keyspace names
+	keyspace, _, _, err = ParseDestination("my ks@primary", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.Equal(t, "my ks", keyspace)
+}
+
+func TestDestinationToString(t *testing.T) {
+	testcases := []struct {
+		keyspace     string
+		tabletType   topodatapb.TabletType
+		dest         key.Destination
+		targetString string
+	}{{
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		dest:         key.DestinationShard("-80"),
+		targetString: "ks:-80@primary",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+		dest:         key.DestinationShards{"-80", "80-"},
+		targetString: "ks:[-80,80-]@replica",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+		dest:         key.DestinationShards{"-80"},
+		targetString: "ks:[-80]@replica",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		dest:         key.DestinationExactKeyRange{KeyRange: &topodatapb.KeyRange{Start: []byte{0x10}, End: []byte{0x20}}},
+		targetString: "ks[10-20]@primary",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		dest:         key.DestinationExactKeyRange{KeyRange: &topodatapb.KeyRange{}},
+		targetString: "ks[-]@primary",
+	}, {
+		keyspace:   "ks",
+		tabletType: topodatapb.TabletType_RDONLY,
+		dest: key.DestinationExactKeyRanges{
+			{End: []byte{0x20}},
+			{Start: []byte{0x40}, End: []byte{0x60}},
+		},
+		targetString: "ks[-20,40-60]@rdonly",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		dest:         key.DestinationKeyspaceID([]byte("\xde\xad\xbe\xef")),
+		targetString: "ks[deadbeef]@primary",
+	}, {
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+		targetString: "ks@replica",
+	}, {
+		tabletType:   topodatapb.TabletType_PRIMARY,
+		targetString: "@primary",
+	}, {
+		keyspace:     "ks",
+		dest:         key.DestinationShard("0"),
+		targetString: "ks:0",
+	}}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.targetString, func(t *testing.T) {
+			targetString, err := DestinationToString(tcase.keyspace, tcase.tabletType, tcase.dest)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.targetString, targetString)
+
+			keyspace, tabletType, dest, err := ParseDestination(targetString, topodatapb.TabletType_UNKNOWN)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.keyspace, keyspace)
+			assert.Equal(t, tcase.tabletType, tabletType)
+			assert.Equal(t, tcase.dest, dest)
+		})
+	}
+
+	errcases := []struct {
+		dest key.Destination
+		err  string
+	}{{
+		dest: key.DestinationAllShards{},
+		err:  "destination DestinationAllShards() cannot be expressed as a target string",
+	}, {
+		dest: key.DestinationShards{},
+		err:  "empty shard list cannot be expressed as a target string",
+	}, {
+		dest: key.DestinationExactKeyRanges{},
+		err:  "keyrange list DestinationExactKeyRanges() must have at least two keyranges to be expressed as a target string",
+	}, {
+		dest: key.DestinationExactKeyRanges{{Start: []byte{0x10}, End: []byte{0x20}}},
+		err:  "keyrange list DestinationExactKeyRanges(10-20) must have at least two keyranges to be expressed as a target string",
+	}}
+	for _, tcase := range errcases {
+		_, err := DestinationToString("ks", topodatapb.TabletType_PRIMARY, tcase.dest)
+		assert.EqualError(t, err, tcase.err)
+	}
+}
+
+func TestSplitTarget(t *testing.T) {
+	testcases := []struct {
+		targetString string
+		keyspace     string
+		destExpr     string
+		tabletType   string
+	}{{
+		targetString: "ks[10-20]@primary",
+		keyspace:     "ks",
+		destExpr:     "[10-20]",
+		tabletType:   "primary",
+	}, {
+		targetString: "ks[-]@primary",
+		keyspace:     "ks",
+		destExpr:     "[-]",
+		tabletType:   "primary",
+	}, {
+		targetString: "ks[deadbeef]@primary",
+		keyspace:     "ks",
+		destExpr:     "[deadbeef]",
+		tabletType:   "primary",
+	}, {
+		targetString: "ks[10-]@primary",
+		keyspace:     "ks",
+		destExpr:     "[10-]",
+		tabletType:   "primary",
+	}, {
+		targetString: "ks[-20]@primary",
+		keyspace:     "ks",
+		destExpr:     "[-20]",
+		tabletType:   "primary",
+	}, {
+		targetString: "ks:-80@primary",
+		keyspace:     "ks",
+		destExpr:     ":-80",
+		tabletType:   "primary",
+	}, {
+		targetString: ":-80@primary",
+		keyspace:     "",
+		destExpr:     ":-80",
+		tabletType:   "primary",
+	}, {
+		targetString: "@primary",
+		tabletType:   "primary",
+	}, {
+		targetString: "@replica",
+		tabletType:   "replica",
+	}, {
+		targetString: "ks",
+		keyspace:     "ks",
+	}, {
+		targetString: "ks/-80",
+		keyspace:     "ks",
+		destExpr:     "/-80",
+	}, {
+		// the components are not interpreted
+		targetString: "ks[qrnqorrs]@unknown",
+		keyspace:     "ks",
+		destExpr:     "[qrnqorrs]",
+		tabletType:   "unknown",
+	}}
+
+	for _, tcase := range testcases {
+		t.Run(tcase.targetString, func(t *testing.T) {
+			keyspace, destExpr, tabletType, err := SplitTarget(tcase.targetString)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.keyspace, keyspace)
+			assert.Equal(t, tcase.destExpr, destExpr)
+			assert.Equal(t, tcase.tabletType, tabletType)
+		})
+	}
+
+	_, _, _, err := SplitTarget("ks[10-20@primary")
+	assert.EqualError(t, err, "invalid key range provided. Couldn't find range end ']'")
+}
@@ -1,4 +1,20 @@
-package topo
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topoproto
 
 import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -10,7 +26,7 @@ import (
 // and must match specific constraints.
 // They are only allowed to use ASCII letters or digits, - and _.
 // No spaces or special characters are allowed.
-func validateObjectName(name string) error {
+func ValidateObjectName(name string) error {
 	if name == "" {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty name")
 	}
@@ -32,3 +48,9 @@ func validateObjectName(name string) error {
 
 	return nil
 }
+
+// ValidateKeyspaceName checks if the provided name is a valid name for a
+// keyspace.
+func ValidateKeyspaceName(name string) error {
+	return ValidateObjectName(name)
+}
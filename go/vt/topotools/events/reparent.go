@@ -19,6 +19,8 @@ limitations under the License.
 package events
 
 import (
+	"time"
+
 	base "vitess.io/vitess/go/vt/events"
 	"vitess.io/vitess/go/vt/topo"
 
@@ -32,4 +34,75 @@ type Reparent struct {
 	ShardInfo              topo.ShardInfo
 	OldPrimary, NewPrimary *topodatapb.Tablet
 	ExternalID             string
+
+	// WaitReplicasTimeout is the budget an emergency reparent was given to wait
+	// on replicas, and ReplicaWaitTime is how much of it was actually spent
+	// waiting for relay logs to apply and for replicas to be repointed.
+	WaitReplicasTimeout time.Duration
+	ReplicaWaitTime     time.Duration
+
+	// Timings holds how long each step of an emergency reparent took, keyed by
+	// step: StopReplicationAndGetStatus, WaitForAllRelayLogsToApply,
+	// FindMostAdvanced, PromoteIntermediateSource, WaitForCatchUp and
+	// PromoteNewPrimary. A step is recorded even if it failed, and is missing
+	// if it was not reached or not needed.
+	Timings map[string]time.Duration
+
+	// SemiSyncAckers holds the aliases of the reachable tablets that can send
+	// semi-sync acks to the new primary, when its durability policy requires
+	// them for it to make forward progress.
+	SemiSyncAckers []string
+
+	// PromotionResult is the position returned by the new primary when it was
+	// promoted, and ReparentJournalPopulated is true once that position has
+	// been recorded in its reparent journal.
+	PromotionResult          string
+	ReparentJournalPopulated bool
+
+	// IgnoredReplicas maps the aliases of the tablets an emergency reparent
+	// skipped to the reason why, e.g. because they were explicitly ignored or
+	// because their type prevents them from being promoted.
+	IgnoredReplicas map[string]string
+
+	// SkippedReplicas holds the aliases of the tablets an emergency reparent
+	// did not point at the new primary because of their type, sorted.
+	SkippedReplicas []string
+
+	// ErrantGTIDs maps the aliases of the tablets an emergency reparent left
+	// out of the candidates for having errant GTIDs to their errant GTID set.
+	ErrantGTIDs map[string]string
+
+	// CandidatePositions maps the aliases of the tablets an emergency reparent
+	// found to be valid candidates, i.e. without errant GTIDs, to the encoded
+	// replication position it compared them on. The tablet that won is the
+	// NewPrimary, or the PlannedPrimary of a dry run.
+	CandidatePositions map[string]string
+
+	// PlannedPrimary is the tablet an emergency reparent run with DryRun would
+	// have promoted, and Candidates the valid candidates it chose from, most
+	// advanced first.
+	PlannedPrimary *topodatapb.Tablet
+	Candidates     []ReparentCandidate
+
+	// PredictedReplicas holds the aliases of the tablets a dry run expects to
+	// follow the PlannedPrimary, because they were reachable when their status
+	// was gathered, and PredictedFailedReplicas the ones it expects to fail to,
+	// because they were not. Both are sorted.
+	PredictedReplicas       []string
+	PredictedFailedReplicas []string
+
+	// NoOpReason explains why an emergency reparent run with BailIfHealthy
+	// did nothing. No other field is set when it is.
+	NoOpReason string
+
+	// Warnings holds the problems noticed during the reparent that did not
+	// prevent it from succeeding.
+	Warnings []string
+}
+
+// ReparentCandidate is a tablet considered for promotion by a reparent, along
+// with its replication position.
+type ReparentCandidate struct {
+	Alias    string
+	Position string
 }
@@ -0,0 +1,32 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/callerid"
+)
+
+// withApplicationName returns ctx with an effective caller ID whose component
+// is ApplicationName, unless it is not set or ctx already has one.
+func (c *conn) withApplicationName(ctx context.Context) context.Context {
+	if c.cfg.ApplicationName == "" || callerid.EffectiveCallerIDFromContext(ctx) != nil {
+		return ctx
+	}
+	return callerid.NewContext(ctx, callerid.NewEffectiveCallerID("", c.cfg.ApplicationName, ""), nil)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// connectionErrors are the messages of the gRPC errors returned when vtgate
+// cannot be reached, as opposed to the errors vtgate itself returns.
+var connectionErrors = []string{
+	"connection error",
+	"error reading from server",
+	"transport is closing",
+	"the client connection is closing",
+}
+
+// isConnectionError returns true if the given error means the connection to
+// vtgate is dead.
+func isConnectionError(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_CANCELED:
+	default:
+		return false
+	}
+	msg := err.Error()
+	for _, connErr := range connectionErrors {
+		if strings.Contains(msg, connErr) {
+			return true
+		}
+	}
+	return false
+}
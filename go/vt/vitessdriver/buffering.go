@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// BufferingDelayReader reports whether the last statement of a connection was
+// held in the vtgate buffer during a failover, and for how long. The
+// BufferingDelay function reads it from a *sql.Conn.
+type BufferingDelayReader interface {
+	// BufferingDelay returns how long vtgate buffered the last statement
+	// executed on the connection during a failover, or 0 if it was not
+	// buffered.
+	BufferingDelay() time.Duration
+}
+
+// BufferingDelay returns how long vtgate buffered the last statement executed
+// on the given connection during a failover, or 0 if it was not buffered. A
+// statement that succeeded after a non-zero delay was held up by a failover,
+// not by a slow query. The delay is also reported for a statement that failed
+// after it was buffered, for example because the failover did not finish in
+// time, in which case it can usually be retried.
+func BufferingDelay(ctx context.Context, c *sql.Conn) (time.Duration, error) {
+	var delay time.Duration
+	err := withConn(c, "reading the buffering delay", func(reader BufferingDelayReader) error {
+		delay = reader.BufferingDelay()
+		return nil
+	})
+	return delay, err
+}
+
+// BufferingDelay returns the delay vtgate reported on the session for the last
+// statement.
+func (c *conn) BufferingDelay() time.Duration {
+	return time.Duration(c.session.SessionPb().GetBufferingDelayMs()) * time.Millisecond
+}
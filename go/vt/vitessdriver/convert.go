@@ -19,6 +19,9 @@ package vitessdriver
 import (
 	"database/sql/driver"
 	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
 	"time"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -29,6 +32,27 @@ import (
 
 type converter struct {
 	location *time.Location
+	decimal  DecimalHandling
+	// bindPrefix is the PositionalBindPrefix, or empty for the default.
+	bindPrefix string
+}
+
+// defaultPositionalBindPrefix is the prefix of the names of positional bind
+// variables when PositionalBindPrefix is not set.
+const defaultPositionalBindPrefix = "v"
+
+// validBindPrefix matches the prefixes that make valid bind variable names
+// once followed by a number.
+var validBindPrefix = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// positionalBindVar returns the name of the bind variable of the i-th
+// positional argument, starting at 0.
+func (cv *converter) positionalBindVar(i int) string {
+	prefix := cv.bindPrefix
+	if prefix == "" {
+		prefix = defaultPositionalBindPrefix
+	}
+	return prefix + strconv.Itoa(i+1)
 }
 
 func (cv *converter) ToNative(v sqltypes.Value) (any, error) {
@@ -47,6 +71,8 @@ func (cv *converter) ToNative(v sqltypes.Value) (any, error) {
 		return datetimeToNative(v, cv.location)
 	case v.Type() == sqltypes.Date:
 		return dateToNative(v, cv.location)
+	case v.Type() == sqltypes.Decimal && cv.decimal != DecimalAsBytes:
+		return decimalToNative(v, cv.decimal)
 	case v.IsQuoted() || v.Type() == sqltypes.Bit || v.Type() == sqltypes.Decimal:
 		out, err = v.ToBytes()
 	case v.Type() == sqltypes.Expression:
@@ -83,7 +109,7 @@ func (cv *converter) buildBindVars(args []driver.Value) (map[string]*querypb.Bin
 		if err != nil {
 			return nil, err
 		}
-		bindVars[fmt.Sprintf("v%d", i+1)] = bv
+		bindVars[cv.positionalBindVar(i)] = bv
 	}
 	return bindVars, nil
 }
@@ -111,7 +137,7 @@ func (cv *converter) bindVarsFromNamedValues(args []driver.NamedValue) (map[stri
 			}
 		}
 		if v.Name == "" {
-			bindVars[fmt.Sprintf("v%d", i+1)] = bv
+			bindVars[cv.positionalBindVar(i)] = bv
 		} else {
 			if v.Name[0] == ':' || v.Name[0] == '@' {
 				bindVars[v.Name[1:]] = bv
@@ -125,6 +151,21 @@ func (cv *converter) bindVarsFromNamedValues(args []driver.NamedValue) (map[stri
 
 func newConverter(cfg *Configuration) (*converter, error) {
 	c := &converter{location: time.UTC}
+
+	switch cfg.DecimalHandling {
+	case DecimalAsBytes, DecimalAsString, DecimalAsFloat64, DecimalAsRat:
+		c.decimal = cfg.DecimalHandling
+	default:
+		return nil, fmt.Errorf("unknown DecimalHandling: %q", cfg.DecimalHandling)
+	}
+
+	if cfg.PositionalBindPrefix != "" {
+		if !validBindPrefix.MatchString(cfg.PositionalBindPrefix) {
+			return nil, fmt.Errorf("invalid PositionalBindPrefix: %q", cfg.PositionalBindPrefix)
+		}
+		c.bindPrefix = cfg.PositionalBindPrefix
+	}
+
 	if cfg.DefaultLocation == "" {
 		return c, nil
 	}
@@ -136,3 +177,33 @@ func newConverter(cfg *Configuration) (*converter, error) {
 	c.location = loc
 	return c, nil
 }
+
+// DecimalHandling controls the Go type DECIMAL values are converted to.
+type DecimalHandling string
+
+const (
+	// DecimalAsBytes converts DECIMAL values to []byte. This is the default.
+	DecimalAsBytes DecimalHandling = ""
+	// DecimalAsString converts DECIMAL values to string.
+	DecimalAsString DecimalHandling = "string"
+	// DecimalAsFloat64 converts DECIMAL values to float64, which may lose precision.
+	DecimalAsFloat64 DecimalHandling = "float64"
+	// DecimalAsRat converts DECIMAL values to *big.Rat, which is exact.
+	DecimalAsRat DecimalHandling = "rat"
+)
+
+func decimalToNative(v sqltypes.Value, handling DecimalHandling) (any, error) {
+	switch handling {
+	case DecimalAsString:
+		return v.ToString(), nil
+	case DecimalAsFloat64:
+		return v.ToFloat64()
+	case DecimalAsRat:
+		r, ok := new(big.Rat).SetString(v.RawStr())
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v cannot be converted to a *big.Rat", v)
+		}
+		return r, nil
+	}
+	return v.ToBytes()
+}
@@ -24,12 +24,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtgate/grpcvtgateconn"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 )
@@ -43,6 +49,7 @@ var (
 var (
 	_ interface {
 		driver.Connector
+		io.Closer
 	} = &connector{}
 
 	_ interface {
@@ -58,6 +65,9 @@ var (
 		driver.Pinger
 		driver.QueryerContext
 		driver.Tx
+		BatchExecer
+		Savepointer
+		WarningsReader
 	} = &conn{}
 
 	_ interface {
@@ -65,6 +75,20 @@ var (
 		driver.StmtExecContext
 		driver.StmtQueryContext
 	} = &stmt{}
+
+	_ interface {
+		driver.Rows
+		driver.RowsColumnTypeScanType
+		driver.RowsColumnTypeDatabaseTypeName
+		driver.RowsColumnTypeNullable
+	} = &rows{}
+
+	_ interface {
+		driver.Rows
+		driver.RowsColumnTypeScanType
+		driver.RowsColumnTypeDatabaseTypeName
+		driver.RowsColumnTypeNullable
+	} = &streamingRows{}
 )
 
 func init() {
@@ -111,6 +135,19 @@ func OpenWithConfiguration(c Configuration) (*sql.DB, error) {
 		vtgateconn.RegisterDialer(c.Protocol, grpcvtgateconn.Dial(c.GRPCDialOptions...))
 	}
 
+	// the callback cannot travel through the JSON name, so the connector is
+	// built here instead of by database/sql.
+	if c.OnSchemaVersionChange != nil {
+		if c.DriverName != "vitess" {
+			return nil, errors.New("OnSchemaVersionChange cannot be used with a custom DriverName")
+		}
+		connector, err := drv{}.newConnector(c)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(connector), nil
+	}
+
 	return sql.Open(c.DriverName, json)
 }
 
@@ -118,7 +155,8 @@ type drv struct{}
 
 // Open implements the database/sql/driver.Driver interface.
 //
-// For "name", the Vitess driver requires that a JSON object is passed in.
+// For "name", the Vitess driver accepts either a JSON object or a DSN as
+// parsed by ParseDSN.
 //
 // Instead of using this call and passing in a hand-crafted JSON string, it's
 // recommended to use the public Vitess helper functions like
@@ -129,6 +167,10 @@ type drv struct{}
 //
 //	{"protocol": "grpc", "address": "localhost:1111", "target": "@primary"}
 //
+// Example for a DSN:
+//
+//	vitess://localhost:1111/keyspace?target=@replica&timeout=30s
+//
 // For a description of the available fields, see the Configuration struct.
 func (d drv) Open(name string) (driver.Conn, error) {
 	conn, err := d.OpenConnector(name)
@@ -144,8 +186,15 @@ func (d drv) Open(name string) (driver.Conn, error) {
 // See the documentation of Open for details on the format of name.
 func (d drv) OpenConnector(name string) (driver.Connector, error) {
 	var cfg Configuration
-	if err := json.Unmarshal([]byte(name), &cfg); err != nil {
-		return nil, err
+	if isJSONName(name) {
+		if err := json.Unmarshal([]byte(name), &cfg); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if cfg, err = ParseDSN(name); err != nil {
+			return nil, err
+		}
 	}
 
 	cfg.setDefaults()
@@ -158,6 +207,8 @@ type connector struct {
 	drv     drv
 	cfg     Configuration
 	convert *converter
+
+	schemaVersion *schemaVersionWatcher
 }
 
 func (d drv) newConnector(cfg Configuration) (driver.Connector, error) {
@@ -165,12 +216,22 @@ func (d drv) newConnector(cfg Configuration) (driver.Connector, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.OnSchemaVersionChange != nil && cfg.SchemaVersionQuery == "" {
+		return nil, errors.New("OnSchemaVersionChange requires SchemaVersionQuery")
+	}
+	if cfg.ReadYourWrites && cfg.Streaming {
+		return nil, errors.New("ReadYourWrites cannot be used with Streaming")
+	}
 
-	return &connector{
+	c := &connector{
 		drv:     d,
 		cfg:     cfg,
 		convert: convert,
-	}, nil
+	}
+	if cfg.OnSchemaVersionChange != nil {
+		c.schemaVersion = newSchemaVersionWatcher(c)
+	}
+	return c, nil
 }
 
 // Connect implements the database/sql/driver.Connector interface.
@@ -190,6 +251,14 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 // Driver implements the database/sql/driver.Connector interface.
 func (c *connector) Driver() driver.Driver { return c.drv }
 
+// Close implements io.Closer. It is called by sql.DB.Close.
+func (c *connector) Close() error {
+	if c.schemaVersion != nil {
+		c.schemaVersion.Close()
+	}
+	return nil
+}
+
 // Configuration holds all Vitess driver settings.
 //
 // Fields with documented default values do not have to be set explicitly.
@@ -208,6 +277,15 @@ type Configuration struct {
 	// Target specifies the default target.
 	Target string
 
+	// Cell makes vtgate prefer the tablets of the given cell for the queries
+	// of the connection, for example to read from the replicas in the cell of
+	// the application. It is sent as part of the target, as in
+	// "ks@replica|cell", and replaces a cell already named in Target. The
+	// tablets of other cells are only used for the shards that have no
+	// healthy tablet in the cell.
+	// Default: none
+	Cell string
+
 	// Streaming is true when streaming RPCs are used.
 	// Recommended for large results.
 	// Default: false
@@ -235,6 +313,98 @@ type Configuration struct {
 	// SessionToken is a protobuf encoded vtgatepb.Session represented as base64, which
 	// can be used to distribute a transaction over the wire.
 	SessionToken string
+
+	// QueryLatencyMetrics enables the VitessDriverQueryLatency histogram, which
+	// records the latency of every query keyed by its fingerprint. The
+	// histogram is registered the first time it records a query, and is
+	// shared by all the connections of the process.
+	// Default: false
+	QueryLatencyMetrics bool
+
+	// QueryLatencyMaxFingerprints is the number of distinct fingerprints
+	// VitessDriverQueryLatency keeps. The queries of any other shape are
+	// recorded under the "other" fingerprint.
+	// Default: 100
+	QueryLatencyMaxFingerprints int
+
+	// TrackGTIDs asks vtgate to return the GTID of the writes executed on the
+	// connection. The last one seen can be retrieved with LastSeenGTID.
+	// Default: false
+	TrackGTIDs bool
+
+	// DecimalHandling is the Go type DECIMAL columns are converted to: []byte
+	// (""), string ("string"), float64 ("float64") or *big.Rat ("rat").
+	// Scanning a *big.Rat requires a **big.Rat or *any destination.
+	// Default: ""
+	DecimalHandling DecimalHandling
+
+	// SQLErrors converts the errors returned by vtgate into *sqlerror.SQLError,
+	// so that the MySQL error number and SQLSTATE can be checked with errors.As
+	// instead of matching the error message.
+	// Default: false
+	SQLErrors bool
+
+	// ImplicitTransactions makes multi-statement queries sent with Exec, such
+	// as "insert ...; insert ...", and batches of more than one statement sent
+	// with ExecBatch atomic, by running them in a transaction that is
+	// committed if all of them succeed and rolled back otherwise. The
+	// statements of a multi-statement Exec share its arguments. Single
+	// statements, and queries sent while a transaction is already open, are
+	// not affected.
+	// Default: false
+	ImplicitTransactions bool
+
+	// ApplicationName is sent to vtgate as the component of the effective
+	// caller ID of every query, unless the context of the query already has
+	// an effective caller ID, so that vtgate can attribute the queries to the
+	// application in its logs.
+	// Default: none
+	ApplicationName string
+
+	// SchemaVersionQuery is a query returning a single value that changes
+	// whenever the schema changes, e.g. the latest version of a migrations
+	// table. vtgate does not expose a schema version itself. It is run when
+	// the database is opened and then every SchemaVersionInterval.
+	// Default: none
+	SchemaVersionQuery string
+
+	// SchemaVersionInterval is how often SchemaVersionQuery is run.
+	// Default: 1m
+	SchemaVersionInterval time.Duration
+
+	// QueryTimeout is the timeout of every statement whose context has no
+	// deadline. An explicit context deadline always wins, whether it is
+	// shorter or longer. For streaming queries, the timeout covers reading
+	// all the rows, until the rows are closed.
+	// Default: none
+	QueryTimeout time.Duration
+
+	// ReadYourWrites routes the reads of a connection to the primary once it
+	// has executed a write, until the next commit or rollback, so that they
+	// observe the write even if Target is a replica. The keyspace and shard
+	// of the target are kept. It cannot be used with Streaming.
+	// Default: false
+	ReadYourWrites bool
+
+	// PositionalBindPrefix is the prefix of the names of the bind variables
+	// positional arguments are sent as, followed by their position starting
+	// at 1. It must be a valid identifier, e.g. "v" gives v1, v2, ...
+	// Default: "v"
+	PositionalBindPrefix string
+
+	// MaxRetries is how many times a statement that failed because vtgate
+	// could not be reached is retried, after dialing vtgate again. Only
+	// reads executed outside of a transaction are retried, since they are
+	// safe to run twice. Streaming queries are never retried.
+	// Default: 0
+	MaxRetries int
+
+	// OnSchemaVersionChange is called with the old and new values returned by
+	// SchemaVersionQuery when they differ, so that caches depending on the
+	// schema can be invalidated. It is only honored by OpenWithConfiguration.
+	//
+	// Default: none
+	OnSchemaVersionChange func(oldVersion, newVersion string) `json:"-"`
 }
 
 // toJSON converts Configuration to the JSON string which is required by the
@@ -265,11 +435,26 @@ type conn struct {
 	convert *converter
 	conn    *vtgateconn.VTGateConn
 	session *vtgateconn.VTGateSession
+
+	lastSeenGTID string
+
+	// pinnedFromTarget is the target the session was using before
+	// ReadYourWrites pinned it to the primary, if pinned is set.
+	pinned           bool
+	pinnedFromTarget string
+}
+
+// dialVTGate opens a new connection to vtgate with the dialer registered for
+// the protocol, which uses the GRPCDialOptions of the configuration. Both dial
+// and redial go through it, so that a connection replaced after an error is
+// set up like the original one.
+func (c *conn) dialVTGate(ctx context.Context) (*vtgateconn.VTGateConn, error) {
+	return vtgateconn.DialProtocol(ctx, c.cfg.Protocol, c.cfg.Address)
 }
 
 func (c *conn) dial(ctx context.Context) error {
 	var err error
-	c.conn, err = vtgateconn.DialProtocol(ctx, c.cfg.Protocol, c.cfg.Address)
+	c.conn, err = c.dialVTGate(ctx)
 	if err != nil {
 		return err
 	}
@@ -280,18 +465,38 @@ func (c *conn) dial(ctx context.Context) error {
 		}
 		c.session = c.conn.SessionFromPb(sessionFromToken)
 	} else {
-		c.session = c.conn.Session(c.cfg.Target, nil)
+		target := c.cfg.Target
+		if c.cfg.Cell != "" {
+			target = topoproto.TargetWithCell(target, c.cfg.Cell)
+		}
+		c.session = c.conn.Session(target, nil)
+	}
+	if c.cfg.TrackGTIDs {
+		c.enableGTIDTracking()
 	}
 	return nil
 }
 
+// Ping implements the database/sql/driver.Pinger interface. It returns
+// driver.ErrBadConn if vtgate cannot be reached, so that database/sql
+// discards the connection.
 func (c *conn) Ping(ctx context.Context) error {
 	if c.cfg.Streaming {
 		return errors.New("Ping not allowed for streaming connections")
 	}
 
-	_, err := c.ExecContext(ctx, "select 1", nil)
-	return err
+	ctx = c.withApplicationName(ctx)
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	// the error is checked before it is converted by sqlError.
+	if _, err := c.session.Execute(ctx, "select 1", nil); err != nil {
+		if ctx.Err() == nil && isConnectionError(err) {
+			return driver.ErrBadConn
+		}
+		return c.sqlError(err)
+	}
+	return nil
 }
 
 func (c *conn) Prepare(query string) (driver.Stmt, error) {
@@ -392,6 +597,24 @@ func SessionTokenFromTx(ctx context.Context, tx *sql.Tx) (string, error) {
 	return sessionToken, nil
 }
 
+// ShardSessionsFromTx returns the shard sessions of the tx, i.e. the shards
+// on which it has opened a transaction so far, as reported by vtgate.
+func ShardSessionsFromTx(ctx context.Context, tx *sql.Tx) ([]*vtgatepb.Session_ShardSession, error) {
+	var sessionToken string
+
+	err := tx.QueryRowContext(ctx, "vt_session_token").Scan(&sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := sessionTokenToSession(sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return session.ShardSessions, nil
+}
+
 func newSessionTokenRow(session *vtgatepb.Session, c *converter) (driver.Rows, error) {
 	sessionToken, err := sessionToSessionToken(session)
 	if err != nil {
@@ -481,7 +704,9 @@ func (c *conn) Rollback() error {
 }
 
 func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
-	ctx := context.TODO()
+	ctx := c.withApplicationName(context.TODO())
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
 
 	if c.cfg.Streaming {
 		return nil, errors.New("Exec not allowed for streaming connections")
@@ -490,15 +715,24 @@ func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
 	if err != nil {
 		return nil, err
 	}
+	if statements := c.implicitTransactionStatements(query); len(statements) > 1 {
+		return c.execStatements(ctx, statements, bindVars)
+	}
 
-	qr, err := c.session.Execute(ctx, query, bindVars)
+	defer c.recordLatency(query, time.Now())
+	qr, err := c.execute(ctx, query, bindVars)
 	if err != nil {
-		return nil, err
+		return nil, c.sqlError(err)
 	}
+	c.trackGTID(qr)
+	c.trackReadYourWrites(query)
 	return result{int64(qr.InsertID), int64(qr.RowsAffected)}, nil
 }
 
 func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx = c.withApplicationName(ctx)
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
 	if c.cfg.Streaming {
 		return nil, errors.New("Exec not allowed for streaming connections")
 	}
@@ -507,32 +741,38 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 	if err != nil {
 		return nil, err
 	}
-	qr, err := c.session.Execute(ctx, query, bv)
+	if statements := c.implicitTransactionStatements(query); len(statements) > 1 {
+		return c.execStatements(ctx, statements, bv)
+	}
+	defer c.recordLatency(query, time.Now())
+	qr, err := c.execute(ctx, query, bv)
 	if err != nil {
-		return nil, err
+		return nil, c.sqlError(err)
 	}
+	c.trackGTID(qr)
+	c.trackReadYourWrites(query)
 	return result{int64(qr.InsertID), int64(qr.RowsAffected)}, nil
 }
 
 func (c *conn) Query(query string, args []driver.Value) (driver.Rows, error) {
-	ctx := context.TODO()
+	ctx := c.withApplicationName(context.TODO())
 	bindVars, err := c.convert.buildBindVars(args)
 	if err != nil {
 		return nil, err
 	}
 
+	defer c.recordLatency(query, time.Now())
+	ctx, cancel := c.withQueryTimeout(ctx)
 	if c.cfg.Streaming {
-		stream, err := c.session.StreamExecute(ctx, query, bindVars)
-		if err != nil {
-			return nil, err
-		}
-		return newStreamingRows(stream, c.convert), nil
+		return c.streamExecute(ctx, cancel, query, bindVars)
 	}
+	defer cancel()
 
-	qr, err := c.session.Execute(ctx, query, bindVars)
+	qr, err := c.execute(ctx, query, bindVars)
 	if err != nil {
-		return nil, err
+		return nil, c.sqlError(err)
 	}
+	c.trackReadYourWrites(query)
 	return newRows(qr, c.convert), nil
 }
 
@@ -541,25 +781,215 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 	if query == "vt_session_token" {
 		return newSessionTokenRow(c.session.SessionPb(), c.convert)
 	}
+	ctx = c.withApplicationName(ctx)
 
 	bv, err := c.convert.bindVarsFromNamedValues(args)
 	if err != nil {
 		return nil, err
 	}
 
+	defer c.recordLatency(query, time.Now())
+	if gtid, timeout, ok := minimumGTIDFromContext(ctx); ok {
+		defer c.requireGTID(gtid, timeout)()
+	}
+	ctx, cancel := c.withQueryTimeout(ctx)
+	if c.cfg.Streaming {
+		return c.streamExecute(ctx, cancel, query, bv)
+	}
+	defer cancel()
+
+	qr, err := c.execute(ctx, query, bv)
+	if err != nil {
+		return nil, c.sqlError(err)
+	}
+	c.trackReadYourWrites(query)
+	return newRows(qr, c.convert), nil
+}
+
+// withConn calls fn with the driver connection underlying c, which must
+// implement T. feature describes T in the error returned when it does not.
+func withConn[T any](c *sql.Conn, feature string, fn func(T) error) error {
+	return c.Raw(func(driverConn any) error {
+		dc, ok := driverConn.(T)
+		if !ok {
+			return fmt.Errorf("connection does not support %s", feature)
+		}
+		return fn(dc)
+	})
+}
+
+// BatchExecer sends several statements to vtgate in a single ExecuteBatch
+// call. The ExecBatch function calls it on the connection of a *sql.Conn.
+type BatchExecer interface {
+	ExecBatch(queries []string, args [][]driver.NamedValue) ([]Result, error)
+	ExecBatchContext(ctx context.Context, queries []string, args [][]driver.NamedValue) ([]Result, error)
+}
+
+// Result is the outcome of a single statement sent as part of a batch.
+// Err is set if that statement failed, in which case Result is nil.
+type Result struct {
+	driver.Result
+	Err error
+}
+
+// ExecBatch sends all the given statements to vtgate in a single round trip
+// and returns their results in order. args may be nil, or must have one entry
+// per statement. A failing statement does not prevent the others from being
+// executed; its error is reported in the corresponding Result.
+func ExecBatch(ctx context.Context, c *sql.Conn, queries []string, args [][]driver.NamedValue) ([]Result, error) {
+	var results []Result
+	err := withConn(c, "batch execution", func(be BatchExecer) error {
+		var err error
+		results, err = be.ExecBatchContext(ctx, queries, args)
+		return err
+	})
+	return results, err
+}
+
+func (c *conn) ExecBatch(queries []string, args [][]driver.NamedValue) ([]Result, error) {
+	return c.ExecBatchContext(context.TODO(), queries, args)
+}
+
+func (c *conn) ExecBatchContext(ctx context.Context, queries []string, args [][]driver.NamedValue) ([]Result, error) {
+	ctx = c.withApplicationName(ctx)
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
 	if c.cfg.Streaming {
-		stream, err := c.session.StreamExecute(ctx, query, bv)
+		return nil, errors.New("ExecBatch not allowed for streaming connections")
+	}
+	if args != nil && len(args) != len(queries) {
+		return nil, fmt.Errorf("number of argument lists (%d) does not match number of queries (%d)", len(args), len(queries))
+	}
+
+	bindVars := make([]map[string]*querypb.BindVariable, len(queries))
+	for i := range args {
+		bv, err := c.convert.bindVarsFromNamedValues(args[i])
 		if err != nil {
 			return nil, err
 		}
-		return newStreamingRows(stream, c.convert), nil
+		bindVars[i] = bv
+	}
+	return c.execBatch(ctx, queries, bindVars)
+}
+
+// execBatch sends the statements to vtgate with ExecuteBatch, in an implicit
+// transaction if ImplicitTransactions requires one.
+func (c *conn) execBatch(ctx context.Context, queries []string, bindVars []map[string]*querypb.BindVariable) ([]Result, error) {
+	// every statement of the batch takes the whole round trip to vtgate
+	defer func(start time.Time) {
+		for _, query := range queries {
+			c.recordLatency(query, start)
+		}
+	}(time.Now())
+
+	implicitTx := c.cfg.ImplicitTransactions && len(queries) > 1 && !c.session.SessionPb().GetInTransaction()
+	if implicitTx {
+		if _, err := c.session.Execute(ctx, "begin", nil); err != nil {
+			return nil, c.sqlError(err)
+		}
 	}
 
-	qr, err := c.session.Execute(ctx, query, bv)
+	qrs, err := c.session.ExecuteBatch(ctx, queries, bindVars)
 	if err != nil {
+		err = c.sqlError(err)
+		if implicitTx {
+			err = c.endImplicitTransaction(ctx, err)
+		}
 		return nil, err
 	}
-	return newRows(qr, c.convert), nil
+
+	var firstErr error
+	results := make([]Result, len(qrs))
+	for i, qr := range qrs {
+		if qr.QueryError != nil {
+			results[i].Err = c.sqlError(qr.QueryError)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("statement %d failed: %w", i, results[i].Err)
+			}
+			continue
+		}
+		results[i].Result = result{int64(qr.QueryResult.InsertID), int64(qr.QueryResult.RowsAffected)}
+		c.trackGTID(qr.QueryResult)
+		c.trackReadYourWrites(queries[i])
+	}
+	if implicitTx {
+		return results, c.endImplicitTransaction(ctx, firstErr)
+	}
+	return results, nil
+}
+
+// implicitTransactionStatements returns the statements of query if it must
+// run in an implicit transaction, i.e. if ImplicitTransactions is set, no
+// transaction is open and the query has more than one statement. Otherwise,
+// or if the query cannot be split, it returns nil and the query is sent as is.
+func (c *conn) implicitTransactionStatements(query string) []string {
+	if !c.cfg.ImplicitTransactions || c.session.SessionPb().GetInTransaction() {
+		return nil
+	}
+	parser, err := statementSplitter()
+	if err != nil {
+		return nil
+	}
+	pieces, err := parser.SplitStatementToPieces(query)
+	if err != nil || len(pieces) < 2 {
+		return nil
+	}
+	statements := make([]string, 0, len(pieces))
+	for _, piece := range pieces {
+		if piece = strings.TrimSpace(piece); piece != "" {
+			statements = append(statements, piece)
+		}
+	}
+	return statements
+}
+
+// statementSplitter returns the parser that splits multi-statement queries.
+// Splitting only looks for semicolons, so it does not depend on the MySQL
+// version of the parser.
+var statementSplitter = sync.OnceValues(func() (*sqlparser.Parser, error) {
+	return sqlparser.New(sqlparser.Options{})
+})
+
+// execStatements runs the statements of a multi-statement query in a single
+// batch and an implicit transaction. They all share the given bind variables.
+// The returned result has the rows affected by all the statements, and the
+// insert id of the last statement that generated one.
+func (c *conn) execStatements(ctx context.Context, statements []string, bindVars map[string]*querypb.BindVariable) (driver.Result, error) {
+	batchBindVars := make([]map[string]*querypb.BindVariable, len(statements))
+	for i := range batchBindVars {
+		batchBindVars[i] = bindVars
+	}
+	results, err := c.execBatch(ctx, statements, batchBindVars)
+	if err != nil {
+		return nil, err
+	}
+	var res result
+	for _, r := range results {
+		rowsAffected, _ := r.RowsAffected()
+		res.rowsaffected += rowsAffected
+		if insertID, _ := r.LastInsertId(); insertID != 0 {
+			res.insertid = insertID
+		}
+	}
+	return res, nil
+}
+
+// endImplicitTransaction commits the transaction opened for an ImplicitTransactions
+// batch if batchErr is nil, and rolls it back otherwise. The returned error
+// wraps batchErr if the transaction was rolled back.
+func (c *conn) endImplicitTransaction(ctx context.Context, batchErr error) error {
+	if batchErr == nil {
+		if _, err := c.session.Execute(ctx, "commit", nil); err != nil {
+			return c.sqlError(err)
+		}
+		c.trackReadYourWrites("commit")
+		return nil
+	}
+	if _, err := c.session.Execute(ctx, "rollback", nil); err != nil {
+		return fmt.Errorf("failed to roll back implicit transaction: %v, after: %w", err, batchErr)
+	}
+	c.trackReadYourWrites("rollback")
+	return fmt.Errorf("implicit transaction rolled back: %w", batchErr)
 }
 
 type stmt struct {
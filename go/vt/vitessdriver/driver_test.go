@@ -20,7 +20,9 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"reflect"
@@ -32,10 +34,16 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/grpcvtgateservice"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 )
 
 var testAddress string
@@ -131,6 +139,22 @@ func TestOpen(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:    "Open() with DSN",
+			connStr: fmt.Sprintf("vitess://%s/ks?target=@replica&timeout=30s&defaultlocation=America/Los_Angeles", testAddress),
+			conn: &conn{
+				cfg: Configuration{
+					Protocol:        "grpc",
+					DriverName:      "vitess",
+					Target:          "ks@replica",
+					DefaultLocation: "America/Los_Angeles",
+					QueryTimeout:    30 * time.Second,
+				},
+				convert: &converter{
+					location: locationPST,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -167,6 +191,14 @@ func TestOpen_InvalidJson(t *testing.T) {
 	}
 }
 
+func TestOpen_InvalidDSN(t *testing.T) {
+	_, err := drv{}.Open("vitess://localhost:1?foo=bar")
+	want := "invalid DSN"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("err: %v, want %s", err, want)
+	}
+}
+
 func TestBeginIsolation(t *testing.T) {
 	db, err := Open(testAddress, "@primary")
 	require.NoError(t, err)
@@ -215,6 +247,210 @@ func TestExec(t *testing.T) {
 	}
 }
 
+func TestExecBatch(t *testing.T) {
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(0)}}
+	results, err := ExecBatch(ctx, sconn, []string{"request", "requestDates", "none"}, [][]driver.NamedValue{args, args, nil})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.NoError(t, results[0].Err)
+	insertID, _ := results[0].LastInsertId()
+	rowsAffected, _ := results[0].RowsAffected()
+	assert.EqualValues(t, 72, insertID)
+	assert.EqualValues(t, 123, rowsAffected)
+
+	require.NoError(t, results[1].Err)
+	insertID, _ = results[1].LastInsertId()
+	rowsAffected, _ = results[1].RowsAffected()
+	assert.EqualValues(t, 73, insertID)
+	assert.EqualValues(t, 42, rowsAffected)
+
+	assert.ErrorContains(t, results[2].Err, "no match for: none")
+
+	_, err = ExecBatch(ctx, sconn, []string{"request"}, [][]driver.NamedValue{args, args})
+	assert.ErrorContains(t, err, "does not match number of queries")
+}
+
+func TestExecBatchImplicitTransactions(t *testing.T) {
+	db, err := OpenWithConfiguration(Configuration{
+		Address:              testAddress,
+		Target:               "@primary",
+		ImplicitTransactions: true,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(0)}}
+	results, err := ExecBatch(ctx, sconn, []string{"txRequest", "none"}, [][]driver.NamedValue{args, nil})
+	assert.ErrorContains(t, err, "implicit transaction rolled back: statement 1 failed")
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "no match for: none")
+
+	// the fake server only ends up with this session after a rollback.
+	info, err := GetSessionInfo(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, SessionInfo{TargetString: "@primary"}, info)
+}
+
+func TestExecImplicitTransactions(t *testing.T) {
+	db, err := OpenWithConfiguration(Configuration{
+		Address:              testAddress,
+		Target:               "@primary",
+		ImplicitTransactions: true,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	// a single connection, so that its session can be inspected after Exec.
+	db.SetMaxOpenConns(1)
+
+	// the second statement fails, so the first one is rolled back.
+	_, err = db.Exec("txRequest; none", int64(0))
+	assert.ErrorContains(t, err, "implicit transaction rolled back: statement 1 failed")
+	assert.ErrorContains(t, err, "no match for: none")
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	// the fake server only ends up with this session after a rollback.
+	info, err := GetSessionInfo(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, SessionInfo{TargetString: "@primary"}, info)
+}
+
+func TestApplicationName(t *testing.T) {
+	db, err := OpenWithConfiguration(Configuration{
+		Address:         testAddress,
+		Target:          "@rdonly",
+		ApplicationName: "billing",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var component string
+	require.NoError(t, db.QueryRow(callerComponentQuery).Scan(&component))
+	assert.Equal(t, "billing", component)
+
+	// an effective caller ID already in the context is left alone.
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("user", "reporting", ""), nil)
+	require.NoError(t, db.QueryRowContext(ctx, callerComponentQuery).Scan(&component))
+	assert.Equal(t, "reporting", component)
+}
+
+func TestQueryTimeout(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%v", streaming), func(t *testing.T) {
+			db, err := OpenWithConfiguration(Configuration{
+				Address:      testAddress,
+				Target:       "@rdonly",
+				Streaming:    streaming,
+				QueryTimeout: time.Hour,
+			})
+			require.NoError(t, err)
+			defer db.Close()
+
+			var left int64
+			require.NoError(t, db.QueryRow(deadlineQuery).Scan(&left))
+			assert.Greater(t, left, (59 * time.Minute).Milliseconds())
+			assert.LessOrEqual(t, left, time.Hour.Milliseconds())
+
+			// an explicit deadline wins, even if it is longer.
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+			defer cancel()
+			require.NoError(t, db.QueryRowContext(ctx, deadlineQuery).Scan(&left))
+			assert.Greater(t, left, time.Hour.Milliseconds())
+		})
+	}
+
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	var left int64
+	require.NoError(t, db.QueryRow(deadlineQuery).Scan(&left))
+	assert.EqualValues(t, -1, left)
+}
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := Open(testAddress, "@primary")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.PingContext(ctx))
+
+	// a connection whose vtgate client was closed is reported as bad.
+	closedConn, err := vtgateconn.DialProtocol(ctx, "grpc", testAddress)
+	require.NoError(t, err)
+	closedSession := closedConn.Session("@primary", nil)
+	closedConn.Close()
+
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+	err = sconn.Raw(func(driverConn any) error {
+		c := driverConn.(*conn)
+		c.session = closedSession
+		return c.Ping(ctx)
+	})
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+
+	// errors returned by vtgate itself do not make the connection bad.
+	assert.False(t, isConnectionError(vterrors.New(vtrpcpb.Code_UNAVAILABLE, "no healthy tablet available")))
+
+	streamingDB, err := OpenForStreaming(testAddress, "@primary")
+	require.NoError(t, err)
+	defer streamingDB.Close()
+	assert.EqualError(t, streamingDB.PingContext(ctx), "Ping not allowed for streaming connections")
+}
+
+func TestBufferingDelay(t *testing.T) {
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	_, err = sconn.ExecContext(ctx, bufferedQuery)
+	require.NoError(t, err)
+	delay, err := BufferingDelay(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, delay)
+
+	// the delay only belongs to the statement that was buffered.
+	_, err = sconn.ExecContext(ctx, warningQuery)
+	require.NoError(t, err)
+	delay, err = BufferingDelay(ctx, sconn)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	// a statement that failed after it was buffered reports its delay too.
+	_, err = sconn.ExecContext(ctx, bufferedFailureQuery)
+	require.Error(t, err)
+	delay, err = BufferingDelay(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, delay)
+}
+
 func TestConfigurationToJSON(t *testing.T) {
 	config := Configuration{
 		Protocol:        "some-invalid-protocol",
@@ -222,7 +458,7 @@ func TestConfigurationToJSON(t *testing.T) {
 		Streaming:       true,
 		DefaultLocation: "Local",
 	}
-	want := `{"Protocol":"some-invalid-protocol","Address":"","Target":"ks2","Streaming":true,"DefaultLocation":"Local","SessionToken":""}`
+	want := `{"Protocol":"some-invalid-protocol","Address":"","Target":"ks2","Cell":"","Streaming":true,"DefaultLocation":"Local","SessionToken":"","QueryLatencyMetrics":false,"QueryLatencyMaxFingerprints":0,"TrackGTIDs":false,"DecimalHandling":"","SQLErrors":false,"ImplicitTransactions":false,"ApplicationName":"","SchemaVersionQuery":"","SchemaVersionInterval":0,"QueryTimeout":0,"ReadYourWrites":false,"PositionalBindPrefix":"","MaxRetries":0}`
 
 	json, err := config.toJSON()
 	if err != nil {
@@ -233,6 +469,26 @@ func TestConfigurationToJSON(t *testing.T) {
 	}
 }
 
+func TestExecCell(t *testing.T) {
+	db, err := OpenWithConfiguration(Configuration{
+		Address: testAddress,
+		Target:  "@rdonly",
+		Cell:    "zone1",
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the fake server only accepts cellRequest with the cell in the target.
+	r, err := db.Exec("cellRequest", int64(0))
+	require.NoError(t, err)
+	rowsAffected, err := r.RowsAffected()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, rowsAffected)
+
+	_, err = db.Exec("request", int64(0))
+	require.Error(t, err)
+}
+
 func TestExecStreamingNotAllowed(t *testing.T) {
 	db, err := OpenForStreaming(testAddress, "@rdonly")
 	if err != nil {
@@ -437,6 +693,36 @@ func TestBindVars(t *testing.T) {
 	}
 }
 
+func TestPositionalBindPrefix(t *testing.T) {
+	converter, err := newConverter(&Configuration{PositionalBindPrefix: "arg_"})
+	require.NoError(t, err)
+
+	bv, err := converter.bindVarsFromNamedValues([]driver.NamedValue{
+		{Ordinal: 1, Value: int64(0)},
+		{Ordinal: 2, Value: "abcd"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*querypb.BindVariable{
+		"arg_1": sqltypes.Int64BindVariable(0),
+		"arg_2": sqltypes.StringBindVariable("abcd"),
+	}, bv)
+
+	bv, err = converter.buildBindVars([]driver.Value{int64(0)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*querypb.BindVariable{"arg_1": sqltypes.Int64BindVariable(0)}, bv)
+
+	_, err = converter.bindVarsFromNamedValues([]driver.NamedValue{
+		{Ordinal: 1, Value: int64(0)},
+		{Name: "n2", Value: "abcd"},
+	})
+	assert.ErrorIs(t, err, errNoIntermixing)
+
+	for _, prefix := range []string{"1v", "v-", "v 1", ":v"} {
+		_, err := newConverter(&Configuration{PositionalBindPrefix: prefix})
+		assert.EqualError(t, err, fmt.Sprintf("invalid PositionalBindPrefix: %q", prefix))
+	}
+}
+
 func TestDatetimeQuery(t *testing.T) {
 	testcases := []struct {
 		desc        string
@@ -547,6 +833,74 @@ func TestDatetimeQuery(t *testing.T) {
 	}
 }
 
+func TestDecimalHandling(t *testing.T) {
+	const decimal = "12345678901234567890.123456789"
+	wantRat, _ := new(big.Rat).SetString(decimal)
+
+	testcases := []struct {
+		handling DecimalHandling
+		want     any
+		scanType reflect.Type
+	}{{
+		handling: DecimalAsBytes,
+		want:     []byte(decimal),
+		scanType: typeRawBytes,
+	}, {
+		handling: DecimalAsString,
+		want:     decimal,
+		scanType: typeString,
+	}, {
+		handling: DecimalAsFloat64,
+		want:     12345678901234567890.123456789,
+		scanType: typeFloat64,
+	}, {
+		handling: DecimalAsRat,
+		want:     wantRat,
+		scanType: typeRat,
+	}}
+
+	for _, tc := range testcases {
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%q streaming=%v", tc.handling, streaming), func(t *testing.T) {
+				db, err := OpenWithConfiguration(Configuration{
+					Address:         testAddress,
+					Target:          "@rdonly",
+					Streaming:       streaming,
+					DecimalHandling: tc.handling,
+				})
+				require.NoError(t, err)
+				defer db.Close()
+
+				rows, err := db.Query("requestDecimal", 0)
+				require.NoError(t, err)
+				defer rows.Close()
+				columnTypes, err := rows.ColumnTypes()
+				require.NoError(t, err)
+				assert.Equal(t, tc.scanType, columnTypes[0].ScanType())
+
+				var got any
+				require.True(t, rows.Next())
+				require.NoError(t, rows.Scan(&got))
+				assert.IsType(t, tc.want, got)
+				switch want := tc.want.(type) {
+				case *big.Rat:
+					// no precision is lost
+					assert.Zero(t, want.Cmp(got.(*big.Rat)))
+					assert.Equal(t, "12345678901234567890.123456789", got.(*big.Rat).FloatString(9))
+				default:
+					assert.Equal(t, want, got)
+				}
+			})
+		}
+	}
+
+	_, err := OpenWithConfiguration(Configuration{
+		Address:         testAddress,
+		DecimalHandling: "unknown",
+	})
+	assert.ErrorContains(t, err, `unknown DecimalHandling: "unknown"`)
+}
+
 func TestTx(t *testing.T) {
 	c := Configuration{
 		Protocol: "grpc",
@@ -724,6 +1078,34 @@ func TestSessionToken(t *testing.T) {
 	}
 }
 
+func TestShardSessionsFromTx(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := Open(testAddress, "@primary")
+	require.NoError(t, err)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = tx.Exec("txRequest", int64(0))
+	require.NoError(t, err)
+
+	shardSessions, err := ShardSessionsFromTx(ctx, tx)
+	require.NoError(t, err)
+	require.Len(t, shardSessions, 1)
+	assert.True(t, proto.Equal(session2.ShardSessions[0], shardSessions[0]), "got %v", shardSessions[0])
+
+	// streaming connections cannot run transactions.
+	streamingDB, err := OpenForStreaming(testAddress, "@primary")
+	require.NoError(t, err)
+	defer streamingDB.Close()
+
+	_, err = streamingDB.Begin()
+	assert.ErrorContains(t, err, "not allowed for streaming connections")
+}
+
 // TestStreamExec tests that different kinds of query present in `execMap` can run through streaming api
 func TestStreamExec(t *testing.T) {
 	db, err := OpenForStreaming(testAddress, "@rdonly")
@@ -740,6 +1122,13 @@ func TestStreamExec(t *testing.T) {
 			require.NoError(t, err)
 			defer r.Close()
 
+			if v.err != nil {
+				for r.Next() {
+				}
+				require.ErrorContains(t, r.Err(), v.err.Error())
+				return
+			}
+
 			fields, err := r.Columns()
 			require.NoError(t, err)
 			require.Equal(t, colList(v.result.Fields), fields)
@@ -853,11 +1242,137 @@ func TestConnReuseSessions(t *testing.T) {
 func targetString(t *testing.T, c *sql.Conn) string {
 	t.Helper()
 
-	var target string
-	require.NoError(t, c.Raw(func(driverConn any) error {
-		target = driverConn.(*conn).session.SessionPb().TargetString
-		return nil
-	}))
+	info, err := GetSessionInfo(context.Background(), c)
+	require.NoError(t, err)
+
+	return info.TargetString
+}
+
+func TestGetSessionInfo(t *testing.T) {
+	db, err := Open(testAddress, "@primary")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	info, err := GetSessionInfo(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, SessionInfo{TargetString: "@primary", Autocommit: true}, info)
+
+	_, err = sconn.ExecContext(ctx, "use @rdonly")
+	require.NoError(t, err)
+
+	info, err = GetSessionInfo(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, SessionInfo{TargetString: "@rdonly", SessionUUID: "1111"}, info)
+}
+
+func TestSQLErrors(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%v", streaming), func(t *testing.T) {
+			db, err := OpenWithConfiguration(Configuration{
+				Address:   testAddress,
+				Target:    "@rdonly",
+				Streaming: streaming,
+				SQLErrors: true,
+			})
+			require.NoError(t, err)
+			defer db.Close()
+
+			rows, err := db.Query("duplicateKeyRequest", int64(0))
+			if err == nil {
+				// the error of a stream is only returned once it is consumed
+				assert.False(t, rows.Next())
+				err = rows.Err()
+				rows.Close()
+			}
+
+			var sqlErr *sqlerror.SQLError
+			require.ErrorAs(t, err, &sqlErr)
+			assert.Equal(t, sqlerror.ERDupEntry, sqlErr.Number())
+			assert.Equal(t, sqlerror.SSConstraintViolation, sqlErr.SQLState())
+		})
+	}
+
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("duplicateKeyRequest", int64(0))
+	var sqlErr *sqlerror.SQLError
+	assert.False(t, errors.As(err, &sqlErr), "errors should only be converted when SQLErrors is set")
+	assert.ErrorContains(t, err, "Duplicate entry '1' for key 'PRIMARY'")
+}
+
+func TestInTransaction(t *testing.T) {
+	db, err := Open(testAddress, "@primary")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	inTx, err := InTransaction(ctx, sconn)
+	require.NoError(t, err)
+	assert.False(t, inTx)
+
+	for _, end := range []func(*sql.Tx) error{(*sql.Tx).Commit, (*sql.Tx).Rollback} {
+		tx, err := sconn.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		inTx, err = InTransaction(ctx, sconn)
+		require.NoError(t, err)
+		assert.True(t, inTx)
+
+		_, err = tx.ExecContext(ctx, "txRequest", int64(0))
+		require.NoError(t, err)
+		require.NoError(t, end(tx))
+
+		inTx, err = InTransaction(ctx, sconn)
+		require.NoError(t, err)
+		assert.False(t, inTx)
+	}
+}
+
+func TestSchemaVersionTracking(t *testing.T) {
+	fakeSchemaVersion.Store(1)
+
+	type change struct{ oldVersion, newVersion string }
+	changes := make(chan change, 10)
+	db, err := OpenWithConfiguration(Configuration{
+		Address:               testAddress,
+		Target:                "@rdonly",
+		SchemaVersionQuery:    schemaVersionQuery,
+		SchemaVersionInterval: 10 * time.Millisecond,
+		OnSchemaVersionChange: func(oldVersion, newVersion string) {
+			changes <- change{oldVersion, newVersion}
+		},
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	select {
+	case c := <-changes:
+		t.Fatalf("unexpected schema version change before any bump: %v", c)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	fakeSchemaVersion.Store(2)
+	select {
+	case c := <-changes:
+		assert.Equal(t, change{"1", "2"}, c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnSchemaVersionChange was not called after the schema version changed")
+	}
 
-	return target
+	_, err = OpenWithConfiguration(Configuration{
+		Address:               testAddress,
+		OnSchemaVersionChange: func(string, string) {},
+	})
+	assert.EqualError(t, err, "OnSchemaVersionChange requires SchemaVersionQuery")
 }
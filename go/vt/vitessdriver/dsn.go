@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dsnScheme is the scheme of the DSNs accepted by ParseDSN.
+const dsnScheme = "vitess"
+
+// ParseDSN parses a DSN of the form
+//
+//	vitess://host:port[/keyspace][?param=value&...]
+//
+// into a Configuration. The supported parameters are:
+//
+//   - protocol: the vtgate RPC client implementation, see Configuration.Protocol.
+//   - target: the target, see Configuration.Target. If the DSN has a keyspace,
+//     target may only be a tablet type like "@replica", which is appended to it.
+//   - cell: the cell whose tablets are preferred, see Configuration.Cell.
+//   - timeout: the QueryTimeout, as accepted by time.ParseDuration.
+//   - streaming: whether streaming RPCs are used, as accepted by strconv.ParseBool.
+//   - defaultlocation: the DefaultLocation.
+//
+// Parameter names are case-insensitive. Unknown parameters are rejected, and
+// default values are not filled in.
+func ParseDSN(dsn string) (Configuration, error) {
+	var cfg Configuration
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != dsnScheme {
+		return cfg, fmt.Errorf("invalid DSN: scheme must be %q, got %q", dsnScheme, u.Scheme)
+	}
+	if u.Host == "" {
+		return cfg, fmt.Errorf("invalid DSN: missing host:port")
+	}
+	keyspace := strings.TrimPrefix(u.Path, "/")
+	if strings.Contains(keyspace, "/") {
+		return cfg, fmt.Errorf("invalid DSN: path must be a single keyspace, got %q", u.Path)
+	}
+	cfg.Address = u.Host
+	cfg.Target = keyspace
+
+	for key, values := range u.Query() {
+		if len(values) != 1 {
+			return cfg, fmt.Errorf("invalid DSN: parameter %s must be set once", key)
+		}
+		value := values[0]
+		switch strings.ToLower(key) {
+		case "protocol":
+			cfg.Protocol = value
+		case "target":
+			switch {
+			case keyspace == "":
+				cfg.Target = value
+			case strings.HasPrefix(value, "@"):
+				cfg.Target = keyspace + value
+			default:
+				return cfg, fmt.Errorf("invalid DSN: target %q cannot be combined with keyspace %q", value, keyspace)
+			}
+		case "cell":
+			cfg.Cell = value
+		case "timeout":
+			cfg.QueryTimeout, err = time.ParseDuration(value)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid DSN: invalid timeout: %w", err)
+			}
+		case "streaming":
+			cfg.Streaming, err = strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid DSN: invalid streaming: %w", err)
+			}
+		case "defaultlocation":
+			cfg.DefaultLocation = value
+		default:
+			return cfg, fmt.Errorf("invalid DSN: unknown parameter %s", key)
+		}
+	}
+	return cfg, nil
+}
+
+// isJSONName returns true if name, as passed to Open, is a JSON object rather
+// than a DSN.
+func isJSONName(name string) bool {
+	return strings.HasPrefix(strings.TrimSpace(name), "{")
+}
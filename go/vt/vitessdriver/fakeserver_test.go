@@ -20,21 +20,107 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 )
 
 // fakeVTGateService has the server side of this fake
 type fakeVTGateService struct{}
 
+// schemaVersionQuery returns the value of fakeSchemaVersion, which tests can
+// bump to simulate a schema change.
+const schemaVersionQuery = "schemaVersion"
+
+var fakeSchemaVersion atomic.Int64
+
+// callerComponentQuery returns the component of the effective caller ID of
+// the request.
+const callerComponentQuery = "callerComponent"
+
+// deadlineQuery returns the number of milliseconds left before the deadline
+// of the request, or -1 if it has none.
+const deadlineQuery = "deadline"
+
+func deadlineResult(ctx context.Context) *sqltypes.Result {
+	left := int64(-1)
+	if deadline, ok := ctx.Deadline(); ok {
+		left = time.Until(deadline).Milliseconds()
+	}
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("left", "int64"), strconv.FormatInt(left, 10))
+}
+
+// warningQuery returns an empty result, with a truncation warning on the
+// session.
+const warningQuery = "warning"
+
+// bufferedQuery returns an empty result, with the buffering delay vtgate
+// reports on the session when a query was buffered during a failover.
+// bufferedFailureQuery reports the same delay, but fails.
+const (
+	bufferedQuery        = "buffered"
+	bufferedFailureQuery = "buffered failure"
+)
+
+// targetQuery returns the target of the session of the request.
+const targetQuery = "select target"
+
+// writeQuery and writeRollbackQuery are a write and a rollback accepted
+// whatever the session is.
+const (
+	writeQuery         = "insert into anyTarget values (1)"
+	writeRollbackQuery = "rollback /* anyTarget */"
+)
+
+// savepointQueries are accepted whatever the session is.
+var savepointQueries = map[string]bool{
+	"savepoint `sp1`":         true,
+	"rollback to `sp1`":       true,
+	"release savepoint `sp1`": true,
+}
+
+// pingQuery is the query used by Ping.
+const pingQuery = "select 1"
+
+// reapedTransactionID is the id of a transaction that was killed, which
+// validateSessionTokenQuery fails on when it targets its shard.
+// validateSessionTokenQuery also fails if the session does not target a shard.
+const reapedTransactionID = 666
+
+// flakyQuery fails with a connection error as long as flakyQueryFailures is
+// positive, decrementing it, and then succeeds.
+const flakyQuery = "select flaky"
+
+var flakyQueryFailures atomic.Int32
+
+// vitessTabletsQuery returns vitessTabletsResult whatever the session and
+// bind variables are.
+const vitessTabletsQuery = "show vitess_tablets"
+
+var vitessTabletsResult = sqltypes.MakeTestResult(
+	sqltypes.MakeTestFields("Cell|Keyspace|Shard|TabletType|State|Alias|Hostname|PrimaryTermStartTime", "varchar|varchar|varchar|varchar|varchar|varchar|varchar|varchar"),
+	"zone1|ks2|0|PRIMARY|SERVING|zone1-0000000300|host3|2024-01-01T00:00:00Z",
+	"zone1|ks1|80-|PRIMARY|SERVING|zone1-0000000200|host2|2024-01-01T00:00:00Z",
+	"zone1|ks1|-80|REPLICA|SERVING|zone1-0000000101|host1|",
+	"zone1|ks1|-80|PRIMARY|SERVING|zone1-0000000100|host0|2024-01-01T00:00:00Z",
+	"zone2|ks1|-80|REPLICA|SERVING|zone2-0000000101|host4|",
+	"zone1|ks1|80-|RDONLY|NOT_SERVING|zone1-0000000201|host5|",
+)
+
 // queryExecute contains all the fields we use to test Execute
 type queryExecute struct {
 	SQL           string
@@ -44,12 +130,76 @@ type queryExecute struct {
 
 func (q *queryExecute) Equal(q2 *queryExecute) bool {
 	return q.SQL == q2.SQL &&
-		reflect.DeepEqual(q.BindVariables, q2.BindVariables) &&
+		sqltypes.BindVariablesEqual(q.BindVariables, q2.BindVariables) &&
 		proto.Equal(q.Session, q2.Session)
 }
 
 // Execute is part of the VTGateService interface
 func (f *fakeVTGateService) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConnection, session *vtgatepb.Session, sql string, bindVariables map[string]*querypb.BindVariable) (*vtgatepb.Session, *sqltypes.Result, error) {
+	// like vtgate, only report the warnings and buffering delay of the current statement.
+	session.Warnings = nil
+	session.BufferingDelayMs = 0
+	if sql == warningQuery {
+		session.Warnings = append(session.Warnings, &querypb.QueryWarning{
+			Code:    uint32(sqlerror.ERWarnDataTruncated),
+			Message: "Data truncated for column 'name' at row 1",
+		})
+		return session, &sqltypes.Result{}, nil
+	}
+	if sql == bufferedQuery {
+		session.BufferingDelayMs = 1500
+		return session, &sqltypes.Result{}, nil
+	}
+	if sql == bufferedFailureQuery {
+		session.BufferingDelayMs = 1500
+		return session, nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "context was canceled before failover finished")
+	}
+	if sql == schemaVersionQuery {
+		return session, sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("version", "int64"),
+			strconv.FormatInt(fakeSchemaVersion.Load(), 10),
+		), nil
+	}
+	if sql == callerComponentQuery {
+		return session, sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("component", "varchar"),
+			callerid.GetComponent(callerid.EffectiveCallerIDFromContext(ctx)),
+		), nil
+	}
+	if sql == vitessTabletsQuery {
+		return session, vitessTabletsResult, nil
+	}
+	if sql == validateSessionTokenQuery {
+		keyspaceShard, _, _ := strings.Cut(session.TargetString, "@")
+		_, shard, ok := strings.Cut(keyspaceShard, ":")
+		if !ok {
+			return session, nil, fmt.Errorf("session does not target a shard: %s", session.TargetString)
+		}
+		for _, shardSession := range session.ShardSessions {
+			if shardSession.Target.Shard == shard && shardSession.TransactionId == reapedTransactionID {
+				return session, nil, vterrors.Errorf(vtrpcpb.Code_ABORTED, "transaction %d: ended at 2024-01-01 00:00:00.000 UTC (exceeded timeout: 30s)", reapedTransactionID)
+			}
+		}
+		return session, &sqltypes.Result{}, nil
+	}
+	if sql == flakyQuery {
+		if flakyQueryFailures.Add(-1) >= 0 {
+			return session, nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, `connection error: desc = "transport is closing"`)
+		}
+		return session, sqltypes.MakeTestResult(sqltypes.MakeTestFields("flaky", "int64"), "1"), nil
+	}
+	if sql == pingQuery {
+		return session, sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"), nil
+	}
+	if sql == targetQuery {
+		return session, sqltypes.MakeTestResult(sqltypes.MakeTestFields("target", "varchar"), session.TargetString), nil
+	}
+	if sql == writeQuery || sql == writeRollbackQuery || savepointQueries[sql] {
+		return session, &sqltypes.Result{RowsAffected: 1}, nil
+	}
+	if sql == deadlineQuery {
+		return session, deadlineResult(ctx), nil
+	}
 	execCase, ok := execMap[sql]
 	if !ok {
 		return session, nil, fmt.Errorf("no match for: %s", sql)
@@ -62,6 +212,9 @@ func (f *fakeVTGateService) Execute(ctx context.Context, mysqlCtx vtgateservice.
 	if !query.Equal(execCase.execQuery) {
 		return session, nil, fmt.Errorf("Execute request mismatch: got %+v, want %+v", query, execCase.execQuery)
 	}
+	if execCase.err != nil {
+		return session, nil, execCase.err
+	}
 	if execCase.session != nil {
 		proto.Reset(session)
 		proto.Merge(session, execCase.session)
@@ -71,17 +224,22 @@ func (f *fakeVTGateService) Execute(ctx context.Context, mysqlCtx vtgateservice.
 
 // ExecuteBatch is part of the VTGateService interface
 func (f *fakeVTGateService) ExecuteBatch(ctx context.Context, session *vtgatepb.Session, sql []string, bindVariables []map[string]*querypb.BindVariable) (*vtgatepb.Session, []sqltypes.QueryResponse, error) {
-	if len(sql) == 1 {
-		execCase, ok := execMap[sql[0]]
+	if bindVariables == nil {
+		bindVariables = make([]map[string]*querypb.BindVariable, len(sql))
+	}
+	var responses []sqltypes.QueryResponse
+	for i := range sql {
+		execCase, ok := execMap[sql[i]]
 		if !ok {
-			return session, nil, fmt.Errorf("no match for: %s", sql)
-		}
-		if bindVariables == nil {
-			bindVariables = make([]map[string]*querypb.BindVariable, 1)
+			if len(sql) == 1 {
+				return session, nil, fmt.Errorf("no match for: %s", sql)
+			}
+			responses = append(responses, sqltypes.QueryResponse{QueryError: fmt.Errorf("no match for: %s", sql[i])})
+			continue
 		}
 		query := &queryExecute{
-			SQL:           sql[0],
-			BindVariables: bindVariables[0],
+			SQL:           sql[i],
+			BindVariables: bindVariables[i],
 			Session:       session,
 		}
 		if !query.Equal(execCase.execQuery) {
@@ -91,15 +249,20 @@ func (f *fakeVTGateService) ExecuteBatch(ctx context.Context, session *vtgatepb.
 			proto.Reset(session)
 			proto.Merge(session, execCase.session)
 		}
-		return session, []sqltypes.QueryResponse{
-			{QueryResult: execCase.result},
-		}, nil
+		responses = append(responses, sqltypes.QueryResponse{QueryResult: execCase.result})
 	}
-	return session, nil, nil
+	return session, responses, nil
 }
 
 // StreamExecute is part of the VTGateService interface
 func (f *fakeVTGateService) StreamExecute(ctx context.Context, mysqlCtx vtgateservice.MySQLConnection, session *vtgatepb.Session, sql string, bindVariables map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) (*vtgatepb.Session, error) {
+	if sql == deadlineQuery {
+		result := deadlineResult(ctx)
+		if err := callback(&sqltypes.Result{Fields: result.Fields}); err != nil {
+			return session, err
+		}
+		return session, callback(&sqltypes.Result{Rows: result.Rows})
+	}
 	execCase, ok := execMap[sql]
 	if !ok {
 		return session, fmt.Errorf("no match for: %s", sql)
@@ -112,6 +275,9 @@ func (f *fakeVTGateService) StreamExecute(ctx context.Context, mysqlCtx vtgatese
 	if !query.Equal(execCase.execQuery) {
 		return session, fmt.Errorf("request mismatch: got %+v, want %+v", query, execCase.execQuery)
 	}
+	if execCase.err != nil {
+		return session, execCase.err
+	}
 	if execCase.result != nil {
 		result := &sqltypes.Result{
 			Fields: execCase.result.Fields,
@@ -180,6 +346,8 @@ func CreateFakeServer() vtgateservice.VTGateService {
 	return &fakeVTGateService{}
 }
 
+const writtenGTID = "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+
 var execMap = map[string]struct {
 	execQuery *queryExecute
 	result    *sqltypes.Result
@@ -200,6 +368,19 @@ var execMap = map[string]struct {
 		result:  &result1,
 		session: nil,
 	},
+	"cellRequest": {
+		execQuery: &queryExecute{
+			SQL: "cellRequest",
+			BindVariables: map[string]*querypb.BindVariable{
+				"v1": sqltypes.Int64BindVariable(0),
+			},
+			Session: &vtgatepb.Session{
+				TargetString: "@rdonly|zone1",
+				Autocommit:   true,
+			},
+		},
+		result: &sqltypes.Result{RowsAffected: 1},
+	},
 	"requestDates": {
 		execQuery: &queryExecute{
 			SQL: "requestDates",
@@ -214,6 +395,20 @@ var execMap = map[string]struct {
 		result:  &result2,
 		session: nil,
 	},
+	"requestDecimal": {
+		execQuery: &queryExecute{
+			SQL: "requestDecimal",
+			BindVariables: map[string]*querypb.BindVariable{
+				"v1": sqltypes.Int64BindVariable(0),
+			},
+			Session: &vtgatepb.Session{
+				TargetString: "@rdonly",
+				Autocommit:   true,
+			},
+		},
+		result:  &resultDecimal,
+		session: nil,
+	},
 	"txRequest": {
 		execQuery: &queryExecute{
 			SQL: "txRequest",
@@ -281,6 +476,50 @@ var execMap = map[string]struct {
 			TargetString: "@primary",
 		},
 	},
+	"duplicateKeyRequest": {
+		execQuery: &queryExecute{
+			SQL: "duplicateKeyRequest",
+			BindVariables: map[string]*querypb.BindVariable{
+				"v1": sqltypes.Int64BindVariable(0),
+			},
+			Session: &vtgatepb.Session{
+				TargetString: "@rdonly",
+				Autocommit:   true,
+			},
+		},
+		err: sqlerror.NewSQLError(sqlerror.ERDupEntry, sqlerror.SSConstraintViolation, "Duplicate entry '1' for key 'PRIMARY'"),
+	},
+	"gtidWrite": {
+		execQuery: &queryExecute{
+			SQL: "gtidWrite",
+			Session: &vtgatepb.Session{
+				TargetString: "@primary",
+				Autocommit:   true,
+				ReadAfterWrite: &vtgatepb.ReadAfterWrite{
+					SessionTrackGtids: true,
+				},
+			},
+		},
+		result: &sqltypes.Result{
+			RowsAffected:        1,
+			SessionStateChanges: writtenGTID,
+		},
+	},
+	"gtidRead": {
+		execQuery: &queryExecute{
+			SQL: "gtidRead",
+			Session: &vtgatepb.Session{
+				TargetString: "@primary",
+				Autocommit:   true,
+				ReadAfterWrite: &vtgatepb.ReadAfterWrite{
+					ReadAfterWriteGtid:    writtenGTID,
+					ReadAfterWriteTimeout: 5,
+					SessionTrackGtids:     true,
+				},
+			},
+		},
+		result: &sqltypes.Result{},
+	},
 	"use @rdonly": {
 		execQuery: &queryExecute{
 			SQL: "use @rdonly",
@@ -347,6 +586,20 @@ var result2 = sqltypes.Result{
 	},
 }
 
+var resultDecimal = sqltypes.Result{
+	Fields: []*querypb.Field{
+		{
+			Name: "fieldDecimal",
+			Type: sqltypes.Decimal,
+		},
+	},
+	Rows: [][]sqltypes.Value{
+		{
+			sqltypes.NewVarBinary("12345678901234567890.123456789"),
+		},
+	},
+}
+
 var session1 = &vtgatepb.Session{
 	InTransaction: true,
 	TargetString:  "@rdonly",
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// GTIDTracker remembers the GTID vtgate reports for the writes of a
+// connection opened with TrackGTIDs, so that it can be passed to
+// WithMinimumGTID for a later read. LastSeenGTID reads it from a *sql.Conn.
+type GTIDTracker interface {
+	// LastSeenGTID returns the GTID of the last write executed on the
+	// connection, as reported by vtgate. It is empty if vtgate did not
+	// report any, for example because TrackGTIDs is not set.
+	LastSeenGTID() string
+}
+
+// LastSeenGTID returns the GTID of the last write executed on the given
+// connection. The connection must have been opened with TrackGTIDs set.
+func LastSeenGTID(ctx context.Context, c *sql.Conn) (string, error) {
+	var gtid string
+	err := withConn(c, "GTID tracking", func(tracker GTIDTracker) error {
+		gtid = tracker.LastSeenGTID()
+		return nil
+	})
+	return gtid, err
+}
+
+type minimumGTIDKey struct{}
+
+type minimumGTID struct {
+	gtid    string
+	timeout time.Duration
+}
+
+// WithMinimumGTID returns a context that makes the queries it is used with
+// wait until the given GTID has been applied on the tablet serving them, or
+// until the timeout expires. This is mostly useful in tests that need a read
+// to observe a previous write, using the GTID returned by LastSeenGTID.
+func WithMinimumGTID(ctx context.Context, gtid string, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, minimumGTIDKey{}, minimumGTID{gtid: gtid, timeout: timeout})
+}
+
+func minimumGTIDFromContext(ctx context.Context) (string, time.Duration, bool) {
+	mg, ok := ctx.Value(minimumGTIDKey{}).(minimumGTID)
+	if !ok || mg.gtid == "" {
+		return "", 0, false
+	}
+	return mg.gtid, mg.timeout, true
+}
+
+func (c *conn) LastSeenGTID() string {
+	return c.lastSeenGTID
+}
+
+func (c *conn) enableGTIDTracking() {
+	session := c.session.SessionPb()
+	if session.ReadAfterWrite == nil {
+		session.ReadAfterWrite = &vtgatepb.ReadAfterWrite{}
+	}
+	session.ReadAfterWrite.SessionTrackGtids = true
+}
+
+// trackGTID records the GTID returned by vtgate for a write, if any.
+func (c *conn) trackGTID(qr *sqltypes.Result) {
+	if c.cfg.TrackGTIDs && qr.SessionStateChanges != "" {
+		c.lastSeenGTID = qr.SessionStateChanges
+	}
+}
+
+// requireGTID sets the read after write requirement on the session for the
+// next query, and returns a function that clears it again.
+func (c *conn) requireGTID(gtid string, timeout time.Duration) func() {
+	session := c.session.SessionPb()
+	if session.ReadAfterWrite == nil {
+		session.ReadAfterWrite = &vtgatepb.ReadAfterWrite{}
+	}
+	session.ReadAfterWrite.ReadAfterWriteGtid = gtid
+	session.ReadAfterWrite.ReadAfterWriteTimeout = timeout.Seconds()
+	return func() {
+		// the session may have been replaced by the one returned by vtgate
+		if raw := c.session.SessionPb().ReadAfterWrite; raw != nil {
+			raw.ReadAfterWriteGtid = ""
+			raw.ReadAfterWriteTimeout = 0
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAfterWriteGTID(t *testing.T) {
+	c := Configuration{
+		Address:    testAddress,
+		Target:     "@primary",
+		TrackGTIDs: true,
+	}
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	gtid, err := LastSeenGTID(ctx, sconn)
+	require.NoError(t, err)
+	assert.Empty(t, gtid)
+
+	_, err = sconn.ExecContext(ctx, "gtidWrite")
+	require.NoError(t, err)
+	gtid, err = LastSeenGTID(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, writtenGTID, gtid)
+
+	// the fake server only accepts the read if it is required to wait for the written GTID
+	rows, err := sconn.QueryContext(WithMinimumGTID(ctx, gtid, 5*time.Second), "gtidRead")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	// the requirement only applies to the query it was passed to
+	_, err = sconn.QueryContext(ctx, "gtidRead")
+	require.ErrorContains(t, err, "request mismatch")
+}
+
+func TestExecBatchTracksGTID(t *testing.T) {
+	c := Configuration{
+		Address:    testAddress,
+		Target:     "@primary",
+		TrackGTIDs: true,
+	}
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	results, err := ExecBatch(ctx, sconn, []string{"gtidWrite"}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	gtid, err := LastSeenGTID(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, writtenGTID, gtid)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+)
+
+const (
+	// defaultQueryLatencyMaxFingerprints is the number of fingerprints
+	// VitessDriverQueryLatency keeps when Configuration.QueryLatencyMaxFingerprints
+	// is not set.
+	defaultQueryLatencyMaxFingerprints = 100
+
+	// otherFingerprint is the label of the queries whose fingerprint did not
+	// fit in VitessDriverQueryLatency anymore.
+	otherFingerprint = "other"
+)
+
+var (
+	queryLatencyOnce sync.Once
+	queryLatency     *latencyMetrics
+)
+
+// latencyMetrics records the client side latency of queries, keyed by the
+// fingerprint of the query. Once it has seen as many fingerprints as it is
+// allowed to keep, the queries of any other shape are recorded as "other", so
+// that applications building their queries dynamically cannot grow it without
+// bounds.
+type latencyMetrics struct {
+	timings *stats.Timings
+
+	mu           sync.Mutex
+	fingerprints map[string]struct{}
+}
+
+// getQueryLatency returns the latency metrics of the process. They are only
+// registered the first time a connection with Configuration.QueryLatencyMetrics
+// records a query, so that processes that do not enable them do not export
+// VitessDriverQueryLatency at all.
+func getQueryLatency() *latencyMetrics {
+	queryLatencyOnce.Do(func() {
+		queryLatency = &latencyMetrics{
+			timings:      stats.NewTimings("VitessDriverQueryLatency", "Client side latency of queries sent by the Vitess driver", "Query"),
+			fingerprints: make(map[string]struct{}),
+		}
+	})
+	return queryLatency
+}
+
+// label returns the label the given fingerprint is recorded with, keeping at
+// most maxFingerprints distinct fingerprints.
+func (lm *latencyMetrics) label(fp string, maxFingerprints int) string {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if _, ok := lm.fingerprints[fp]; ok {
+		return fp
+	}
+	if len(lm.fingerprints) >= maxFingerprints {
+		return otherFingerprint
+	}
+	lm.fingerprints[fp] = struct{}{}
+	return fp
+}
+
+func (lm *latencyMetrics) reset() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	lm.fingerprints = make(map[string]struct{})
+	lm.timings.Reset()
+}
+
+func (c *conn) recordLatency(query string, start time.Time) {
+	if !c.cfg.QueryLatencyMetrics {
+		return
+	}
+	maxFingerprints := c.cfg.QueryLatencyMaxFingerprints
+	if maxFingerprints <= 0 {
+		maxFingerprints = defaultQueryLatencyMaxFingerprints
+	}
+	lm := getQueryLatency()
+	lm.timings.Record(lm.label(fingerprint(query), maxFingerprints), start)
+}
+
+// fingerprint returns the shape of the given query: string and numeric literals
+// as well as bind variable placeholders are replaced by '?', and whitespace is
+// collapsed. It does not parse the query, so it is cheap enough to be computed
+// for every query, and two queries that only differ in their literals share the
+// same fingerprint.
+func fingerprint(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			space = b.Len() > 0
+			continue
+		case ch == '\'' || ch == '"':
+			// skip the whole quoted literal, honoring backslash escapes and doubled quotes
+			for i++; i < len(query); i++ {
+				if query[i] == '\\' {
+					i++
+				} else if query[i] == ch {
+					if i+1 < len(query) && query[i+1] == ch {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			ch = '?'
+		case ch == ':' && i+1 < len(query) && isIdentChar(query[i+1]):
+			for i+1 < len(query) && isIdentChar(query[i+1]) {
+				i++
+			}
+			ch = '?'
+		case isDigit(ch) && (i == 0 || !isIdentChar(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			ch = '?'
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || isDigit(ch) || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
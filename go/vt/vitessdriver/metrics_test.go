@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	testcases := []struct {
+		query string
+		want  string
+	}{
+		{"select * from t where id = 1", "select * from t where id = ?"},
+		{"select * from t where id = 12.5", "select * from t where id = ?"},
+		{"select  *\n from t1 where name = 'it''s' and x = :v1", "select * from t1 where name = ? and x = ?"},
+		{`insert into t(a, b) values ("a\"b", ?)`, "insert into t(a, b) values (?, ?)"},
+		{"  select col2 from t2  ", "select col2 from t2"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.query, func(t *testing.T) {
+			assert.Equal(t, tc.want, fingerprint(tc.query))
+		})
+	}
+}
+
+func TestQueryLatencyMetrics(t *testing.T) {
+	queryLatency := getQueryLatency()
+	queryLatency.reset()
+
+	c := Configuration{
+		Address:             testAddress,
+		Target:              "@rdonly",
+		QueryLatencyMetrics: true,
+	}
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// both queries have the same shape, so they share a bucket
+	_, err = db.Exec("select * from t where id = 1")
+	require.Error(t, err)
+	_, err = db.Exec("select * from t where id = 42")
+	require.Error(t, err)
+
+	counts := queryLatency.timings.Counts()
+	assert.EqualValues(t, 2, counts["select * from t where id = ?"])
+	assert.Len(t, queryLatency.timings.Histograms(), 1)
+}
+
+func TestQueryLatencyMaxFingerprints(t *testing.T) {
+	queryLatency := getQueryLatency()
+	queryLatency.reset()
+
+	c := Configuration{
+		Address:                     testAddress,
+		Target:                      "@rdonly",
+		QueryLatencyMetrics:         true,
+		QueryLatencyMaxFingerprints: 2,
+	}
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, query := range []string{
+		"select * from t where id = 1",
+		"select * from t where name = 'a'",
+		"select * from t where id = 2",
+		"select * from t1",
+		"select * from t2 where id = 3",
+	} {
+		_, err = db.Exec(query)
+		require.Error(t, err)
+	}
+
+	// the shapes seen after the first two are recorded as "other"
+	assert.Equal(t, map[string]int64{
+		"select * from t where id = ?":   2,
+		"select * from t where name = ?": 1,
+		"other":                          2,
+		"All":                            5,
+	}, queryLatency.timings.Counts())
+}
+
+func TestQueryLatencyMetricsExecBatch(t *testing.T) {
+	queryLatency := getQueryLatency()
+	queryLatency.reset()
+
+	c := Configuration{
+		Address:             testAddress,
+		Target:              "@rdonly",
+		QueryLatencyMetrics: true,
+	}
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	_, err = ExecBatch(ctx, sconn, []string{"select * from t where id = 1", "select * from t1"}, nil)
+	require.NoError(t, err)
+
+	counts := queryLatency.timings.Counts()
+	assert.EqualValues(t, 1, counts["select * from t where id = ?"])
+	assert.EqualValues(t, 1, counts["select * from t1"])
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Preparer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// ExecOnce prepares the query, executes it with args and closes the
+// statement, even if the execution fails.
+func ExecOnce(ctx context.Context, p Preparer, query string, args ...any) (sql.Result, error) {
+	stmt, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+// QueryOnce prepares the query, runs it with args and closes the statement,
+// even if the query fails. The returned rows stay usable: database/sql only
+// releases the statement once they are closed, which remains the caller's
+// responsibility.
+func QueryOnce(ctx context.Context, p Preparer, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	return stmt.QueryContext(ctx, args...)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecOnce(t *testing.T) {
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	r, err := ExecOnce(ctx, db, "request", int64(0))
+	require.NoError(t, err)
+	insertID, _ := r.LastInsertId()
+	rowsAffected, _ := r.RowsAffected()
+	assert.EqualValues(t, 72, insertID)
+	assert.EqualValues(t, 123, rowsAffected)
+
+	_, err = ExecOnce(ctx, db, "none")
+	assert.ErrorContains(t, err, "no match for: none")
+}
+
+func TestQueryOnce(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%v", streaming), func(t *testing.T) {
+			db, err := OpenWithConfiguration(Configuration{
+				Address:   testAddress,
+				Target:    "@rdonly",
+				Streaming: streaming,
+			})
+			require.NoError(t, err)
+			defer db.Close()
+
+			ctx := context.Background()
+			sconn, err := db.Conn(ctx)
+			require.NoError(t, err)
+			defer sconn.Close()
+
+			rows, err := QueryOnce(ctx, sconn, "request", int64(0))
+			require.NoError(t, err)
+
+			type row struct {
+				field1 int16
+				field2 string
+			}
+			var got []row
+			for rows.Next() {
+				var r row
+				require.NoError(t, rows.Scan(&r.field1, &r.field2))
+				got = append(got, r)
+			}
+			require.NoError(t, rows.Err())
+			require.NoError(t, rows.Close())
+			assert.Equal(t, []row{{1, "value1"}, {2, "value2"}}, got)
+
+			rows, err = QueryOnce(ctx, sconn, "none")
+			if streaming && err == nil {
+				// gRPC requires to consume the stream first before the error becomes visible.
+				assert.False(t, rows.Next())
+				err = rows.Err()
+				rows.Close()
+			}
+			assert.ErrorContains(t, err, "no match for: none")
+		})
+	}
+}
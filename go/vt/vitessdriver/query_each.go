@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+)
+
+// RowIterator hands the rows of a query to a callback one at a time, without
+// the per-row allocations of sql.Rows.Scan. QueryEach runs it on a *sql.Conn.
+type RowIterator interface {
+	QueryEach(ctx context.Context, query string, args []driver.NamedValue, fn func(values []driver.Value) error) error
+}
+
+// QueryEach runs the given query and calls fn with the values of every row
+// it returns, in order. Rows are streamed if the connection was opened for
+// streaming. The values slice is reused between rows, so fn must copy any
+// value it wants to keep. Iteration stops at the first error returned by fn,
+// and that error is returned. args may contain sql.NamedArg values to use
+// named bind variables.
+func QueryEach(ctx context.Context, c *sql.Conn, query string, args []any, fn func(values []driver.Value) error) error {
+	namedArgs := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		namedArgs[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if na, ok := arg.(sql.NamedArg); ok {
+			namedArgs[i].Name = na.Name
+			namedArgs[i].Value = na.Value
+		}
+	}
+	return withConn(c, "row iteration", func(ri RowIterator) error {
+		return ri.QueryEach(ctx, query, namedArgs, fn)
+	})
+}
+
+func (c *conn) QueryEach(ctx context.Context, query string, args []driver.NamedValue, fn func(values []driver.Value) error) error {
+	rows, err := c.QueryContext(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		err := rows.Next(values)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryEach(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		c := Configuration{
+			Address:   testAddress,
+			Target:    "@rdonly",
+			Streaming: streaming,
+		}
+		db, err := OpenWithConfiguration(c)
+		require.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+		sconn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer sconn.Close()
+
+		var sum int64
+		err = QueryEach(ctx, sconn, "request", []any{int64(0)}, func(values []driver.Value) error {
+			require.Len(t, values, 2)
+			sum += values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, sum, "streaming: %v", streaming)
+
+		// named arguments are passed as named bind variables
+		sum = 0
+		err = QueryEach(ctx, sconn, "request", []any{sql.Named("v1", int64(0))}, func(values []driver.Value) error {
+			sum += values[0].(int64)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, sum, "streaming: %v", streaming)
+
+		// iteration stops at the first error returned by the callback
+		errStop := errors.New("stop")
+		calls := 0
+		err = QueryEach(ctx, sconn, "request", []any{int64(0)}, func(values []driver.Value) error {
+			calls++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+
+		err = QueryEach(ctx, sconn, "none", nil, func(values []driver.Value) error {
+			return nil
+		})
+		assert.ErrorContains(t, err, "no match for: none")
+	}
+}
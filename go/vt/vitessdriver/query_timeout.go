@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+)
+
+// withQueryTimeout returns ctx with a QueryTimeout deadline, unless it is not
+// set or ctx already has a deadline. The returned cancel func must be called
+// once the statement is done.
+func (c *conn) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.cfg.QueryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.cfg.QueryTimeout)
+}
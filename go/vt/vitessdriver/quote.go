@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// QuoteIdentifier returns name quoted with backticks, so that it can be used
+// as a table or column name in dynamic SQL. Backticks in name are doubled.
+func QuoteIdentifier(name string) string {
+	return sqlescape.EscapeID(name)
+}
+
+// QuoteValue returns v as a SQL literal, so that it can be used in dynamic SQL.
+// It accepts the same types as the arguments of a query, and also slices, which
+// are quoted as a parenthesized list. time.Time values are converted to UTC.
+// Prefer passing values as query arguments when possible.
+func QuoteValue(v any) (string, error) {
+	cv := converter{location: time.UTC}
+	bv, err := cv.BuildBindVariable(v)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	sqlparser.EncodeValue(&buf, bv)
+	return buf.String(), nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, "`users`", QuoteIdentifier("users"))
+	assert.Equal(t, "`select`", QuoteIdentifier("select"))
+	assert.Equal(t, "`we``ird`", QuoteIdentifier("we`ird"))
+	assert.Equal(t, "```; drop table users; --`", QuoteIdentifier("`; drop table users; --"))
+}
+
+func TestQuoteValue(t *testing.T) {
+	testCases := []struct {
+		in   any
+		want string
+	}{
+		{nil, "null"},
+		{int64(-42), "-42"},
+		{uint64(42), "42"},
+		{1.5, "1.5"},
+		{"plain", "'plain'"},
+		{"it's", `'it\'s'`},
+		{`say "hi"`, `'say \"hi\"'`},
+		{"back\\slash", `'back\\slash'`},
+		{"line\nbreak\x00", `'line\nbreak\0'`},
+		{"'; drop table users; --", `'\'; drop table users; --'`},
+		{[]byte("bytes"), "'bytes'"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "'2024-01-02 03:04:05'"},
+		{[]any{int64(1), "a'b"}, `(1, 'a\'b')`},
+	}
+
+	for _, tc := range testCases {
+		got, err := QuoteValue(tc.in)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "%v", tc.in)
+	}
+
+	_, err := QuoteValue(struct{}{})
+	assert.Error(t, err)
+}
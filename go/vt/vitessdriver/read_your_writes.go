@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// trackReadYourWrites pins the session to the primary after a successful
+// write, and unpins it after a commit or rollback, if ReadYourWrites is set.
+func (c *conn) trackReadYourWrites(query string) {
+	if !c.cfg.ReadYourWrites {
+		return
+	}
+	session := c.session.SessionPb()
+	switch sqlparser.Preview(query) {
+	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
+		if !c.pinned {
+			c.pinned = true
+			c.pinnedFromTarget = session.TargetString
+			session.TargetString = primaryTarget(session.TargetString)
+		}
+	case sqlparser.StmtCommit, sqlparser.StmtRollback:
+		if c.pinned {
+			// leave the target alone if it was changed with USE since.
+			if session.TargetString == primaryTarget(c.pinnedFromTarget) {
+				session.TargetString = c.pinnedFromTarget
+			}
+			c.pinned = false
+			c.pinnedFromTarget = ""
+		}
+	}
+}
+
+// primaryTarget returns target with its tablet type replaced by primary. Its
+// preferred cell is kept.
+func primaryTarget(target string) string {
+	target, cell := topoproto.SplitTargetCell(target)
+	keyspaceShard, _, _ := strings.Cut(target, "@")
+	return topoproto.TargetWithCell(keyspaceShard+"@primary", cell)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	db, err := OpenWithConfiguration(Configuration{
+		Address:        testAddress,
+		Target:         "ks:-80@replica",
+		ReadYourWrites: true,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	target := func() string {
+		var target string
+		require.NoError(t, sconn.QueryRowContext(ctx, targetQuery).Scan(&target))
+		return target
+	}
+
+	assert.Equal(t, "ks:-80@replica", target())
+
+	_, err = sconn.ExecContext(ctx, writeQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "ks:-80@primary", target())
+
+	// other connections are not affected.
+	var other string
+	require.NoError(t, db.QueryRowContext(ctx, targetQuery).Scan(&other))
+	assert.Equal(t, "ks:-80@replica", other)
+
+	_, err = sconn.ExecContext(ctx, writeRollbackQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "ks:-80@replica", target())
+}
+
+func TestReadYourWritesDisabled(t *testing.T) {
+	db, err := Open(testAddress, "@replica")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	_, err = sconn.ExecContext(ctx, writeQuery)
+	require.NoError(t, err)
+
+	var target string
+	require.NoError(t, sconn.QueryRowContext(ctx, targetQuery).Scan(&target))
+	assert.Equal(t, "@replica", target)
+}
+
+func TestReadYourWritesStreaming(t *testing.T) {
+	_, err := OpenWithConfiguration(Configuration{
+		Address:        testAddress,
+		Target:         "@replica",
+		Streaming:      true,
+		ReadYourWrites: true,
+	})
+	assert.EqualError(t, err, "ReadYourWrites cannot be used with Streaming")
+}
+
+func TestPrimaryTarget(t *testing.T) {
+	for target, want := range map[string]string{
+		"":               "@primary",
+		"@replica":       "@primary",
+		"ks":             "ks@primary",
+		"ks:-80@rdonly":  "ks:-80@primary",
+		"ks:-80@primary": "ks:-80@primary",
+		"ks@replica|c1":  "ks@primary|c1",
+	} {
+		assert.Equal(t, want, primaryTarget(target), target)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// retryBackoff is the delay before the first retry of a statement. It is
+// doubled for every following retry.
+var retryBackoff = 50 * time.Millisecond
+
+// execute runs the query with Execute, retrying it up to MaxRetries times if
+// it failed because vtgate could not be reached and it is safe to retry.
+func (c *conn) execute(ctx context.Context, query string, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := c.session.Execute(ctx, query, bindVars)
+	backoff := retryBackoff
+	for attempt := 0; err != nil && attempt < c.cfg.MaxRetries && c.canRetry(ctx, query, err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if dialErr := c.redial(ctx); dialErr != nil {
+			return nil, err
+		}
+		qr, err = c.session.Execute(ctx, query, bindVars)
+	}
+	return qr, err
+}
+
+// canRetry returns true if the query, which failed with err, can be retried:
+// vtgate could not be reached, and the query is a read executed outside of a
+// transaction.
+func (c *conn) canRetry(ctx context.Context, query string, err error) bool {
+	if ctx.Err() != nil || !isConnectionError(err) || c.session.SessionPb().GetInTransaction() {
+		return false
+	}
+	return isReadOnlyStatement(query)
+}
+
+// isReadOnlyStatement returns true if query is a read, which can be run twice.
+func isReadOnlyStatement(query string) bool {
+	switch sqlparser.Preview(query) {
+	case sqlparser.StmtSelect, sqlparser.StmtShow, sqlparser.StmtExplain:
+		return true
+	default:
+		return false
+	}
+}
+
+// redial replaces the connection to vtgate with a new one, dialed like the
+// original one, keeping the session.
+func (c *conn) redial(ctx context.Context) error {
+	newConn, err := c.dialVTGate(ctx)
+	if err != nil {
+		return err
+	}
+	c.conn.Close()
+	c.conn = newConn
+	c.session = newConn.SessionFromPb(c.session.SessionPb())
+	return nil
+}
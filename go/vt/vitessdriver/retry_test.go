@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+)
+
+func TestMaxRetries(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+	defer flakyQueryFailures.Store(0)
+
+	ctx := context.Background()
+	db, err := OpenWithConfiguration(Configuration{
+		Address:    testAddress,
+		Target:     "@primary",
+		MaxRetries: 2,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var flaky int64
+	flakyQueryFailures.Store(1)
+	require.NoError(t, db.QueryRowContext(ctx, flakyQuery).Scan(&flaky))
+	assert.EqualValues(t, -1, flakyQueryFailures.Load())
+
+	// more failures than retries.
+	flakyQueryFailures.Store(3)
+	err = db.QueryRowContext(ctx, flakyQuery).Scan(&flaky)
+	assert.ErrorContains(t, err, "connection error")
+	assert.EqualValues(t, 0, flakyQueryFailures.Load())
+
+	// statements in a transaction are never retried.
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "txRequest", int64(0))
+	require.NoError(t, err)
+	flakyQueryFailures.Store(1)
+	_, err = tx.ExecContext(ctx, flakyQuery)
+	assert.ErrorContains(t, err, "connection error")
+	assert.EqualValues(t, 0, flakyQueryFailures.Load())
+	require.NoError(t, tx.Rollback())
+}
+
+func TestMaxRetriesRedial(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = 20 * time.Millisecond
+	defer flakyQueryFailures.Store(0)
+
+	// the connections dialed to retry use the dial options too.
+	var calls atomic.Int64
+	protocol := "grpc-" + t.Name()
+	defer vtgateconn.DeregisterDialer(protocol)
+	ctx := context.Background()
+	db, err := OpenWithConfiguration(Configuration{
+		Protocol: protocol,
+		Address:  testAddress,
+		Target:   "@primary",
+		GRPCDialOptions: []grpc.DialOption{
+			grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				calls.Add(1)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}),
+		},
+		MaxRetries: 2,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var flaky int64
+	flakyQueryFailures.Store(2)
+	start := time.Now()
+	require.NoError(t, db.QueryRowContext(ctx, flakyQuery).Scan(&flaky))
+	assert.EqualValues(t, 3, calls.Load())
+	// the retries back off, doubling the delay every time.
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestIsReadOnlyStatement(t *testing.T) {
+	for query, want := range map[string]bool{
+		"select * from t":              true,
+		"/* comment */ select 1":       true,
+		"show tables":                  true,
+		"explain select * from t":      true,
+		"insert into t values (1)":     false,
+		"update t set a = 1":           false,
+		"delete from t":                false,
+		"set @@session.autocommit = 1": false,
+		"begin":                        false,
+	} {
+		assert.Equal(t, want, isReadOnlyStatement(query), query)
+	}
+}
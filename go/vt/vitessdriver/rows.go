@@ -20,6 +20,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"io"
+	"math/big"
 	"reflect"
 	"time"
 
@@ -74,14 +75,21 @@ var (
 	typeUint64   = reflect.TypeOf(uint64(0))
 	typeFloat32  = reflect.TypeOf(float32(0))
 	typeFloat64  = reflect.TypeOf(float64(0))
+	typeString   = reflect.TypeOf("")
 	typeRawBytes = reflect.TypeOf(sql.RawBytes{})
+	typeRat      = reflect.TypeOf(new(big.Rat))
 	typeTime     = reflect.TypeOf(time.Time{})
 	typeUnknown  = reflect.TypeOf(new(interface{}))
 )
 
 // Implements the RowsColumnTypeScanType interface
 func (ri *rows) ColumnTypeScanType(index int) reflect.Type {
-	field := ri.qr.Fields[index]
+	return fieldScanType(ri.qr.Fields[index], ri.convert.decimal)
+}
+
+// fieldScanType returns the Go type the values of field are converted to,
+// DECIMAL ones being converted according to decimal.
+func fieldScanType(field *query.Field, decimal DecimalHandling) reflect.Type {
 	switch field.GetType() {
 	case query.Type_INT8:
 		return typeInt8
@@ -107,13 +115,24 @@ func (ri *rows) ColumnTypeScanType(index int) reflect.Type {
 		return typeFloat32
 	case query.Type_FLOAT64:
 		return typeFloat64
-	case query.Type_TIMESTAMP, query.Type_DECIMAL, query.Type_VARCHAR, query.Type_TEXT,
+	case query.Type_DECIMAL:
+		switch decimal {
+		case DecimalAsString:
+			return typeString
+		case DecimalAsFloat64:
+			return typeFloat64
+		case DecimalAsRat:
+			return typeRat
+		default:
+			return typeRawBytes
+		}
+	case query.Type_TIME, query.Type_VARCHAR, query.Type_TEXT,
 		query.Type_BLOB, query.Type_VARBINARY, query.Type_CHAR, query.Type_BINARY, query.Type_BIT,
 		query.Type_ENUM, query.Type_SET, query.Type_TUPLE, query.Type_GEOMETRY, query.Type_JSON,
 		query.Type_HEXNUM, query.Type_HEXVAL, query.Type_BITNUM:
 
 		return typeRawBytes
-	case query.Type_DATE, query.Type_TIME, query.Type_DATETIME:
+	case query.Type_DATE, query.Type_DATETIME, query.Type_TIMESTAMP:
 		return typeTime
 	default:
 		return typeUnknown
@@ -121,7 +140,11 @@ func (ri *rows) ColumnTypeScanType(index int) reflect.Type {
 }
 
 func (ri *rows) ColumnTypeDatabaseTypeName(index int) string {
-	field := ri.qr.Fields[index]
+	return fieldDatabaseTypeName(ri.qr.Fields[index])
+}
+
+// fieldDatabaseTypeName returns the MySQL type name of field.
+func fieldDatabaseTypeName(field *query.Field) string {
 	switch field.GetType() {
 	case query.Type_INT8:
 		return "TINYINT"
@@ -193,6 +216,10 @@ func (ri *rows) ColumnTypeDatabaseTypeName(index int) string {
 }
 
 func (ri *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
-	field := ri.qr.Fields[index]
-	return field.GetFlags()&uint32(query.MySqlFlag_NOT_NULL_FLAG) == 0, true
+	return fieldNullable(ri.qr.Fields[index]), true
+}
+
+// fieldNullable returns true if field may be NULL.
+func fieldNullable(field *query.Field) bool {
+	return field.GetFlags()&uint32(query.MySqlFlag_NOT_NULL_FLAG) == 0
 }
@@ -138,6 +138,37 @@ func TestRows(t *testing.T) {
 	_ = ri.Close()
 }
 
+// Test that the ColumnTypeScanType function returns the type DECIMAL values are
+// converted to under each DecimalHandling, and that values scan into it.
+func TestColumnTypeScanTypeDecimal(t *testing.T) {
+	testcases := []struct {
+		handling DecimalHandling
+		want     reflect.Type
+	}{
+		{DecimalAsBytes, typeRawBytes},
+		{DecimalAsString, typeString},
+		{DecimalAsFloat64, typeFloat64},
+		{DecimalAsRat, typeRat},
+	}
+	for _, tc := range testcases {
+		t.Run(string(tc.handling), func(t *testing.T) {
+			r := sqltypes.Result{
+				Fields: []*querypb.Field{{Name: "amount", Type: sqltypes.Decimal}},
+				Rows:   [][]sqltypes.Value{{sqltypes.MakeTrusted(sqltypes.Decimal, []byte("12.50"))}},
+			}
+			ri := newRows(&r, &converter{decimal: tc.handling})
+			defer ri.Close()
+
+			scanType := ri.(driver.RowsColumnTypeScanType).ColumnTypeScanType(0)
+			assert.Equal(t, tc.want, scanType)
+
+			dest := make([]driver.Value, 1)
+			require.NoError(t, ri.Next(dest))
+			assert.True(t, reflect.TypeOf(dest[0]).ConvertibleTo(scanType), "value of type %T does not match the scan type %v", dest[0], scanType)
+		})
+	}
+}
+
 // Test that the ColumnTypeScanType function returns the correct reflection type for each
 // sql type. The sql type in turn comes from a table column's type.
 func TestColumnTypeScanType(t *testing.T) {
@@ -199,6 +230,14 @@ func TestColumnTypeScanType(t *testing.T) {
 				Name: "field14",
 				Type: sqltypes.Datetime,
 			},
+			{
+				Name: "field15",
+				Type: sqltypes.Timestamp,
+			},
+			{
+				Name: "field16",
+				Type: sqltypes.Time,
+			},
 		},
 	}
 
@@ -220,6 +259,8 @@ func TestColumnTypeScanType(t *testing.T) {
 		typeFloat64,
 		typeRawBytes,
 		typeTime,
+		typeTime,
+		typeRawBytes,
 	}
 
 	for i := 0; i < len(wantTypes); i++ {
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"fmt"
+
+	"vitess.io/vitess/go/sqlescape"
+)
+
+// Savepointer is implemented by the connections of this driver, which are
+// also their transactions. database/sql has no savepoint API, so it has to be
+// reached through sql.Conn.Raw.
+type Savepointer interface {
+	// Savepoint sets a savepoint with the given name in the current transaction.
+	Savepoint(name string) error
+	// RollbackTo rolls the current transaction back to the given savepoint.
+	RollbackTo(name string) error
+	// ReleaseSavepoint removes the given savepoint, keeping its changes.
+	ReleaseSavepoint(name string) error
+}
+
+func (c *conn) Savepoint(name string) error {
+	return c.execSavepoint("Savepoint", "savepoint "+sqlescape.EscapeID(name))
+}
+
+func (c *conn) RollbackTo(name string) error {
+	return c.execSavepoint("RollbackTo", "rollback to "+sqlescape.EscapeID(name))
+}
+
+func (c *conn) ReleaseSavepoint(name string) error {
+	return c.execSavepoint("ReleaseSavepoint", "release savepoint "+sqlescape.EscapeID(name))
+}
+
+func (c *conn) execSavepoint(method, query string) error {
+	if c.cfg.Streaming {
+		return fmt.Errorf("%s not allowed for streaming connections", method)
+	}
+	// like Commit and Rollback, only the original creator of the
+	// transaction can manage its savepoints.
+	if c.cfg.SessionToken != "" {
+		return fmt.Errorf("calling %s from a distributed tx is not allowed", method)
+	}
+	_, err := c.Exec(query, nil)
+	return err
+}
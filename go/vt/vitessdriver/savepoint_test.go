@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// savepointErrors calls all the Savepointer methods on a connection
+// opened with c and returns their errors.
+func savepointErrors(t *testing.T, c Configuration) []error {
+	db, err := OpenWithConfiguration(c)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	var errs []error
+	require.NoError(t, sconn.Raw(func(driverConn any) error {
+		sp := driverConn.(Savepointer)
+		errs = append(errs, sp.Savepoint("sp1"), sp.RollbackTo("sp1"), sp.ReleaseSavepoint("sp1"))
+		return nil
+	}))
+	return errs
+}
+
+func TestSavepoint(t *testing.T) {
+	errs := savepointErrors(t, Configuration{Address: testAddress, Target: "@primary"})
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	errs = savepointErrors(t, Configuration{Address: testAddress, Target: "@primary", Streaming: true})
+	assert.EqualError(t, errs[0], "Savepoint not allowed for streaming connections")
+	assert.EqualError(t, errs[1], "RollbackTo not allowed for streaming connections")
+	assert.EqualError(t, errs[2], "ReleaseSavepoint not allowed for streaming connections")
+
+	sessionToken, err := sessionToSessionToken(&vtgatepb.Session{TargetString: "@primary", InTransaction: true})
+	require.NoError(t, err)
+	errs = savepointErrors(t, Configuration{Address: testAddress, Target: "@primary", SessionToken: sessionToken})
+	assert.EqualError(t, errs[0], "calling Savepoint from a distributed tx is not allowed")
+	assert.EqualError(t, errs[1], "calling RollbackTo from a distributed tx is not allowed")
+	assert.EqualError(t, errs[2], "calling ReleaseSavepoint from a distributed tx is not allowed")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultSchemaVersionInterval is how often the schema version is polled
+// when SchemaVersionInterval is not set.
+const defaultSchemaVersionInterval = time.Minute
+
+// schemaVersionWatcher polls the schema version of a connector on its own
+// connection, and calls OnSchemaVersionChange when it changes.
+type schemaVersionWatcher struct {
+	connector *connector
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// version is only accessed by the polling goroutine.
+	version string
+}
+
+func newSchemaVersionWatcher(c *connector) *schemaVersionWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &schemaVersionWatcher{
+		connector: c,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+func (w *schemaVersionWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	var c *conn
+	defer func() {
+		if c != nil {
+			c.Close()
+		}
+	}()
+
+	interval := w.connector.cfg.SchemaVersionInterval
+	if interval <= 0 {
+		interval = defaultSchemaVersionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if c == nil {
+			driverConn, err := w.connector.Connect(ctx)
+			if err == nil {
+				c = driverConn.(*conn)
+			}
+		}
+		if c != nil {
+			if err := w.poll(ctx, c); err != nil {
+				// the connection may be broken, dial a new one next time.
+				c.Close()
+				c = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads the schema version and calls the callback if it changed. The
+// first version read is only recorded.
+func (w *schemaVersionWatcher) poll(ctx context.Context, c *conn) error {
+	version, err := c.readSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	old := w.version
+	w.version = version
+	if old != "" && old != version {
+		w.connector.cfg.OnSchemaVersionChange(old, version)
+	}
+	return nil
+}
+
+// Close stops the watcher and waits for it to exit.
+func (w *schemaVersionWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+// readSchemaVersion runs SchemaVersionQuery and returns the first column of
+// its first row.
+func (c *conn) readSchemaVersion(ctx context.Context) (string, error) {
+	qr, err := c.session.Execute(c.withApplicationName(ctx), c.cfg.SchemaVersionQuery, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) == 0 || len(qr.Rows[0]) == 0 {
+		return "", errors.New("schema version query returned no value")
+	}
+	return qr.Rows[0][0].ToString(), nil
+}
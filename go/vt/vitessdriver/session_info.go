@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SessionInfo is a read-only snapshot of the vtgate session of a connection.
+// Changing it has no effect on the connection.
+type SessionInfo struct {
+	// TargetString is the current target of the session, as changed by USE.
+	TargetString string
+	// InTransaction is true if the session has an open transaction.
+	InTransaction bool
+	// Autocommit is true if autocommit is enabled on the session.
+	Autocommit bool
+	// SessionUUID is the identifier vtgate assigned to the session, if any.
+	SessionUUID string
+	// LastSeenGTID is the GTID of the last write, if TrackGTIDs is set.
+	LastSeenGTID string
+}
+
+// SessionInspector returns a snapshot of the vtgate session a connection
+// carries between statements. GetSessionInfo and InTransaction take it from
+// a *sql.Conn.
+type SessionInspector interface {
+	SessionInfo() SessionInfo
+}
+
+// GetSessionInfo returns a snapshot of the vtgate session of the given connection.
+func GetSessionInfo(ctx context.Context, c *sql.Conn) (SessionInfo, error) {
+	var info SessionInfo
+	err := withConn(c, "session inspection", func(inspector SessionInspector) error {
+		info = inspector.SessionInfo()
+		return nil
+	})
+	return info, err
+}
+
+// InTransaction returns true if the vtgate session of the given connection
+// has an open transaction.
+func InTransaction(ctx context.Context, c *sql.Conn) (bool, error) {
+	info, err := GetSessionInfo(ctx, c)
+	return info.InTransaction, err
+}
+
+func (c *conn) SessionInfo() SessionInfo {
+	session := c.session.SessionPb()
+	return SessionInfo{
+		TargetString:  session.GetTargetString(),
+		InTransaction: session.GetInTransaction(),
+		Autocommit:    session.GetAutocommit(),
+		SessionUUID:   session.GetSessionUUID(),
+		LastSeenGTID:  c.lastSeenGTID,
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/grpcvtgateconn"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// validateSessionTokenQuery is sent to every shard of the transaction of a
+// session token by ValidateSessionToken.
+const validateSessionTokenQuery = "select 1 from dual"
+
+// TransactionEndedError is returned by ValidateSessionToken when the
+// transaction of the session token is no longer open on one of its shards,
+// for example because it was killed after exceeding the transaction timeout.
+type TransactionEndedError struct {
+	Keyspace string
+	Shard    string
+	Err      error
+}
+
+func (e *TransactionEndedError) Error() string {
+	return fmt.Sprintf("transaction of the session token has ended on %s/%s: %v", e.Keyspace, e.Shard, e.Err)
+}
+
+func (e *TransactionEndedError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateSessionToken checks that the transaction of c.SessionToken is still
+// open, without running any statement in the caller's session. It runs a
+// trivial query in the transaction on each of its shards, and returns a
+// *TransactionEndedError if one of them reports that the transaction ended.
+// Address and SessionToken are the minimum required on c.
+func ValidateSessionToken(ctx context.Context, c Configuration) error {
+	if c.SessionToken == "" {
+		return errors.New("c.SessionToken is required")
+	}
+
+	session, err := sessionTokenToSession(c.SessionToken)
+	if err != nil {
+		return err
+	}
+	if len(session.ShardSessions) == 0 {
+		return errors.New("there must be at least 1 ShardSession")
+	}
+
+	c.setDefaults()
+	if len(c.GRPCDialOptions) != 0 {
+		vtgateconn.RegisterDialer(c.Protocol, grpcvtgateconn.Dial(c.GRPCDialOptions...))
+	}
+	conn, err := vtgateconn.DialProtocol(ctx, c.Protocol, c.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, shardSession := range session.ShardSessions {
+		target := shardSession.GetTarget()
+		// target the shard directly, so that the query is sent to it within
+		// the transaction.
+		probe := session.CloneVT()
+		probe.TargetString = fmt.Sprintf("%s:%s@%s", target.GetKeyspace(), target.GetShard(), topoproto.TabletTypeLString(target.GetTabletType()))
+		_, err := conn.SessionFromPb(probe).Execute(ctx, validateSessionTokenQuery, nil)
+		if err == nil {
+			continue
+		}
+		if vterrors.Code(err) == vtrpcpb.Code_ABORTED {
+			return &TransactionEndedError{Keyspace: target.GetKeyspace(), Shard: target.GetShard(), Err: err}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestValidateSessionToken(t *testing.T) {
+	ctx := context.Background()
+
+	tokenWithTransactions := func(ids ...int64) string {
+		session := &vtgatepb.Session{InTransaction: true, TargetString: "@primary"}
+		for i, id := range ids {
+			session.ShardSessions = append(session.ShardSessions, &vtgatepb.Session_ShardSession{
+				Target: &querypb.Target{
+					Keyspace:   "ks",
+					Shard:      []string{"-80", "80-"}[i],
+					TabletType: topodatapb.TabletType_PRIMARY,
+				},
+				TransactionId: id,
+			})
+		}
+		token, err := sessionToSessionToken(session)
+		require.NoError(t, err)
+		return token
+	}
+
+	err := ValidateSessionToken(ctx, Configuration{Address: testAddress, SessionToken: tokenWithTransactions(1, 2)})
+	assert.NoError(t, err)
+
+	err = ValidateSessionToken(ctx, Configuration{Address: testAddress, SessionToken: tokenWithTransactions(1, reapedTransactionID)})
+	var ended *TransactionEndedError
+	require.ErrorAs(t, err, &ended)
+	assert.Equal(t, "ks", ended.Keyspace)
+	assert.Equal(t, "80-", ended.Shard)
+	assert.ErrorContains(t, err, "transaction of the session token has ended on ks/80-")
+
+	err = ValidateSessionToken(ctx, Configuration{Address: testAddress, SessionToken: tokenWithTransactions()})
+	assert.EqualError(t, err, "there must be at least 1 ShardSession")
+
+	err = ValidateSessionToken(ctx, Configuration{Address: testAddress})
+	assert.EqualError(t, err, "c.SessionToken is required")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// sqlError converts an error returned by vtgate into a *sqlerror.SQLError
+// if SQLErrors is set, and returns it unchanged otherwise.
+func (c *conn) sqlError(err error) error {
+	if err == nil || !c.cfg.SQLErrors {
+		return err
+	}
+	return sqlerror.NewSQLErrorFromError(err)
+}
+
+// sqlErrorStream converts the errors of a result stream into
+// *sqlerror.SQLError, leaving io.EOF alone.
+type sqlErrorStream struct {
+	sqltypes.ResultStream
+}
+
+func (s sqlErrorStream) Recv() (*sqltypes.Result, error) {
+	qr, err := s.ResultStream.Recv()
+	if err != nil && err != io.EOF {
+		err = sqlerror.NewSQLErrorFromError(err)
+	}
+	return qr, err
+}
+
+// streamExecute runs the query with StreamExecute, converting its errors
+// if SQLErrors is set. cancel releases ctx, and is called when the returned
+// rows are closed.
+func (c *conn) streamExecute(ctx context.Context, cancel context.CancelFunc, query string, bindVars map[string]*querypb.BindVariable) (driver.Rows, error) {
+	stream, err := c.session.StreamExecute(ctx, query, bindVars)
+	if err != nil {
+		cancel()
+		return nil, c.sqlError(err)
+	}
+	if c.cfg.SQLErrors {
+		stream = sqlErrorStream{stream}
+	}
+	rows := newStreamingRows(stream, c.convert).(*streamingRows)
+	rows.cancel = cancel
+	return rows, nil
+}
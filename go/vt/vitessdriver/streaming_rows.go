@@ -17,8 +17,10 @@ limitations under the License.
 package vitessdriver
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
+	"reflect"
 
 	"vitess.io/vitess/go/sqltypes"
 
@@ -34,6 +36,8 @@ type streamingRows struct {
 	qr      *sqltypes.Result
 	index   int
 	convert *converter
+	// cancel, if set, releases the context of the stream.
+	cancel context.CancelFunc
 }
 
 // newStreamingRows creates a new streamingRows from stream.
@@ -60,6 +64,9 @@ func (ri *streamingRows) Columns() []string {
 }
 
 func (ri *streamingRows) Close() error {
+	if ri.cancel != nil {
+		ri.cancel()
+	}
 	return nil
 }
 
@@ -87,6 +94,39 @@ func (ri *streamingRows) Next(dest []driver.Value) error {
 	return nil
 }
 
+// field returns the field of the column at index, fetching the fields first
+// if needed. It returns nil if they cannot be fetched.
+func (ri *streamingRows) field(index int) *querypb.Field {
+	if ri.failed != nil {
+		return nil
+	}
+	if err := ri.checkFields(); err != nil {
+		_ = ri.setErr(err)
+		return nil
+	}
+	if index < 0 || index >= len(ri.fields) {
+		return nil
+	}
+	return ri.fields[index]
+}
+
+// Implements the RowsColumnTypeScanType interface
+func (ri *streamingRows) ColumnTypeScanType(index int) reflect.Type {
+	return fieldScanType(ri.field(index), ri.convert.decimal)
+}
+
+func (ri *streamingRows) ColumnTypeDatabaseTypeName(index int) string {
+	return fieldDatabaseTypeName(ri.field(index))
+}
+
+func (ri *streamingRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	field := ri.field(index)
+	if field == nil {
+		return false, false
+	}
+	return fieldNullable(field), true
+}
+
 // checkFields fetches the first packet from the channel, which
 // should contain the field info.
 func (ri *streamingRows) checkFields() error {
@@ -232,3 +232,40 @@ func TestStreamingRowsError(t *testing.T) {
 	}
 	_ = ri.Close()
 }
+
+func TestStreamingRowsColumnTypes(t *testing.T) {
+	c := make(chan *sqltypes.Result, 1)
+	c <- &packet1
+	close(c)
+	ri := newStreamingRows(&adapter{c: c, err: io.EOF}, &converter{})
+	defer ri.Close()
+
+	// the fields are fetched by the first call, whichever it is.
+	scanTypes := ri.(driver.RowsColumnTypeScanType)
+	require.Equal(t, typeInt32, scanTypes.ColumnTypeScanType(0))
+	require.Equal(t, typeFloat32, scanTypes.ColumnTypeScanType(1))
+	require.Equal(t, typeRawBytes, scanTypes.ColumnTypeScanType(2))
+	require.Equal(t, typeUnknown, scanTypes.ColumnTypeScanType(3))
+
+	typeNames := ri.(driver.RowsColumnTypeDatabaseTypeName)
+	require.Equal(t, "INT", typeNames.ColumnTypeDatabaseTypeName(0))
+	require.Equal(t, "FLOAT", typeNames.ColumnTypeDatabaseTypeName(1))
+	require.Equal(t, "VARCHAR", typeNames.ColumnTypeDatabaseTypeName(2))
+
+	nullable, ok := ri.(driver.RowsColumnTypeNullable).ColumnTypeNullable(0)
+	require.True(t, ok)
+	require.True(t, nullable)
+}
+
+func TestStreamingRowsColumnTypesError(t *testing.T) {
+	c := make(chan *sqltypes.Result)
+	close(c)
+	ri := newStreamingRows(&adapter{c: c, err: errors.New("error before fields")}, &converter{})
+	defer ri.Close()
+
+	require.Equal(t, typeUnknown, ri.(driver.RowsColumnTypeScanType).ColumnTypeScanType(0))
+	require.Equal(t, "", ri.(driver.RowsColumnTypeDatabaseTypeName).ColumnTypeDatabaseTypeName(0))
+	_, ok := ri.(driver.RowsColumnTypeNullable).ColumnTypeNullable(0)
+	require.False(t, ok)
+	require.EqualError(t, ri.Next(make([]driver.Value, 1)), "error before fields")
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// Querier is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Keyspace is a keyspace known to vtgate, with its shards sorted by name.
+type Keyspace struct {
+	Name   string
+	Shards []Shard
+}
+
+// Shard is a shard known to vtgate. ServedTypes holds the types of the
+// tablets that are serving it, sorted and without duplicates.
+type Shard struct {
+	Name        string
+	ServedTypes []topodatapb.TabletType
+}
+
+// ListKeyspaces returns the keyspaces and shards of the tablets vtgate knows
+// about, sorted by name, as reported by SHOW VITESS_TABLETS. It is meant to
+// be used by tools that let users pick a target.
+func ListKeyspaces(ctx context.Context, q Querier) ([]Keyspace, error) {
+	rows, err := q.QueryContext(ctx, "show vitess_tablets")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keyspaces []Keyspace
+	for rows.Next() {
+		var cell, keyspace, shard, tabletType, state, alias, hostname, primaryTermStartTime string
+		if err := rows.Scan(&cell, &keyspace, &shard, &tabletType, &state, &alias, &hostname, &primaryTermStartTime); err != nil {
+			return nil, err
+		}
+
+		i, found := slices.BinarySearchFunc(keyspaces, keyspace, func(ks Keyspace, name string) int {
+			return strings.Compare(ks.Name, name)
+		})
+		if !found {
+			keyspaces = slices.Insert(keyspaces, i, Keyspace{Name: keyspace})
+		}
+		ks := &keyspaces[i]
+
+		j, found := slices.BinarySearchFunc(ks.Shards, shard, func(s Shard, name string) int {
+			return strings.Compare(s.Name, name)
+		})
+		if !found {
+			ks.Shards = slices.Insert(ks.Shards, j, Shard{Name: shard})
+		}
+		if state != "SERVING" {
+			continue
+		}
+
+		servedType, err := topoproto.ParseTabletType(tabletType)
+		if err != nil {
+			return nil, err
+		}
+		sh := &ks.Shards[j]
+		if k, found := slices.BinarySearch(sh.ServedTypes, servedType); !found {
+			sh.ServedTypes = slices.Insert(sh.ServedTypes, k, servedType)
+		}
+	}
+	return keyspaces, rows.Err()
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestListKeyspaces(t *testing.T) {
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	keyspaces, err := ListKeyspaces(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, []Keyspace{
+		{
+			Name: "ks1",
+			Shards: []Shard{
+				{Name: "-80", ServedTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA}},
+				{Name: "80-", ServedTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}},
+			},
+		},
+		{
+			Name: "ks2",
+			Shards: []Shard{
+				{Name: "0", ServedTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}},
+			},
+		},
+	}, keyspaces)
+}
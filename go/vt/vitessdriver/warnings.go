@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WarningsReader gives access to the warnings vtgate attached to the session
+// of the last statement, which database/sql has no way to surface. Use the
+// Warnings function to read them from a *sql.Conn.
+type WarningsReader interface {
+	// Warnings returns the warnings vtgate reported for the last statement
+	// executed on the connection, formatted as "<code>: <message>".
+	Warnings() []string
+}
+
+// Warnings returns the warnings vtgate reported for the last statement
+// executed on the given connection.
+func Warnings(ctx context.Context, c *sql.Conn) ([]string, error) {
+	var warnings []string
+	err := withConn(c, "reading warnings", func(reader WarningsReader) error {
+		warnings = reader.Warnings()
+		return nil
+	})
+	return warnings, err
+}
+
+// Warnings returns the warnings of the session returned by vtgate on the last
+// round trip. vtgate clears them at the start of every statement but SHOW
+// ones, so they belong to the last statement.
+func (c *conn) Warnings() []string {
+	var warnings []string
+	for _, w := range c.session.SessionPb().GetWarnings() {
+		warnings = append(warnings, fmt.Sprintf("%d: %s", w.Code, w.Message))
+	}
+	return warnings
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	db, err := Open(testAddress, "@rdonly")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	sconn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer sconn.Close()
+
+	warnings, err := Warnings(ctx, sconn)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	_, err = sconn.ExecContext(ctx, warningQuery)
+	require.NoError(t, err)
+	warnings, err = Warnings(ctx, sconn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1265: Data truncated for column 'name' at row 1"}, warnings)
+
+	// the warnings only belong to the statement that raised them.
+	var component string
+	require.NoError(t, sconn.QueryRowContext(ctx, callerComponentQuery).Scan(&component))
+	warnings, err = Warnings(ctx, sconn)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
@@ -4598,6 +4598,112 @@ func TestEmergencyReparentShard(t *testing.T) {
 	}
 }
 
+func TestEmergencyReparentShardAllowNonServing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		allowNonServing bool
+		expectedErr     string
+	}{
+		{
+			name:        "non-serving shard is refused by default",
+			expectedErr: "primary of shard testkeyspace/- is not serving",
+		},
+		{
+			name:            "non-serving shard is reparented when allowed",
+			allowNonServing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary:  true,
+				ForceSetShardPrimary: true,
+			}, &topodatapb.Tablet{
+				Alias:                &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				Type:                 topodatapb.TabletType_PRIMARY,
+				PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+				Keyspace:             "testkeyspace",
+				Shard:                "-",
+			}, &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+				Type:     topodatapb.TabletType_REPLICA,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			})
+			// the shard is the target of a reshard that is not serving yet
+			_, err := ts.UpdateShardFields(ctx, "testkeyspace", "-", func(si *topo.ShardInfo) error {
+				si.IsPrimaryServing = false
+				return nil
+			})
+			require.NoError(t, err)
+
+			tmc := &testutil.TabletManagerClient{
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000200": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000200": {},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				StopReplicationAndGetStatusResults: map[string]struct {
+					StopStatus *replicationdatapb.StopReplicationStatus
+					Error      error
+				}{
+					"zone1-0000000100": {
+						Error: mysql.ErrNotReplica,
+					},
+					"zone1-0000000200": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+								Position:         "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+							},
+						},
+					},
+				},
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000200": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5": nil,
+					},
+				},
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.EmergencyReparentShard(ctx, &vtctldatapb.EmergencyReparentShardRequest{
+				Keyspace:            "testkeyspace",
+				Shard:               "-",
+				WaitReplicasTimeout: protoutil.DurationToProto(time.Millisecond * 10),
+				AllowNonServing:     tt.allowNonServing,
+			})
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "zone1-0000000200", topoproto.TabletAliasString(resp.PromotedPrimary))
+		})
+	}
+}
+
 func TestExecuteFetchAsApp(t *testing.T) {
 	t.Parallel()
 
@@ -171,6 +171,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 	preventCrossCellPromotion := subFlags.Bool("prevent_cross_cell_promotion", false, "only promotes a new primary from the same cell as the previous primary")
 	ignoreReplicasList := subFlags.String("ignore_replicas", "", "comma-separated list of replica tablet aliases to ignore during emergency reparent")
 	waitForAllTablets := subFlags.Bool("wait_for_all_tablets", false, "should ERS wait for all the tablets to respond. Useful when all the tablets are reachable")
+	allowNonServing := subFlags.Bool("allow_non_serving", false, "allow reparenting a shard whose primary is not serving, like the target shards of a reshard")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -204,6 +205,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 		WaitReplicasTimeout:       *waitReplicasTimeout,
 		IgnoreReplicas:            topoproto.ParseTabletSet(*ignoreReplicasList),
 		PreventCrossCellPromotion: *preventCrossCellPromotion,
+		AllowNonServing:           *allowNonServing,
 	})
 }
 
@@ -0,0 +1,297 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"encoding/json"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ElectionDecision records the input and the outcome of the election of a new
+// primary by EmergencyReparentShard. It is written as JSON to the DecisionSink
+// of the options, the protobuf messages in their protojson form, and can be
+// given back to ReplayElectionDecision to check that the same tablets would be
+// chosen again.
+type ElectionDecision struct {
+	Keyspace string
+	Shard    string
+
+	// Durability is the name of the durability policy of the keyspace, and
+	// PromotionRules the promotion rule it gives to each tablet.
+	Durability     string
+	PromotionRules map[string]string
+
+	// The options that change which tablet is chosen. The candidates can also
+	// be reordered by CandidateScorer and vetoed by VetoCandidate, which are
+	// callbacks and cannot be replayed.
+	NewPrimaryAlias           string
+	PreferRelayLogReceived    bool
+	RequireGTIDMode           bool
+	PreventCrossCellPromotion bool
+	AvoidPrimaryAliases       []string
+	PreferDirectReplicas      bool
+	ExcludeIOErrored          bool
+	PreferTabletTag           string
+	PreferTabletTagValue      string
+
+	// The tablets of the shard, the previous primary, and the statuses gathered
+	// from the tablets, the reachable ones, when their replication was stopped.
+	Tablets          map[string]*topodatapb.Tablet
+	PreviousPrimary  string
+	StatusMap        map[string]*replicationdatapb.StopReplicationStatus
+	PrimaryStatusMap map[string]*replicationdatapb.PrimaryStatus
+	ReachableTablets []string
+
+	// ErrantGTIDTablets holds the tablets left out for having errant GTIDs, and
+	// Laggards the ones left out for not applying their relay logs in time when
+	// RelayLogApplyQuorum is set.
+	ErrantGTIDTablets []string
+	Laggards          []string
+	// Candidates holds the valid candidates, most advanced first, and
+	// IntermediateSource the one that was chosen first.
+	Candidates         []string
+	IntermediateSource string
+	// NewPrimary is the tablet that was promoted, or would have been by a dry run.
+	NewPrimary string
+	// Error is set if the reparent failed.
+	Error string
+}
+
+func newElectionDecision(keyspace, shard string, opts EmergencyReparentOptions) *ElectionDecision {
+	decision := &ElectionDecision{
+		Keyspace:                  keyspace,
+		Shard:                     shard,
+		PreferRelayLogReceived:    opts.PreferRelayLogReceived,
+		RequireGTIDMode:           opts.RequireGTIDMode,
+		PreventCrossCellPromotion: opts.PreventCrossCellPromotion,
+		PreferDirectReplicas:      opts.PreferDirectReplicas,
+		ExcludeIOErrored:          opts.ExcludeIOErrored,
+		PreferTabletTag:           opts.PreferTabletTag,
+		PreferTabletTagValue:      opts.PreferTabletTagValue,
+	}
+	if opts.AvoidPrimaryAliases.Len() > 0 {
+		decision.AvoidPrimaryAliases = sets.List(opts.AvoidPrimaryAliases)
+	}
+	if opts.NewPrimaryAlias != nil {
+		decision.NewPrimaryAlias = topoproto.TabletAliasString(opts.NewPrimaryAlias)
+	}
+	return decision
+}
+
+// electionDecisionJSON is the JSON form of an ElectionDecision, in which the
+// protobuf messages are marshalled with protojson.
+type electionDecisionJSON struct {
+	*plainElectionDecision
+
+	Tablets          map[string]json.RawMessage
+	StatusMap        map[string]json.RawMessage
+	PrimaryStatusMap map[string]json.RawMessage
+}
+
+// plainElectionDecision has the fields of ElectionDecision, but not its JSON methods.
+type plainElectionDecision ElectionDecision
+
+// MarshalJSON implements json.Marshaler.
+func (d *ElectionDecision) MarshalJSON() ([]byte, error) {
+	var (
+		out = electionDecisionJSON{plainElectionDecision: (*plainElectionDecision)(d)}
+		err error
+	)
+	if out.Tablets, err = marshalProtoMap(d.Tablets); err != nil {
+		return nil, err
+	}
+	if out.StatusMap, err = marshalProtoMap(d.StatusMap); err != nil {
+		return nil, err
+	}
+	if out.PrimaryStatusMap, err = marshalProtoMap(d.PrimaryStatusMap); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *ElectionDecision) UnmarshalJSON(data []byte) error {
+	in := electionDecisionJSON{plainElectionDecision: (*plainElectionDecision)(d)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	var err error
+	if d.Tablets, err = unmarshalProtoMap[topodatapb.Tablet](in.Tablets); err != nil {
+		return err
+	}
+	if d.StatusMap, err = unmarshalProtoMap[replicationdatapb.StopReplicationStatus](in.StatusMap); err != nil {
+		return err
+	}
+	if d.PrimaryStatusMap, err = unmarshalProtoMap[replicationdatapb.PrimaryStatus](in.PrimaryStatusMap); err != nil {
+		return err
+	}
+	return nil
+}
+
+func marshalProtoMap[M proto.Message](m map[string]M) (map[string]json.RawMessage, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]json.RawMessage, len(m))
+	for key, msg := range m {
+		data, err := json2.MarshalPB(msg)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = data
+	}
+	return out, nil
+}
+
+func unmarshalProtoMap[T any, M interface {
+	*T
+	proto.Message
+}](in map[string]json.RawMessage) (map[string]M, error) {
+	if in == nil {
+		return nil, nil
+	}
+	m := make(map[string]M, len(in))
+	for key, data := range in {
+		msg := M(new(T))
+		if err := json2.UnmarshalPB(data, msg); err != nil {
+			return nil, err
+		}
+		m[key] = msg
+	}
+	return m, nil
+}
+
+// setTablets records the tablets of the shard and their promotion rules.
+func (d *ElectionDecision) setTablets(tabletMap map[string]*topo.TabletInfo, durability Durabler) {
+	d.Tablets = make(map[string]*topodatapb.Tablet, len(tabletMap))
+	d.PromotionRules = make(map[string]string, len(tabletMap))
+	for alias, tabletInfo := range tabletMap {
+		d.Tablets[alias] = tabletInfo.Tablet
+		d.PromotionRules[alias] = string(PromotionRule(durability, tabletInfo.Tablet))
+	}
+}
+
+// setStatuses records the statuses gathered when replication was stopped, and
+// the tablets they were gathered from.
+func (d *ElectionDecision) setStatuses(statusMap map[string]*replicationdatapb.StopReplicationStatus, primaryStatusMap map[string]*replicationdatapb.PrimaryStatus, reachableTablets []*topodatapb.Tablet) {
+	d.StatusMap = statusMap
+	d.PrimaryStatusMap = primaryStatusMap
+	d.ReachableTablets = make([]string, 0, len(reachableTablets))
+	for _, tablet := range reachableTablets {
+		d.ReachableTablets = append(d.ReachableTablets, topoproto.TabletAliasString(tablet.Alias))
+	}
+	slices.Sort(d.ReachableTablets)
+}
+
+// setValidCandidates records the tablets of the status map that are not valid
+// candidates, which are the ones with errant GTIDs.
+func (d *ElectionDecision) setValidCandidates(validCandidates map[string]replication.Position) {
+	d.ErrantGTIDTablets = nil
+	for alias := range d.StatusMap {
+		if _, ok := validCandidates[alias]; !ok {
+			d.ErrantGTIDTablets = append(d.ErrantGTIDTablets, alias)
+		}
+	}
+	slices.Sort(d.ErrantGTIDTablets)
+}
+
+// setMostAdvanced records the outcome of findMostAdvanced.
+func (d *ElectionDecision) setMostAdvanced(intermediateSource *topodatapb.Tablet, candidates []*topodatapb.Tablet) {
+	d.IntermediateSource = topoproto.TabletAliasString(intermediateSource.Alias)
+	d.Candidates = make([]string, 0, len(candidates))
+	for _, tablet := range candidates {
+		d.Candidates = append(d.Candidates, topoproto.TabletAliasString(tablet.Alias))
+	}
+}
+
+// writeDecision writes the decision to the DecisionSink of the options, if any.
+// Failing to do so does not fail the reparent.
+func writeDecision(logger logutil.Logger, decision *ElectionDecision, opts EmergencyReparentOptions, err error) {
+	if opts.DecisionSink == nil {
+		return
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	if err := json.NewEncoder(opts.DecisionSink).Encode(decision); err != nil {
+		logger.Warningf("failed to write the emergency reparent decision: %v", err)
+	}
+}
+
+// ReplayElectionDecision runs the election of EmergencyReparentShard again on the
+// input recorded in the given decision, leaving out the recorded laggards, and
+// returns a copy of it with the candidates, the intermediate source and the new
+// primary it chooses, which can be compared with the recorded ones. The direct
+// replicas of the previous primary and the tablets whose IO thread was erroring
+// are found again from the recorded statuses. Like ChooseNewPrimary, it assumes
+// every valid candidate could catch up with the intermediate source.
+func ReplayElectionDecision(decision *ElectionDecision, logger logutil.Logger) (*ElectionDecision, error) {
+	durability, err := GetDurabilityPolicy(decision.Durability)
+	if err != nil {
+		return nil, err
+	}
+	opts := EmergencyReparentOptions{
+		PreferRelayLogReceived:    decision.PreferRelayLogReceived,
+		RequireGTIDMode:           decision.RequireGTIDMode,
+		PreventCrossCellPromotion: decision.PreventCrossCellPromotion,
+		AvoidPrimaryAliases:       sets.New(decision.AvoidPrimaryAliases...),
+		PreferDirectReplicas:      decision.PreferDirectReplicas,
+		ExcludeIOErrored:          decision.ExcludeIOErrored,
+		PreferTabletTag:           decision.PreferTabletTag,
+		PreferTabletTagValue:      decision.PreferTabletTagValue,
+		durability:                durability,
+	}
+	if decision.NewPrimaryAlias != "" {
+		opts.NewPrimaryAlias, err = topoproto.ParseTabletAlias(decision.NewPrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tabletMap := make(map[string]*topo.TabletInfo, len(decision.Tablets))
+	for alias, tablet := range decision.Tablets {
+		tabletMap[alias] = &topo.TabletInfo{Tablet: tablet}
+	}
+	reachableTablets := make([]*topodatapb.Tablet, 0, len(decision.ReachableTablets))
+	for _, alias := range decision.ReachableTablets {
+		if tablet, ok := decision.Tablets[alias]; ok {
+			reachableTablets = append(reachableTablets, tablet)
+		}
+	}
+
+	erp := NewEmergencyReparenter(nil, nil, logger)
+	intermediateSource, candidates, newPrimary, err := erp.elect(decision.StatusMap, decision.PrimaryStatusMap, tabletMap, reachableTablets, decision.Tablets[decision.PreviousPrimary], decision.Laggards, opts)
+	if err != nil {
+		return nil, err
+	}
+	replayed := *decision
+	replayed.setMostAdvanced(intermediateSource, candidates)
+	replayed.NewPrimary = topoproto.TabletAliasString(newPrimary.Alias)
+	replayed.Error = ""
+	return &replayed, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"slices"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools/events"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// dryRunTabletManagerClient is the TabletManagerClient used to gather the
+// replication status of the tablets during a dry run. It reads the status
+// of the tablets instead of stopping their replication or demoting them.
+type dryRunTabletManagerClient struct {
+	tmclient.TabletManagerClient
+}
+
+// StopReplicationAndGetStatus returns the current replication status of the
+// tablet, without stopping replication.
+func (c dryRunTabletManagerClient) StopReplicationAndGetStatus(ctx context.Context, tablet *topodatapb.Tablet, _ replicationdatapb.StopReplicationMode) (*replicationdatapb.StopReplicationStatus, error) {
+	status, err := c.ReplicationStatus(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	return &replicationdatapb.StopReplicationStatus{Before: status, After: status}, nil
+}
+
+// DemotePrimary returns the current primary status of the tablet, without
+// demoting it.
+func (c dryRunTabletManagerClient) DemotePrimary(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.PrimaryStatus, error) {
+	return c.PrimaryStatus(ctx, tablet)
+}
+
+// planDryRun records on the event the tablet ERS would promote, and the valid
+// candidates it would choose from, in the order they were ranked. A real run
+// would only choose among the candidates that managed to replicate from the
+// intermediate source, the plan assumes they all would.
+//
+// It also predicts which of the tablets ERS would point at the new primary
+// would succeed in doing so: the ones that were reachable when their status
+// was gathered, without contacting them again.
+func (erp *EmergencyReparenter) planDryRun(
+	ev *events.Reparent,
+	plannedPrimary *topodatapb.Tablet,
+	validCandidateTablets []*topodatapb.Tablet,
+	validCandidates map[string]replication.Position,
+	tabletMap map[string]*topo.TabletInfo,
+	reachableTablets []*topodatapb.Tablet,
+	opts EmergencyReparentOptions,
+) {
+	ev.PlannedPrimary = plannedPrimary.CloneVT()
+	ev.Candidates = make([]events.ReparentCandidate, 0, len(validCandidateTablets))
+	for _, tablet := range validCandidateTablets {
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		ev.Candidates = append(ev.Candidates, events.ReparentCandidate{
+			Alias:    alias,
+			Position: replication.EncodePosition(validCandidates[alias]),
+		})
+	}
+
+	reachable := make(map[string]bool, len(reachableTablets))
+	for _, tablet := range reachableTablets {
+		reachable[topoproto.TabletAliasString(tablet.Alias)] = true
+	}
+	ev.PredictedReplicas, ev.PredictedFailedReplicas = nil, nil
+	for alias, ti := range tabletMap {
+		switch {
+		case topoproto.TabletAliasEqual(ti.Alias, plannedPrimary.Alias):
+		case opts.IgnoreReplicas.Has(alias), skipReattach(ti.Tablet, opts):
+		case reachable[alias]:
+			ev.PredictedReplicas = append(ev.PredictedReplicas, alias)
+		default:
+			ev.PredictedFailedReplicas = append(ev.PredictedFailedReplicas, alias)
+		}
+	}
+	slices.Sort(ev.PredictedReplicas)
+	slices.Sort(ev.PredictedFailedReplicas)
+
+	erp.logger.Infof("dry run: would promote %v, with %d replica(s) expected to follow it and %d expected to fail",
+		topoproto.TabletAliasString(plannedPrimary.Alias), len(ev.PredictedReplicas), len(ev.PredictedFailedReplicas))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package reparentutil
 
 import (
+	"slices"
+
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
@@ -35,6 +37,20 @@ func SemiSyncAckersForPrimary(durability Durabler, primary *topodatapb.Tablet, a
 	return
 }
 
+// semiSyncAckersReached returns the sorted aliases of the reached tablets that can send semi-sync acks to the given primary,
+// which are the tablets its ability to make forward progress depends on. It returns nil if the primary does not need any acks.
+func semiSyncAckersReached(durability Durabler, primary *topodatapb.Tablet, tabletsReached []*topodatapb.Tablet) []string {
+	if SemiSyncAckers(durability, primary) == 0 {
+		return nil
+	}
+	var ackers []string
+	for _, tablet := range SemiSyncAckersForPrimary(durability, primary, tabletsReached) {
+		ackers = append(ackers, topoproto.TabletAliasString(tablet.Alias))
+	}
+	slices.Sort(ackers)
+	return ackers
+}
+
 // haveRevokedForTablet checks whether we have reached enough tablets such that the given primary eligible tablet cannot accept any new writes
 // The tablets reached should have their replication stopped and must be set to read only.
 func haveRevokedForTablet(durability Durabler, primaryEligible *topodatapb.Tablet, tabletsReached []*topodatapb.Tablet, allTablets []*topodatapb.Tablet) bool {
@@ -19,9 +19,14 @@ package reparentutil
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	"vitess.io/vitess/go/mysql/replication"
 
 	"vitess.io/vitess/go/event"
@@ -55,21 +60,155 @@ type EmergencyReparenter struct {
 type EmergencyReparentOptions struct {
 	NewPrimaryAlias *topodatapb.TabletAlias
 	IgnoreReplicas  sets.Set[string]
+	// AvoidPrimaryAliases lists the tablets that must never be promoted, even if
+	// they are the most advanced. They are still reparented like any replica.
+	AvoidPrimaryAliases sets.Set[string]
 	// WaitAllTablets is used to specify whether ERS should wait for all the tablets to return and not proceed
 	// further after n-1 tablets have returned.
 	WaitAllTablets            bool
 	WaitReplicasTimeout       time.Duration
 	PreventCrossCellPromotion bool
+	// PreferDirectReplicas is used to break ties between equally advanced candidates
+	// in favour of the tablets that were replicating directly from the previous primary.
+	PreferDirectReplicas bool
+	// AllowNonServing allows reparenting a shard whose primary is not serving,
+	// for example the target shards of an in-progress reshard. By default such
+	// shards are refused.
+	AllowNonServing bool
+	// RequireGTIDMode refuses to promote any candidate that is not replicating
+	// with GTIDs, for shards that are migrating away from file based replication.
+	RequireGTIDMode bool
+	// ReparentBatchSize limits how many replicas are pointed at the new primary at
+	// the same time. Replicas are reparented in waves of this size, each wave
+	// starting once the previous one is done, all of them within a single
+	// WaitReplicasTimeout. Zero means all at once. Unlike a reparent of all the
+	// replicas at once, which returns as soon as one of them is replicating from
+	// the new primary, ERS waits for all the waves while the shard is locked.
+	ReparentBatchSize int
+	// VerifySemiSyncEnabled checks that the new primary reports semi-sync as
+	// enabled after its promotion, when the durability policy requires semi-sync
+	// ackers for it.
+	VerifySemiSyncEnabled bool
+	// ExcludeIOErrored excludes the tablets whose IO thread was reporting an error
+	// before replication was stopped from the promotion candidates, unless there
+	// is no other candidate.
+	ExcludeIOErrored bool
+	// VerifyReplicasConverging samples the position of the reparented replicas
+	// once the reparent is done, and reports the ones that are neither caught up
+	// with the new primary nor making progress as warnings on the event.
+	VerifyReplicasConverging bool
+	// VetoCandidate, if set, is called with the tablet chosen for promotion before
+	// it is promoted. If it returns an error, the tablet is removed from the
+	// candidates and the next best one is chosen instead. It may be called more
+	// than once for the same tablet.
+	VetoCandidate func(candidate *topodatapb.Tablet) error
+	// AbortOnConcurrentReparent periodically checks, while waiting on the
+	// replicas, that the primary term of the shard is still the one ERS started
+	// with, and aborts if another reparent has changed it in the meantime.
+	// This includes pointing the replicas at the new primary, once its own
+	// promotion may have updated the term.
+	AbortOnConcurrentReparent bool
+	// OperationSource tells what initiated the reparent, e.g. "auto" or "manual".
+	// It is used as the Source label of the EmergencyReparentCountsBySource
+	// counters, where an empty value is reported as "unknown".
+	OperationSource string
+	// PreferRelayLogReceived compares the candidates on everything they have
+	// received in their relay logs, even if they have not executed it yet, for
+	// the replication flavors where the executed position would be used
+	// otherwise. Relay logs are always applied before promotion.
+	PreferRelayLogReceived bool
+	// DryRun stops once the tablet to promote has been chosen, and reports it
+	// as the PlannedPrimary of the event along with the ranked Candidates, and
+	// the replicas that are expected to follow it or not. The
+	// shard is still locked, but neither the shard record nor any tablet is
+	// changed: the replication status of the tablets is read without stopping
+	// their replication, and relay logs are not waited on. Dry runs are not
+	// counted in the EmergencyReparentCounts stats.
+	DryRun bool
+	// BailIfHealthy probes the current primary of the shard before doing
+	// anything, and returns without locking or changing the shard if it is a
+	// writable primary, setting NoOpReason on the returned event. It has no
+	// effect when NewPrimaryAlias requests another tablet.
+	BailIfHealthy bool
+	// DecisionSink, if set, receives the ElectionDecision of the reparent as
+	// JSON once it is over, whether it succeeded or not, for later analysis.
+	DecisionSink io.Writer
+	// RelayLogApplyQuorum, if non-zero, lets ERS go on as soon as this many
+	// candidates have applied their relay logs, within WaitReplicasTimeout. The
+	// others are removed from the candidates, and are reported as warnings on
+	// the event, unless one of them has received transactions the most advanced
+	// remaining candidate has not, in which case ERS fails. By default all the
+	// candidates must apply their relay logs.
+	RelayLogApplyQuorum int
+	// CandidateScorer, if set, breaks the ties between candidates that are
+	// equally advanced and have the same promotion rule: the ones with the
+	// highest score are preferred. It can never make a candidate that is behind
+	// the most advanced position win.
+	CandidateScorer func(tablet *topodatapb.Tablet, pos replication.Position) int
+	// StopReplicationRetries is how many more times the replication of a tablet
+	// is attempted to be stopped when it fails, waiting StopReplicationRetryDelay
+	// between attempts, before the tablet is counted as unreachable. All the
+	// attempts must fit in the time given to stop replication on the tablets.
+	StopReplicationRetries    int
+	StopReplicationRetryDelay time.Duration
+	// RequireDemotedPrimaryPosition excludes from the candidates the tablets that
+	// claim to be a primary when their replication is stopped, but do not return
+	// a readable position once demoted, rather than trusting them.
+	RequireDemotedPrimaryPosition bool
+	// SkipReplicaTypes lists the types of the tablets that are not pointed at
+	// the new primary once it is promoted, e.g. RDONLY, to return sooner. They
+	// are still considered for promotion, and are reported as SkippedReplicas
+	// on the event so that they can be repaired later.
+	SkipReplicaTypes []topodatapb.TabletType
+	// PreferTabletTag and PreferTabletTagValue, if PreferTabletTag is set, break
+	// the ties between the candidates that are equally advanced and have the
+	// same promotion rule in favour of the tablets whose tag PreferTabletTag is
+	// PreferTabletTagValue, e.g. a "tier" tag of "high".
+	PreferTabletTag      string
+	PreferTabletTagValue string
+	// OnPrimaryPromoted, if set, is called once the new primary is promoted and
+	// its reparent journal populated, but before any replica is pointed at it,
+	// e.g. to update service discovery. The shard is still locked when it runs.
+	// If it returns an error, ERS aborts without reparenting the replicas.
+	OnPrimaryPromoted func(ctx context.Context, newPrimary *topodatapb.Tablet) error
+	// OverallTimeout, if non-zero, bounds the whole reparent once the shard is
+	// locked, independently of WaitReplicasTimeout. When it expires, the reparent
+	// is aborted with a DEADLINE_EXCEEDED error naming the step in progress.
+	// Replicas still being reparented at that point carry on in the background,
+	// bounded by WaitReplicasTimeout, but no new wave of ReparentBatchSize
+	// replicas is started.
+	OverallTimeout time.Duration
 
 	// Private options managed internally. We use value passing to avoid leaking
 	// these details back out.
-	lockAction string
-	durability Durabler
+	lockAction        string
+	durability        Durabler
+	directReplicas    sets.Set[string]
+	ioErroredReplicas sets.Set[string]
+	errantGTIDs       map[string]string
 }
 
 // counters for Emergency Reparent Shard
-var ersCounter = stats.NewCountersWithMultiLabels("EmergencyReparentCounts", "Number of times Emergency Reparent Shard has been run",
-	[]string{"Keyspace", "Shard", "Result"},
+var (
+	ersCounter = stats.NewCountersWithMultiLabels("EmergencyReparentCounts", "Number of times Emergency Reparent Shard has been run",
+		[]string{"Keyspace", "Shard", "Result"},
+	)
+	ersBySourceCounter = stats.NewCountersWithMultiLabels("EmergencyReparentCountsBySource", "Number of times Emergency Reparent Shard has been run, by operation source",
+		[]string{"Keyspace", "Shard", "Source", "Result"},
+	)
+)
+
+// unknownOperationSource is the Source label used when no OperationSource is given.
+const unknownOperationSource = "unknown"
+
+// errant GTID stats for Emergency Reparent Shard
+var (
+	ersErrantGTIDCounter = stats.NewCountersWithMultiLabels("EmergencyReparentErrantGTIDDetections", "Number of times Emergency Reparent Shard found tablets with errant GTIDs",
+		[]string{"Keyspace", "Shard"},
+	)
+	ersErrantGTIDTablets = stats.NewGaugesWithMultiLabels("EmergencyReparentErrantGTIDTablets", "Number of tablets with errant GTIDs found by the last Emergency Reparent Shard",
+		[]string{"Keyspace", "Shard"},
+	)
 )
 
 // NewEmergencyReparenter returns a new EmergencyReparenter object, ready to
@@ -98,6 +237,25 @@ func NewEmergencyReparenter(ts *topo.Server, tmc tmclient.TabletManagerClient, l
 func (erp *EmergencyReparenter) ReparentShard(ctx context.Context, keyspace string, shard string, opts EmergencyReparentOptions) (*events.Reparent, error) {
 	var err error
 	statsLabels := []string{keyspace, shard}
+	source := opts.OperationSource
+	if source == "" {
+		source = unknownOperationSource
+	}
+	countResult := func(result string) {
+		if opts.DryRun {
+			return
+		}
+		ersCounter.Add(append(statsLabels, result), 1)
+		ersBySourceCounter.Add([]string{keyspace, shard, source, result}, 1)
+	}
+
+	if opts.BailIfHealthy {
+		if reason, healthy := erp.healthyPrimary(ctx, keyspace, shard, opts); healthy {
+			erp.logger.Infof("not reparenting shard %v/%v: %v", keyspace, shard, reason)
+			countResult(noopResult)
+			return &events.Reparent{NoOpReason: reason}, nil
+		}
+	}
 
 	opts.lockAction = erp.getLockAction(opts.NewPrimaryAlias)
 	// First step is to lock the shard for the given operation, if not already locked
@@ -105,7 +263,7 @@ func (erp *EmergencyReparenter) ReparentShard(ctx context.Context, keyspace stri
 		var unlock func(*error)
 		ctx, unlock, err = erp.ts.LockShard(ctx, keyspace, shard, opts.lockAction)
 		if err != nil {
-			ersCounter.Add(append(statsLabels, failureResult), 1)
+			countResult(failureResult)
 			return nil, err
 		}
 		defer unlock(&err)
@@ -118,10 +276,10 @@ func (erp *EmergencyReparenter) ReparentShard(ctx context.Context, keyspace stri
 		reparentShardOpTimings.Add("EmergencyReparentShard", time.Since(startTime))
 		switch err {
 		case nil:
-			ersCounter.Add(append(statsLabels, successResult), 1)
+			countResult(successResult)
 			event.DispatchUpdate(ev, "finished EmergencyReparentShard")
 		default:
-			ersCounter.Add(append(statsLabels, failureResult), 1)
+			countResult(failureResult)
 			event.DispatchUpdate(ev, "failed EmergencyReparentShard: "+err.Error())
 		}
 	}()
@@ -131,6 +289,327 @@ func (erp *EmergencyReparenter) ReparentShard(ctx context.Context, keyspace stri
 	return ev, err
 }
 
+// healthyPrimary probes the current primary of the shard, and returns true
+// along with a description of the situation if it is a writable primary that
+// ERS has no reason to replace. Any error is taken as a sign that it is not.
+func (erp *EmergencyReparenter) healthyPrimary(ctx context.Context, keyspace, shard string, opts EmergencyReparentOptions) (string, bool) {
+	shardInfo, err := erp.ts.GetShard(ctx, keyspace, shard)
+	if err != nil || shardInfo.PrimaryAlias == nil {
+		return "", false
+	}
+	if opts.NewPrimaryAlias != nil && !topoproto.TabletAliasEqual(opts.NewPrimaryAlias, shardInfo.PrimaryAlias) {
+		return "", false
+	}
+	primaryInfo, err := erp.ts.GetTablet(ctx, shardInfo.PrimaryAlias)
+	if err != nil || primaryInfo.Type != topodatapb.TabletType_PRIMARY {
+		return "", false
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+	status, err := erp.tmc.FullStatus(probeCtx, primaryInfo.Tablet)
+	if err != nil || status.PrimaryStatus == nil || status.ReadOnly {
+		return "", false
+	}
+	return fmt.Sprintf("shard already has a healthy primary %v, no action taken", topoproto.TabletAliasString(shardInfo.PrimaryAlias)), true
+}
+
+// ChooseNewPrimary runs the election of EmergencyReparentShard on replication
+// statuses collected by the caller, and returns the tablet it would promote,
+// without stopping replication or changing anything. The durability policy is
+// the one named by durabilityPolicy, "none" if empty, since there is no
+// keyspace to read it from. The tablets of the status map are taken as the
+// reachable ones, and the PRIMARY tablet of the tablet map, if any, as the
+// previous primary. Like ERS, it assumes every valid candidate can catch up
+// with the most advanced one.
+func ChooseNewPrimary(
+	statusMap map[string]*replicationdatapb.StopReplicationStatus,
+	tabletMap map[string]*topo.TabletInfo,
+	durabilityPolicy string,
+	opts EmergencyReparentOptions,
+) (*topodatapb.Tablet, error) {
+	if durabilityPolicy == "" {
+		durabilityPolicy = "none"
+	}
+	var err error
+	opts.durability, err = GetDurabilityPolicy(durabilityPolicy)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get durability policy %v: %v", durabilityPolicy, err)
+	}
+
+	var (
+		prevPrimary      *topodatapb.Tablet
+		reachableTablets []*topodatapb.Tablet
+	)
+	for alias, tabletInfo := range tabletMap {
+		if tabletInfo.Type == topodatapb.TabletType_PRIMARY {
+			prevPrimary = tabletInfo.Tablet
+		}
+		if _, ok := statusMap[alias]; ok {
+			reachableTablets = append(reachableTablets, tabletInfo.Tablet)
+		}
+	}
+
+	erp := NewEmergencyReparenter(nil, nil, nil)
+	_, _, newPrimary, err := erp.elect(statusMap, nil, tabletMap, reachableTablets, prevPrimary, nil, opts)
+	return newPrimary, err
+}
+
+// elect runs all the steps of the election of reparentShardLocked on the given statuses, assuming every valid
+// candidate can catch up with the intermediate source. It returns the intermediate source, all the valid candidates,
+// sorted, and the tablet to promote.
+func (erp *EmergencyReparenter) elect(
+	statusMap map[string]*replicationdatapb.StopReplicationStatus,
+	primaryStatusMap map[string]*replicationdatapb.PrimaryStatus,
+	tabletMap map[string]*topo.TabletInfo,
+	reachableTablets []*topodatapb.Tablet,
+	prevPrimary *topodatapb.Tablet,
+	laggards []string,
+	opts EmergencyReparentOptions,
+) (intermediateSource *topodatapb.Tablet, candidates []*topodatapb.Tablet, newPrimary *topodatapb.Tablet, err error) {
+	if opts.PreferDirectReplicas {
+		opts.directReplicas = findDirectReplicas(statusMap, prevPrimary)
+	}
+	if opts.ExcludeIOErrored {
+		opts.ioErroredReplicas = findIOErroredReplicas(statusMap)
+	}
+	validCandidates, errantGTIDs, err := findElectionCandidates(statusMap, primaryStatusMap, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	opts.errantGTIDs = errantGTIDs
+	validCandidates, err = restrictElectionCandidates(validCandidates, tabletMap, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	intermediateSource, candidates, err = erp.electIntermediateSource(validCandidates, laggards, tabletMap, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	_, newPrimary, err = erp.electNewPrimary(intermediateSource, candidates, reachableTablets, prevPrimary, tabletMap, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return intermediateSource, candidates, newPrimary, nil
+}
+
+// findElectionCandidates finds the valid candidates for becoming the primary among the tablets of the given
+// statuses, along with their positions. This is where the tablets with errant GTIDs are removed from consideration,
+// they are returned with their errant GTID sets.
+func findElectionCandidates(
+	statusMap map[string]*replicationdatapb.StopReplicationStatus,
+	primaryStatusMap map[string]*replicationdatapb.PrimaryStatus,
+	opts EmergencyReparentOptions,
+) (map[string]replication.Position, map[string]string, error) {
+	validCandidates, errantGTIDs, err := findValidEmergencyReparentCandidates(statusMap, primaryStatusMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.PreferRelayLogReceived {
+		useRelayLogReceivedPositions(validCandidates, statusMap)
+	}
+	return validCandidates, errantGTIDs, nil
+}
+
+// restrictElectionCandidates removes the valid candidates that can never be the replication source, which are the
+// DRAINED, RESTORE and BACKUP tablets, and the ones not replicating with GTIDs if RequireGTIDMode is set.
+// It fails if no candidate is left.
+func restrictElectionCandidates(validCandidates map[string]replication.Position, tabletMap map[string]*topo.TabletInfo, opts EmergencyReparentOptions) (map[string]replication.Position, error) {
+	validCandidates, err := restrictValidCandidates(validCandidates, tabletMap)
+	if err != nil {
+		return nil, err
+	} else if len(validCandidates) == 0 {
+		if len(opts.errantGTIDs) > 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent; tablets with errant GTIDs: %v", errantGTIDSummary(opts.errantGTIDs))
+		}
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent")
+	}
+	// If we were asked to only promote tablets running with GTID_MODE=ON, remove all the others.
+	if opts.RequireGTIDMode {
+		return restrictGTIDModeCandidates(validCandidates, opts.NewPrimaryAlias)
+	}
+	return validCandidates, nil
+}
+
+// electIntermediateSource leaves out of the valid candidates the laggards that could not apply their relay logs,
+// and returns the most advanced of the others along with all of them, sorted. It fails if a laggard has received
+// anything the intermediate source has not, since leaving it out would lose those transactions.
+func (erp *EmergencyReparenter) electIntermediateSource(
+	validCandidates map[string]replication.Position,
+	laggards []string,
+	tabletMap map[string]*topo.TabletInfo,
+	opts EmergencyReparentOptions,
+) (*topodatapb.Tablet, []*topodatapb.Tablet, error) {
+	laggardPositions := make(map[string]replication.Position, len(laggards))
+	for _, alias := range laggards {
+		if opts.NewPrimaryAlias != nil && alias == topoproto.TabletAliasString(opts.NewPrimaryAlias) {
+			return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "requested primary elect %v could not apply its relay logs within the provided waitReplicasTimeout (%s)", alias, opts.WaitReplicasTimeout)
+		}
+		if pos, ok := validCandidates[alias]; ok {
+			laggardPositions[alias] = pos
+			delete(validCandidates, alias)
+		}
+	}
+	if len(validCandidates) == 0 {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent; none of them applied their relay logs within the provided waitReplicasTimeout (%s)", opts.WaitReplicasTimeout)
+	}
+
+	intermediateSource, validTablets, err := erp.findMostAdvanced(validCandidates, tabletMap, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	sourcePos := validCandidates[topoproto.TabletAliasString(intermediateSource.Alias)]
+	for _, alias := range laggards {
+		if pos, ok := laggardPositions[alias]; ok && !sourcePos.AtLeast(pos) {
+			return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "candidate %v could not apply its relay logs within the provided waitReplicasTimeout (%s), but it is ahead of the most advanced other candidate %v, so it cannot be left out",
+				alias, opts.WaitReplicasTimeout, topoproto.TabletAliasString(intermediateSource.Alias))
+		}
+	}
+	return intermediateSource, validTablets, nil
+}
+
+// electNewPrimary keeps the candidates that can be promoted, and returns them along with the one to promote,
+// which is the intermediate source itself if it is the ideal candidate.
+func (erp *EmergencyReparenter) electNewPrimary(
+	intermediateSource *topodatapb.Tablet,
+	candidates []*topodatapb.Tablet,
+	reachableTablets []*topodatapb.Tablet,
+	prevPrimary *topodatapb.Tablet,
+	tabletMap map[string]*topo.TabletInfo,
+	opts EmergencyReparentOptions,
+) ([]*topodatapb.Tablet, *topodatapb.Tablet, error) {
+	// After finding the intermediate source, we want to filter the valid candidate list by the following criteria -
+	// 1. Only keep the tablets which can make progress after being promoted (have sufficient reachable semi-sync ackers)
+	// 2. Remove the tablets with the Must_not promote rule
+	// 3. Remove cross-cell tablets if PreventCrossCellPromotion is specified
+	// Our final primary candidate MUST belong to this list of valid candidates
+	validCandidateTablets, err := erp.filterValidCandidates(candidates, reachableTablets, prevPrimary, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	newPrimary, err := erp.identifyPrimaryCandidate(intermediateSource, validCandidateTablets, tabletMap, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return validCandidateTablets, newPrimary, nil
+}
+
+// dropUnreadablePrimaryStatuses removes from the primary status map the tablets whose position is empty or cannot
+// be decoded, and returns their aliases, sorted.
+func dropUnreadablePrimaryStatuses(primaryStatusMap map[string]*replicationdatapb.PrimaryStatus) []string {
+	var dropped []string
+	for alias, status := range primaryStatusMap {
+		pos, err := replication.DecodePosition(status.GetPosition())
+		if err != nil || pos.IsZero() {
+			dropped = append(dropped, alias)
+			delete(primaryStatusMap, alias)
+		}
+	}
+	slices.Sort(dropped)
+	return dropped
+}
+
+// recordErrantGTIDs updates the errant GTID stats of the shard. The only replicas
+// FindValidEmergencyReparentCandidates leaves out are the ones with errant GTIDs.
+func recordErrantGTIDs(keyspace, shard string, statusMap map[string]*replicationdatapb.StopReplicationStatus, validCandidates map[string]replication.Position) {
+	errant := 0
+	for alias := range statusMap {
+		if _, ok := validCandidates[alias]; !ok {
+			errant++
+		}
+	}
+
+	statsLabels := []string{keyspace, shard}
+	ersErrantGTIDTablets.Set(statsLabels, int64(errant))
+	if errant > 0 {
+		ersErrantGTIDCounter.Add(statsLabels, 1)
+	}
+}
+
+// errantGTIDSummary formats the errant GTID sets of the given tablets as a
+// single line, sorted by alias.
+func errantGTIDSummary(errantGTIDs map[string]string) string {
+	aliases := make([]string, 0, len(errantGTIDs))
+	for alias := range errantGTIDs {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+
+	summary := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		summary = append(summary, fmt.Sprintf("%v (%v)", alias, errantGTIDs[alias]))
+	}
+	return strings.Join(summary, ", ")
+}
+
+// Reasons reported in the IgnoredReplicas of the reparent event.
+const (
+	// IgnoredExplicitly is reported for the tablets listed in IgnoreReplicas.
+	IgnoredExplicitly = "explicitly ignored"
+	// IgnoredDrained is reported for the DRAINED tablets.
+	IgnoredDrained = "drained"
+	// IgnoredTakingBackup is reported for the BACKUP tablets.
+	IgnoredTakingBackup = "taking backup"
+	// IgnoredByType is reported for the tablets of any other type that can never
+	// be promoted, such as RESTORE.
+	IgnoredByType = "excluded by type"
+)
+
+// findIgnoredReplicas returns, by alias, why ERS skipped a tablet of the shard:
+// either because it was listed in IgnoreReplicas, or because restrictValidCandidates
+// removes the candidates of its type from consideration.
+func findIgnoredReplicas(tabletMap map[string]*topo.TabletInfo, ignoreReplicas sets.Set[string], validCandidates map[string]replication.Position) map[string]string {
+	ignored := map[string]string{}
+	for alias, tabletInfo := range tabletMap {
+		if ignoreReplicas.Has(alias) {
+			ignored[alias] = IgnoredExplicitly
+			continue
+		}
+		if _, ok := validCandidates[alias]; !ok {
+			continue
+		}
+		switch tabletInfo.Type {
+		case topodatapb.TabletType_DRAINED:
+			ignored[alias] = IgnoredDrained
+		case topodatapb.TabletType_BACKUP:
+			ignored[alias] = IgnoredTakingBackup
+		case topodatapb.TabletType_RESTORE:
+			ignored[alias] = IgnoredByType
+		}
+	}
+	return ignored
+}
+
+// useRelayLogReceivedPositions replaces the position of the candidates that are
+// replicas with the position they will reach once their relay logs are applied.
+// This only changes the positions of the replicas that do not use GTIDs, since
+// FindValidEmergencyReparentCandidates already uses the relay log positions of
+// the others.
+func useRelayLogReceivedPositions(validCandidates map[string]replication.Position, statusMap map[string]*replicationdatapb.StopReplicationStatus) {
+	for alias := range validCandidates {
+		status, ok := statusMap[alias]
+		if !ok {
+			continue
+		}
+		relayLogPos := replication.ProtoToReplicationStatus(status.After).RelayLogPosition
+		if relayLogPos.IsZero() {
+			continue
+		}
+		validCandidates[alias] = relayLogPos
+	}
+}
+
+// recordStepTiming adds the time elapsed since start to the timing of the given
+// step of the reparent on the event, and logs it.
+func (erp *EmergencyReparenter) recordStepTiming(ev *events.Reparent, step string, start time.Time) {
+	elapsed := time.Since(start)
+	if ev.Timings == nil {
+		ev.Timings = make(map[string]time.Duration)
+	}
+	ev.Timings[step] += elapsed
+	erp.logger.Infof("emergency reparent step %v took %v", step, elapsed)
+}
+
 func (erp *EmergencyReparenter) getLockAction(newPrimaryAlias *topodatapb.TabletAlias) string {
 	action := "EmergencyReparentShard"
 
@@ -156,15 +635,39 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 		validCandidateTablets      []*topodatapb.Tablet
 		validReplacementCandidates []*topodatapb.Tablet
 		betterCandidate            *topodatapb.Tablet
+		newPrimary                 *topodatapb.Tablet
 		isIdeal                    bool
+		// phase is the step in progress, reported if OverallTimeout expires.
+		phase = "ReadTopology"
 	)
 
+	decision := newElectionDecision(keyspace, shard, opts)
+	defer func() {
+		writeDecision(erp.logger, decision, opts, err)
+	}()
+
+	if opts.OverallTimeout > 0 {
+		parentCtx := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.OverallTimeout)
+		defer cancel()
+		defer func() {
+			if err != nil && ctx.Err() == context.DeadlineExceeded && parentCtx.Err() == nil {
+				err = vterrors.Errorf(vtrpc.Code_DEADLINE_EXCEEDED, "emergency reparent of %v/%v did not finish within OverallTimeout (%v), during step %v: %v", keyspace, shard, opts.OverallTimeout, phase, err)
+			}
+		}()
+	}
+
 	shardInfo, err = erp.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
 		return err
 	}
 	ev.ShardInfo = *shardInfo
 
+	if !shardInfo.IsPrimaryServing && !opts.AllowNonServing {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "primary of shard %v/%v is not serving; refusing to reparent a non-serving shard without AllowNonServing", keyspace, shard)
+	}
+
 	keyspaceDurability, err := erp.ts.GetKeyspaceDurability(ctx, keyspace)
 	if err != nil {
 		return err
@@ -175,6 +678,7 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	if err != nil {
 		return err
 	}
+	decision.Durability = keyspaceDurability
 
 	// get the previous primary according to the topology server,
 	// we use this information to choose the best candidate in the same cell
@@ -185,6 +689,7 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 			return err
 		}
 		prevPrimary = prevPrimaryInfo.Tablet
+		decision.PreviousPrimary = topoproto.TabletAliasString(prevPrimary.Alias)
 	}
 
 	// read all the tablets and their information
@@ -193,36 +698,96 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	if err != nil {
 		return vterrors.Wrapf(err, "failed to get tablet map for %v/%v: %v", keyspace, shard, err)
 	}
+	decision.setTablets(tabletMap, opts.durability)
+
+	// Make sure the shard could get a working primary at all before disrupting replication
+	if err = checkDurabilityReachable(opts.durability, keyspaceDurability, tabletMap, opts); err != nil {
+		return err
+	}
 
 	// Stop replication on all the tablets and build their status map
-	stoppedReplicationSnapshot, err = stopReplicationAndBuildStatusMaps(ctx, erp.tmc, ev, tabletMap, topo.RemoteOperationTimeout, opts.IgnoreReplicas, opts.NewPrimaryAlias, opts.durability, opts.WaitAllTablets, erp.logger)
+	tmc := erp.tmc
+	if opts.DryRun {
+		tmc = dryRunTabletManagerClient{erp.tmc}
+	}
+	if opts.StopReplicationRetries > 0 {
+		tmc = stopReplicationRetryingTabletManagerClient{
+			TabletManagerClient: tmc,
+			retries:             opts.StopReplicationRetries,
+			delay:               opts.StopReplicationRetryDelay,
+			logger:              erp.logger,
+		}
+	}
+	phase = "StopReplicationAndGetStatus"
+	stepStart := time.Now()
+	stoppedReplicationSnapshot, err = stopReplicationAndBuildStatusMaps(ctx, tmc, ev, tabletMap, topo.RemoteOperationTimeout, opts.IgnoreReplicas, opts.NewPrimaryAlias, opts.durability, opts.WaitAllTablets, erp.logger)
+	erp.recordStepTiming(ev, "StopReplicationAndGetStatus", stepStart)
 	if err != nil {
 		return vterrors.Wrapf(err, "failed to stop replication and build status maps: %v", err)
 	}
+	if opts.RequireDemotedPrimaryPosition {
+		for _, alias := range dropUnreadablePrimaryStatuses(stoppedReplicationSnapshot.primaryStatusMap) {
+			if opts.NewPrimaryAlias != nil && alias == topoproto.TabletAliasString(opts.NewPrimaryAlias) {
+				return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "requested primary elect %v claims to be a primary but did not return a readable position when demoted", alias)
+			}
+			ev.Warnings = append(ev.Warnings, fmt.Sprintf("tablet %v claims to be a primary but did not return a readable position when demoted, it was excluded from the candidates", alias))
+		}
+	}
+	decision.setStatuses(stoppedReplicationSnapshot.statusMap, stoppedReplicationSnapshot.primaryStatusMap, stoppedReplicationSnapshot.reachableTablets)
 
 	// check that we still have the shard lock. If we don't then we can terminate at this point
 	if err := topo.CheckShardLocked(ctx, keyspace, shard); err != nil {
 		return vterrors.Wrapf(err, "lost topology lock, aborting: %v", err)
 	}
 
+	// If we were asked to prefer direct replicas of the previous primary, find out which tablets
+	// were replicating from it before we stopped replication.
+	if opts.PreferDirectReplicas {
+		opts.directReplicas = findDirectReplicas(stoppedReplicationSnapshot.statusMap, prevPrimary)
+	}
+	if opts.ExcludeIOErrored {
+		opts.ioErroredReplicas = findIOErroredReplicas(stoppedReplicationSnapshot.statusMap)
+	}
+
 	// find the valid candidates for becoming the primary
 	// this is where we check for errant GTIDs and remove the tablets that have them from consideration
-	validCandidates, err = FindValidEmergencyReparentCandidates(stoppedReplicationSnapshot.statusMap, stoppedReplicationSnapshot.primaryStatusMap)
+	validCandidates, ev.ErrantGTIDs, err = findElectionCandidates(stoppedReplicationSnapshot.statusMap, stoppedReplicationSnapshot.primaryStatusMap, opts)
 	if err != nil {
 		return err
 	}
-	// Restrict the valid candidates list. We remove any tablet which is of the type DRAINED, RESTORE or BACKUP.
-	validCandidates, err = restrictValidCandidates(validCandidates, tabletMap)
+	opts.errantGTIDs = ev.ErrantGTIDs
+	recordErrantGTIDs(keyspace, shard, stoppedReplicationSnapshot.statusMap, validCandidates)
+	decision.setValidCandidates(validCandidates)
+	ev.CandidatePositions = make(map[string]string, len(validCandidates))
+	for alias, pos := range validCandidates {
+		ev.CandidatePositions[alias] = replication.EncodePosition(pos)
+	}
+	ev.IgnoredReplicas = findIgnoredReplicas(tabletMap, opts.IgnoreReplicas, validCandidates)
+	// Restrict the valid candidates list. We remove any tablet which is of the type DRAINED, RESTORE or BACKUP,
+	// and the ones not running with GTID_MODE=ON if we were asked to.
+	validCandidates, err = restrictElectionCandidates(validCandidates, tabletMap, opts)
 	if err != nil {
 		return err
-	} else if len(validCandidates) == 0 {
-		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent")
 	}
 
 	// Wait for all candidates to apply relay logs
-	if err = erp.waitForAllRelayLogsToApply(ctx, validCandidates, tabletMap, stoppedReplicationSnapshot.statusMap, opts.WaitReplicasTimeout); err != nil {
+	ev.WaitReplicasTimeout = opts.WaitReplicasTimeout
+	phase = "WaitForAllRelayLogsToApply"
+	waitStart := time.Now()
+	var laggards []string
+	if !opts.DryRun {
+		err = erp.watchShardTerm(ctx, shardInfo, nil /* newPrimary */, opts, func(ctx context.Context) (err error) {
+			laggards, err = erp.waitForAllRelayLogsToApply(ctx, validCandidates, tabletMap, stoppedReplicationSnapshot.statusMap, opts.WaitReplicasTimeout, opts.RelayLogApplyQuorum)
+			return err
+		})
+		erp.recordStepTiming(ev, "WaitForAllRelayLogsToApply", waitStart)
+	}
+	ev.ReplicaWaitTime += time.Since(waitStart)
+	if err != nil {
 		return err
 	}
+	decision.Laggards = slices.Clone(laggards)
+	slices.Sort(decision.Laggards)
 
 	// Find the intermediate source for replication that we want other tablets to replicate from.
 	// This step chooses the most advanced tablet. Further ties are broken by using the promotion rule.
@@ -230,29 +795,34 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	// Here we also check for split brain scenarios and check that the selected replica must be more advanced than all the other valid candidates.
 	// We fail in case there is a split brain detected.
 	// The validCandidateTablets list is sorted by the replication positions with ties broken by promotion rules.
-	intermediateSource, validCandidateTablets, err = erp.findMostAdvanced(validCandidates, tabletMap, opts)
+	phase = "FindMostAdvanced"
+	stepStart = time.Now()
+	intermediateSource, validCandidateTablets, err = erp.electIntermediateSource(validCandidates, laggards, tabletMap, opts)
+	erp.recordStepTiming(ev, "FindMostAdvanced", stepStart)
 	if err != nil {
 		return err
 	}
+	for _, alias := range laggards {
+		ev.Warnings = append(ev.Warnings, fmt.Sprintf("candidate %v was excluded because it could not apply its relay logs within the provided waitReplicasTimeout (%s)", alias, opts.WaitReplicasTimeout))
+	}
 	erp.logger.Infof("intermediate source selected - %v", intermediateSource.Alias)
+	decision.setMostAdvanced(intermediateSource, validCandidateTablets)
 
-	// After finding the intermediate source, we want to filter the valid candidate list by the following criteria -
-	// 1. Only keep the tablets which can make progress after being promoted (have sufficient reachable semi-sync ackers)
-	// 2. Remove the tablets with the Must_not promote rule
-	// 3. Remove cross-cell tablets if PreventCrossCellPromotion is specified
-	// Our final primary candidate MUST belong to this list of valid candidates
-	validCandidateTablets, err = erp.filterValidCandidates(validCandidateTablets, stoppedReplicationSnapshot.reachableTablets, prevPrimary, opts)
+	// Keep the candidates that can be promoted, and find the best one among them.
+	// If it is the intermediate source, then the intermediate source is ideal and we can be certain that it is
+	// part of the valid candidates list. Otherwise, it can be improved later.
+	validCandidateTablets, newPrimary, err = erp.electNewPrimary(intermediateSource, validCandidateTablets, stoppedReplicationSnapshot.reachableTablets, prevPrimary, tabletMap, opts)
 	if err != nil {
 		return err
 	}
+	isIdeal = newPrimary == intermediateSource
+	erp.logger.Infof("intermediate source is ideal candidate- %v", isIdeal)
 
-	// Check whether the intermediate source candidate selected is ideal or if it can be improved later.
-	// If the intermediateSource is ideal, then we can be certain that it is part of the valid candidates list.
-	isIdeal, err = erp.isIntermediateSourceIdeal(intermediateSource, validCandidateTablets, tabletMap, opts)
-	if err != nil {
-		return err
+	if opts.DryRun {
+		erp.planDryRun(ev, newPrimary, validCandidateTablets, validCandidates, tabletMap, stoppedReplicationSnapshot.reachableTablets, opts)
+		decision.NewPrimary = topoproto.TabletAliasString(ev.PlannedPrimary.Alias)
+		return nil
 	}
-	erp.logger.Infof("intermediate source is ideal candidate- %v", isIdeal)
 
 	// Check (again) we still have the topology lock.
 	if err = topo.CheckShardLocked(ctx, keyspace, shard); err != nil {
@@ -260,13 +830,20 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	}
 
 	// initialize the newPrimary with the intermediate source, override this value if it is not the ideal candidate
-	newPrimary := intermediateSource
+	newPrimary = intermediateSource
 	if !isIdeal {
 		// we now reparent all the tablets to start replicating from the intermediate source
 		// we do not promote the tablet or change the shard record. We only change the replication for all the other tablets
 		// it also returns the list of the tablets that started replication successfully including itself part of the validCandidateTablets list.
 		// These are the candidates that we can use to find a replacement.
-		validReplacementCandidates, err = erp.promoteIntermediateSource(ctx, ev, intermediateSource, tabletMap, stoppedReplicationSnapshot.statusMap, validCandidateTablets, opts)
+		phase = "PromoteIntermediateSource"
+		waitStart = time.Now()
+		err = erp.watchShardTerm(ctx, shardInfo, nil /* newPrimary */, opts, func(ctx context.Context) (err error) {
+			validReplacementCandidates, err = erp.promoteIntermediateSource(ctx, ev, intermediateSource, tabletMap, stoppedReplicationSnapshot.statusMap, validCandidateTablets, opts)
+			return err
+		})
+		erp.recordStepTiming(ev, "PromoteIntermediateSource", waitStart)
+		ev.ReplicaWaitTime += time.Since(waitStart)
 		if err != nil {
 			return err
 		}
@@ -281,7 +858,13 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 
 		// if our better candidate is different from our intermediate source, then we wait for it to catch up to the intermediate source
 		if !topoproto.TabletAliasEqual(betterCandidate.Alias, intermediateSource.Alias) {
-			err = waitForCatchUp(ctx, erp.tmc, erp.logger, betterCandidate, intermediateSource, opts.WaitReplicasTimeout)
+			phase = "WaitForCatchUp"
+			waitStart = time.Now()
+			err = erp.watchShardTerm(ctx, shardInfo, nil /* newPrimary */, opts, func(ctx context.Context) error {
+				return waitForCatchUp(ctx, erp.tmc, erp.logger, betterCandidate, intermediateSource, opts.WaitReplicasTimeout)
+			})
+			erp.recordStepTiming(ev, "WaitForCatchUp", waitStart)
+			ev.ReplicaWaitTime += time.Since(waitStart)
 			if err != nil {
 				return err
 			}
@@ -297,29 +880,140 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	//		it is the intermediate source itself) will belong to the list
 	// Since the new primary tablet belongs to the validCandidateTablets list, we no longer need any additional constraint checks
 
-	// Final step is to promote our primary candidate
-	_, err = erp.reparentReplicas(ctx, ev, newPrimary, tabletMap, stoppedReplicationSnapshot.statusMap, opts, false /* intermediateReparent */)
+	// Make sure no other reparent has started while we were waiting, before promoting our primary candidate
+	if opts.AbortOnConcurrentReparent {
+		if err = erp.checkShardTerm(ctx, shardInfo, nil /* newPrimary */); err != nil {
+			return err
+		}
+	}
+
+	// Final step is to promote our primary candidate, and point the replicas at it
+	phase = "PromoteNewPrimary"
+	waitStart = time.Now()
+	err = erp.watchShardTerm(ctx, shardInfo, newPrimary, opts, func(ctx context.Context) error {
+		_, err := erp.reparentReplicas(ctx, ev, newPrimary, tabletMap, stoppedReplicationSnapshot.statusMap, opts, false /* intermediateReparent */)
+		return err
+	})
+	erp.recordStepTiming(ev, "PromoteNewPrimary", waitStart)
+	ev.ReplicaWaitTime += time.Since(waitStart)
 	if err != nil {
 		return err
 	}
 	ev.NewPrimary = newPrimary.CloneVT()
+	decision.NewPrimary = topoproto.TabletAliasString(newPrimary.Alias)
+	ev.SemiSyncAckers = semiSyncAckersReached(opts.durability, newPrimary, stoppedReplicationSnapshot.reachableTablets)
+
+	if opts.VerifyReplicasConverging {
+		ev.Warnings = append(ev.Warnings, erp.verifyReplicasConverging(ctx, newPrimary, tabletMap, opts)...)
+	}
 	return err
 }
 
+// skipReattach returns true if the type of the tablet is one of the SkipReplicaTypes, which are not
+// pointed at the new primary.
+func skipReattach(tablet *topodatapb.Tablet, opts EmergencyReparentOptions) bool {
+	return slices.Contains(opts.SkipReplicaTypes, tablet.Type)
+}
+
+// notifyPrimaryPromoted calls OnPrimaryPromoted with the new primary, after
+// checking that the shard is still locked.
+func (erp *EmergencyReparenter) notifyPrimaryPromoted(ctx context.Context, ev *events.Reparent, newPrimary *topodatapb.Tablet, opts EmergencyReparentOptions) error {
+	if err := topo.CheckShardLocked(ctx, ev.ShardInfo.Keyspace(), ev.ShardInfo.ShardName()); err != nil {
+		return vterrors.Wrapf(err, "lost topology lock before calling OnPrimaryPromoted, aborting: %v", err)
+	}
+	if err := opts.OnPrimaryPromoted(ctx, newPrimary.CloneVT()); err != nil {
+		return vterrors.Wrapf(err, "OnPrimaryPromoted failed for new primary %v: %v", topoproto.TabletAliasString(newPrimary.Alias), err)
+	}
+	return nil
+}
+
+// shardTermCheckInterval is how often the primary term of the shard is checked
+// while waiting on the replicas, when AbortOnConcurrentReparent is set.
+var shardTermCheckInterval = time.Second
+
+// checkShardTerm returns an error if the primary or the primary term of the shard
+// has changed since the given shard record was read. Once newPrimary is promoted,
+// it updates the shard record itself, so a term of newPrimary is not a change.
+func (erp *EmergencyReparenter) checkShardTerm(ctx context.Context, shardInfo *topo.ShardInfo, newPrimary *topodatapb.Tablet) error {
+	current, err := erp.ts.GetShard(ctx, shardInfo.Keyspace(), shardInfo.ShardName())
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to read shard %v/%v to check its primary term: %v", shardInfo.Keyspace(), shardInfo.ShardName(), err)
+	}
+	if newPrimary != nil && topoproto.TabletAliasEqual(current.PrimaryAlias, newPrimary.Alias) {
+		return nil
+	}
+	if !topoproto.TabletAliasEqual(current.PrimaryAlias, shardInfo.PrimaryAlias) || !proto.Equal(current.PrimaryTermStartTime, shardInfo.PrimaryTermStartTime) {
+		return vterrors.Errorf(vtrpc.Code_ABORTED, "concurrent reparent detected: the primary of shard %v/%v changed to %v during emergency reparent, aborting",
+			shardInfo.Keyspace(), shardInfo.ShardName(), topoproto.TabletAliasString(current.PrimaryAlias))
+	}
+	return nil
+}
+
+// watchShardTerm runs the given wait function, checking the primary term of the shard every shardTermCheckInterval
+// while it runs if opts.AbortOnConcurrentReparent is set. If the term changes, the context passed to the wait function
+// is cancelled and the error of checkShardTerm is returned. Failures to read the shard record are only logged.
+// newPrimary is the tablet being promoted by the wait function, if any.
+func (erp *EmergencyReparenter) watchShardTerm(ctx context.Context, shardInfo *topo.ShardInfo, newPrimary *topodatapb.Tablet, opts EmergencyReparentOptions, wait func(ctx context.Context) error) error {
+	if !opts.AbortOnConcurrentReparent {
+		return wait(ctx)
+	}
+
+	waitCtx, waitCancel := context.WithCancel(ctx)
+	defer waitCancel()
+
+	termErrCh := make(chan error, 1)
+	ticker := time.NewTicker(shardTermCheckInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-waitCtx.Done():
+				return
+			case <-ticker.C:
+				err := erp.checkShardTerm(waitCtx, shardInfo, newPrimary)
+				switch {
+				case err == nil || waitCtx.Err() != nil:
+				case vterrors.Code(err) == vtrpc.Code_ABORTED:
+					termErrCh <- err
+					waitCancel()
+					return
+				default:
+					// failing to read the shard record is not a reason to abort, we'll check again later
+					erp.logger.Warningf("%v", err)
+				}
+			}
+		}
+	}()
+
+	err := wait(waitCtx)
+	select {
+	case termErr := <-termErrCh:
+		return termErr
+	default:
+		return err
+	}
+}
+
 func (erp *EmergencyReparenter) waitForAllRelayLogsToApply(
 	ctx context.Context,
 	validCandidates map[string]replication.Position,
 	tabletMap map[string]*topo.TabletInfo,
 	statusMap map[string]*replicationdatapb.StopReplicationStatus,
 	waitReplicasTimeout time.Duration,
-) error {
+	quorum int,
+) ([]string, error) {
 	errCh := make(chan concurrency.Error)
 	defer close(errCh)
 
 	groupCtx, groupCancel := context.WithTimeout(ctx, waitReplicasTimeout)
 	defer groupCancel()
 
+	var (
+		m        sync.Mutex
+		laggards []string
+	)
 	waiterCount := 0
+	skippedCount := 0
 
 	for candidate := range validCandidates {
 		// When we called stopReplicationAndBuildStatusMaps, we got back two
@@ -342,6 +1036,7 @@ func (erp *EmergencyReparenter) waitForAllRelayLogsToApply(
 		status, ok := statusMap[candidate]
 		if !ok {
 			erp.logger.Infof("EmergencyReparent candidate %v not in replica status map; this means it was not running replication (because it was formerly PRIMARY), so skipping WaitForRelayLogsToApply step for this candidate", candidate)
+			skippedCount++
 			continue
 		}
 
@@ -353,23 +1048,40 @@ func (erp *EmergencyReparenter) waitForAllRelayLogsToApply(
 				}
 			}()
 			err = WaitForRelayLogsToApply(groupCtx, erp.tmc, tabletMap[alias], status)
+			if err != nil {
+				m.Lock()
+				laggards = append(laggards, alias)
+				m.Unlock()
+			}
 		}(candidate, status)
 
 		waiterCount++
 	}
 
+	// With a quorum, the candidates that were skipped count towards it, and as
+	// many of the others as are not needed to reach it may fail. The others stop
+	// waiting as soon as the quorum is reached, and are counted as laggards.
+	requiredSuccesses := waiterCount
+	if quorum > 0 {
+		requiredSuccesses = max(0, min(waiterCount, quorum-skippedCount))
+	}
+	allowedErrors := waiterCount - requiredSuccesses
 	errgroup := concurrency.ErrorGroup{
 		NumGoroutines:        waiterCount,
-		NumRequiredSuccesses: waiterCount,
-		NumAllowedErrors:     0,
+		NumRequiredSuccesses: requiredSuccesses,
+		NumAllowedErrors:     allowedErrors,
 	}
 	rec := errgroup.Wait(groupCancel, errCh)
 
-	if len(rec.Errors) != 0 {
-		return vterrors.Wrapf(rec.Error(), "could not apply all relay logs within the provided waitReplicasTimeout (%s): %v", waitReplicasTimeout, rec.Error())
+	if len(rec.Errors) > allowedErrors {
+		return nil, vterrors.Wrapf(rec.Error(), "could not apply all relay logs within the provided waitReplicasTimeout (%s): %v", waitReplicasTimeout, rec.Error())
 	}
 
-	return nil
+	slices.Sort(laggards)
+	for _, alias := range laggards {
+		erp.logger.Warningf("EmergencyReparent candidate %v could not apply its relay logs within the provided waitReplicasTimeout (%s), continuing with a quorum of %d", alias, waitReplicasTimeout, quorum)
+	}
+	return laggards, nil
 }
 
 // findMostAdvanced finds the intermediate source for ERS. We always choose the most advanced one from our valid candidates list. Further ties are broken by looking at the promotion rules.
@@ -390,6 +1102,22 @@ func (erp *EmergencyReparenter) findMostAdvanced(
 	if err != nil {
 		return nil, nil, err
 	}
+	// Reorder the equally good tablets by the score given by the caller, if any.
+	if opts.CandidateScorer != nil {
+		sortTiedTabletsByScore(validTablets, tabletPositions, opts.durability, opts.CandidateScorer)
+	}
+	// Then move the tagged tablets ahead of the equally good ones, if asked to.
+	if opts.PreferTabletTag != "" {
+		preferTaggedTablets(validTablets, tabletPositions, opts.durability, opts.PreferTabletTag, opts.PreferTabletTagValue)
+	}
+	// Break the tie between the equally good tablets at the top of the list in favour of a direct replica, if asked to.
+	if opts.directReplicas.Len() > 0 {
+		preferDirectReplica(validTablets, tabletPositions, opts.durability, opts.directReplicas)
+	}
+	// Similarly, avoid the tablets whose IO thread was erroring when there is an equally good one.
+	if opts.ioErroredReplicas.Len() > 0 {
+		avoidIOErroredReplica(validTablets, tabletPositions, opts.durability, opts.ioErroredReplicas)
+	}
 	for _, tablet := range validTablets {
 		erp.logger.Infof("finding intermediate source - sorted replica: %v", tablet.Alias)
 	}
@@ -412,6 +1140,9 @@ func (erp *EmergencyReparenter) findMostAdvanced(
 		requestedPrimaryAlias := topoproto.TabletAliasString(opts.NewPrimaryAlias)
 		pos, ok := validCandidates[requestedPrimaryAlias]
 		if !ok {
+			if errantGTIDs, isErrant := opts.errantGTIDs[requestedPrimaryAlias]; isErrant {
+				return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "requested primary elect %v has errant GTIDs: %v", requestedPrimaryAlias, errantGTIDs)
+			}
 			return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "requested primary elect %v has errant GTIDs", requestedPrimaryAlias)
 		}
 		// if the requested tablet is as advanced as the most advanced tablet, then we can just use it for promotion.
@@ -488,6 +1219,8 @@ func (erp *EmergencyReparenter) reparentReplicas(
 		replicaMutex               sync.Mutex
 	)
 
+	// replCtx bounds the reparent of all the replicas, in as many waves as
+	// needed, by WaitReplicasTimeout.
 	replCtx, replCancel := context.WithTimeout(context.Background(), opts.WaitReplicasTimeout)
 	primaryCtx, primaryCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer primaryCancel()
@@ -511,6 +1244,23 @@ func (erp *EmergencyReparenter) reparentReplicas(
 	replWg := sync.WaitGroup{}
 	rec := concurrency.AllErrorRecorder{}
 
+	var replicas []string
+	for alias, ti := range tabletMap {
+		switch {
+		case alias == topoproto.TabletAliasString(newPrimaryTablet.Alias):
+			continue
+		case opts.IgnoreReplicas.Has(alias):
+			continue
+		case !intermediateReparent && skipReattach(ti.Tablet, opts):
+			ev.SkippedReplicas = append(ev.SkippedReplicas, alias)
+		default:
+			replicas = append(replicas, alias)
+		}
+	}
+	slices.Sort(ev.SkippedReplicas)
+	numReplicas := len(replicas)
+	inWaves := opts.ReparentBatchSize > 0 && numReplicas > opts.ReparentBatchSize
+
 	handlePrimary := func(alias string, tablet *topodatapb.Tablet) error {
 		if !intermediateReparent {
 			var position string
@@ -527,16 +1277,26 @@ func (erp *EmergencyReparenter) reparentReplicas(
 			if err != nil {
 				return vterrors.Wrapf(err, "primary-elect tablet %v failed to be upgraded to primary: %v", alias, err)
 			}
+			ev.PromotionResult = position
+			if position == "" {
+				ev.Warnings = append(ev.Warnings, fmt.Sprintf("new primary %v did not return a position when promoted", alias))
+			}
+			if opts.VerifySemiSyncEnabled {
+				if err = erp.verifySemiSyncEnabled(primaryCtx, tablet, opts); err != nil {
+					return err
+				}
+			}
 			erp.logger.Infof("populating reparent journal on new primary %v", alias)
 			err = erp.tmc.PopulateReparentJournal(primaryCtx, tablet, now, opts.lockAction, tablet.Alias, position)
 			if err != nil {
 				return vterrors.Wrapf(err, "failed to PopulateReparentJournal on primary: %v", err)
 			}
+			ev.ReparentJournalPopulated = true
 		}
 		return nil
 	}
 
-	handleReplica := func(alias string, ti *topo.TabletInfo) {
+	handleReplica := func(ctx context.Context, alias string, ti *topo.TabletInfo) {
 		defer replWg.Done()
 		erp.logger.Infof("setting new primary on replica %v", alias)
 
@@ -553,7 +1313,7 @@ func (erp *EmergencyReparenter) reparentReplicas(
 			forceStart = fs
 		}
 
-		err := erp.tmc.SetReplicationSource(replCtx, ti.Tablet, newPrimaryTablet.Alias, 0, "", forceStart, IsReplicaSemiSync(opts.durability, newPrimaryTablet, ti.Tablet), 0)
+		err := erp.tmc.SetReplicationSource(ctx, ti.Tablet, newPrimaryTablet.Alias, 0, "", forceStart, IsReplicaSemiSync(opts.durability, newPrimaryTablet, ti.Tablet), 0)
 		if err != nil {
 			err = vterrors.Wrapf(err, "tablet %v SetReplicationSource failed: %v", alias, err)
 			rec.RecordError(err)
@@ -567,37 +1327,73 @@ func (erp *EmergencyReparenter) reparentReplicas(
 
 		// Signal that at least one goroutine succeeded to SetReplicationSource.
 		// We do this only when we do not want to wait for all the replicas.
-		if !intermediateReparent {
+		if !intermediateReparent && !inWaves {
 			replSuccessCancel()
 		}
 	}
 
-	numReplicas := 0
+	// reparentBatch reparents a wave of replicas.
+	reparentBatch := func(batch []string) {
+		batchWg := sync.WaitGroup{}
+		for _, alias := range batch {
+			batchWg.Add(1)
+			go func(alias string) {
+				defer batchWg.Done()
+				handleReplica(replCtx, alias, tabletMap[alias])
+			}(alias)
+		}
+		batchWg.Wait()
+	}
 
-	for alias, ti := range tabletMap {
-		switch {
-		case alias == topoproto.TabletAliasString(newPrimaryTablet.Alias):
-			continue
-		case !opts.IgnoreReplicas.Has(alias):
-			replWg.Add(1)
-			numReplicas++
-			go handleReplica(alias, ti)
+	startReplicas := func() {
+		replWg.Add(numReplicas)
+
+		if !inWaves {
+			go reparentBatch(replicas)
+		} else {
+			// Reparent the replicas in waves, so that the new primary doesn't have
+			// to serve all of them at once. We wait for all of them, so the shard is
+			// still locked when the later waves start, but no new wave is started
+			// once the reparent is aborted.
+			slices.Sort(replicas)
+			go func() {
+				for start := 0; start < numReplicas; start += opts.ReparentBatchSize {
+					batch := replicas[start:min(start+opts.ReparentBatchSize, numReplicas)]
+					if err := ctx.Err(); err != nil {
+						erp.logger.Warningf("not reparenting replicas %v: %v", batch, err)
+						for range batch {
+							rec.RecordError(vterrors.Wrapf(err, "reparent aborted before reparenting replicas %v: %v", batch, err))
+							replWg.Done()
+						}
+						continue
+					}
+					erp.logger.Infof("reparenting replicas %v", batch)
+					reparentBatch(batch)
+				}
+			}()
 		}
+
+		// Spin up a background goroutine to wait until all replica goroutines
+		// finished. Polling this way allows us to have reparentReplicas return
+		// success as soon as (a) the primary successfully populates its reparent
+		// journal and (b) at least one replica successfully begins replicating.
+		//
+		// If we were to follow the more common pattern of blocking on replWg.Wait()
+		// in the main body of promoteNewPrimary, we would be bound to the
+		// time of slowest replica, instead of the time of the fastest successful
+		// replica, and we want ERS to be fast.
+		go func() {
+			replWg.Wait()
+			allReplicasDoneCancel()
+		}()
 	}
 
-	// Spin up a background goroutine to wait until all replica goroutines
-	// finished. Polling this way allows us to have reparentReplicas return
-	// success as soon as (a) the primary successfully populates its reparent
-	// journal and (b) at least one replica successfully begins replicating.
-	//
-	// If we were to follow the more common pattern of blocking on replWg.Wait()
-	// in the main body of promoteNewPrimary, we would be bound to the
-	// time of slowest replica, instead of the time of the fastest successful
-	// replica, and we want ERS to be fast.
-	go func() {
-		replWg.Wait()
-		allReplicasDoneCancel()
-	}()
+	// The replicas are reparented while the primary is being promoted, unless
+	// OnPrimaryPromoted must run in between.
+	notifyPromoted := opts.OnPrimaryPromoted != nil && !intermediateReparent
+	if !notifyPromoted {
+		startReplicas()
+	}
 
 	primaryErr := handlePrimary(topoproto.TabletAliasString(newPrimaryTablet.Alias), newPrimaryTablet)
 	if primaryErr != nil {
@@ -607,6 +1403,16 @@ func (erp *EmergencyReparenter) reparentReplicas(
 		return nil, vterrors.Wrapf(primaryErr, "failed to promote %v to primary", topoproto.TabletAliasString(newPrimaryTablet.Alias))
 	}
 
+	if notifyPromoted {
+		if err := erp.notifyPrimaryPromoted(ctx, ev, newPrimaryTablet, opts); err != nil {
+			erp.logger.Errorf("aborting emergency reparent: %v", err)
+			replCancel()
+
+			return nil, err
+		}
+		startReplicas()
+	}
+
 	// We should only cancel the context that all the replicas are using when they are done.
 	// Since this function can return early when only 1 replica succeeds, if we cancel this context as a deferred call from this function,
 	// then we would end up having cancelled the context for the replicas who have not yet finished running all the commands.
@@ -616,7 +1422,17 @@ func (erp *EmergencyReparenter) reparentReplicas(
 		defer replCancel()
 	}()
 
+	// The replicas do not use ctx, so that they can go on once we return, but
+	// we must not wait on them once the reparent is aborted, by OverallTimeout
+	// or by a concurrent reparent.
+	var abortCh <-chan struct{}
+	if opts.OverallTimeout > 0 || opts.AbortOnConcurrentReparent {
+		abortCh = ctx.Done()
+	}
+
 	select {
+	case <-abortCh:
+		return nil, vterrors.Errorf(vterrors.Code(ctx.Err()), "failed to wait for replicas to replicate from %v: %v", topoproto.TabletAliasString(newPrimaryTablet.Alias), ctx.Err())
 	case <-replSuccessCtx.Done():
 		// At least one replica was able to SetReplicationSource successfully
 		// Here we do not need to return the replicas which started replicating
@@ -635,6 +1451,10 @@ func (erp *EmergencyReparenter) reparentReplicas(
 			// we're going to be explicit that this is doubly unexpected.
 			return nil, vterrors.Wrapf(rec.Error(), "received more errors (= %d) than replicas (= %d), which should be impossible: %v", errCount, numReplicas, rec.Error())
 		case errCount == numReplicas:
+			if numReplicas == 0 && len(ev.SkippedReplicas) > 0 {
+				// All the replicas were skipped, there is nothing to wait for.
+				return nil, nil
+			}
 			if len(tabletMap) <= 2 {
 				// If there are at most 2 tablets in the tablet map, we shouldn't be failing the promotion if the replica fails to SetReplicationSource.
 				// The failing replica is probably the old primary that is down, so it is okay if it fails. We still log a warning message in the logs.
@@ -649,22 +1469,115 @@ func (erp *EmergencyReparenter) reparentReplicas(
 
 }
 
-// isIntermediateSourceIdeal is used to find whether the intermediate source that ERS chose is also the ideal one or not
-func (erp *EmergencyReparenter) isIntermediateSourceIdeal(
-	intermediateSource *topodatapb.Tablet,
-	validCandidates []*topodatapb.Tablet,
+// replicaConvergenceInterval is how long verifyReplicasConverging waits between
+// the two samples of the position of a replica that is not caught up yet.
+var replicaConvergenceInterval = time.Second
+
+// verifyReplicasConverging checks that the replicas of the new primary are either caught up with it,
+// or making progress towards it. It samples the position of every replica, and samples it again after
+// replicaConvergenceInterval for the ones that are behind. A warning is returned for every replica
+// whose position could not be read or did not advance.
+func (erp *EmergencyReparenter) verifyReplicasConverging(
+	ctx context.Context,
+	newPrimary *topodatapb.Tablet,
 	tabletMap map[string]*topo.TabletInfo,
 	opts EmergencyReparentOptions,
-) (bool, error) {
-	// we try to find a better candidate with the current list of valid candidates, and if it matches our current primary candidate, then we return true
-	candidate, err := erp.identifyPrimaryCandidate(intermediateSource, validCandidates, tabletMap, opts)
+) []string {
+	primaryAlias := topoproto.TabletAliasString(newPrimary.Alias)
+	primaryCtx, primaryCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer primaryCancel()
+	primaryPosStr, err := erp.tmc.PrimaryPosition(primaryCtx, newPrimary)
+	if err != nil {
+		return []string{fmt.Sprintf("could not verify replicas are converging: failed to get the position of the new primary %v: %v", primaryAlias, err)}
+	}
+	primaryPos, err := replication.DecodePosition(primaryPosStr)
+	if err != nil {
+		return []string{fmt.Sprintf("could not verify replicas are converging: failed to decode the position of the new primary %v: %v", primaryAlias, err)}
+	}
+
+	samplePosition := func(tablet *topodatapb.Tablet) (replication.Position, error) {
+		sampleCtx, sampleCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		defer sampleCancel()
+		status, err := erp.tmc.ReplicationStatus(sampleCtx, tablet)
+		if err != nil {
+			return replication.Position{}, err
+		}
+		return replication.DecodePosition(status.Position)
+	}
+
+	var (
+		warnings []string
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+	)
+	warn := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	for alias, info := range tabletMap {
+		if alias == primaryAlias || opts.IgnoreReplicas.Has(alias) || skipReattach(info.Tablet, opts) {
+			continue
+		}
+		wg.Add(1)
+		go func(alias string, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			before, err := samplePosition(tablet)
+			if err != nil {
+				warn("failed to get the position of replica %v: %v", alias, err)
+				return
+			}
+			if before.AtLeast(primaryPos) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				warn("failed to verify replica %v is converging: %v", alias, ctx.Err())
+				return
+			case <-time.After(replicaConvergenceInterval):
+			}
+
+			after, err := samplePosition(tablet)
+			if err != nil {
+				warn("failed to get the position of replica %v: %v", alias, err)
+				return
+			}
+			if !after.AtLeast(primaryPos) && after.Equal(before) {
+				warn("replica %v appears stuck at position %v and is not converging towards the new primary %v at position %v", alias, after, primaryAlias, primaryPos)
+			}
+		}(alias, info.Tablet)
+	}
+	wg.Wait()
+
+	slices.Sort(warnings)
+	for _, warning := range warnings {
+		erp.logger.Warningf("%s", warning)
+	}
+	return warnings
+}
+
+// verifySemiSyncEnabled checks that the given newly promoted primary has semi-sync enabled,
+// if the durability policy requires semi-sync ackers for it.
+func (erp *EmergencyReparenter) verifySemiSyncEnabled(ctx context.Context, primary *topodatapb.Tablet, opts EmergencyReparentOptions) error {
+	ackers := SemiSyncAckers(opts.durability, primary)
+	if ackers == 0 {
+		return nil
+	}
+	alias := topoproto.TabletAliasString(primary.Alias)
+	status, err := erp.tmc.FullStatus(ctx, primary)
 	if err != nil {
-		return false, err
+		return vterrors.Wrapf(err, "failed to get the status of the new primary %v to verify semi-sync: %v", alias, err)
+	}
+	if !status.SemiSyncPrimaryEnabled {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "new primary %v does not have semi-sync enabled after promotion, but the durability policy requires %d semi-sync acker(s)", alias, ackers)
 	}
-	return candidate == intermediateSource, nil
+	return nil
 }
 
-// identifyPrimaryCandidate is used to find the final candidate for ERS promotion
+// identifyPrimaryCandidate is used to find the final candidate for ERS promotion.
+// Candidates vetoed by opts.VetoCandidate are skipped in favour of the next best one.
 func (erp *EmergencyReparenter) identifyPrimaryCandidate(
 	intermediateSource *topodatapb.Tablet,
 	validCandidates []*topodatapb.Tablet,
@@ -677,6 +1590,38 @@ func (erp *EmergencyReparenter) identifyPrimaryCandidate(
 		}
 	}()
 
+	for {
+		candidate, err = erp.findPrimaryCandidate(intermediateSource, validCandidates, tabletMap, opts)
+		if err != nil || opts.VetoCandidate == nil {
+			return candidate, err
+		}
+
+		vetoErr := opts.VetoCandidate(candidate)
+		if vetoErr == nil {
+			return candidate, nil
+		}
+		candidateAlias := topoproto.TabletAliasString(candidate.Alias)
+		if opts.NewPrimaryAlias != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "requested candidate %v was vetoed: %v", candidateAlias, vetoErr)
+		}
+		erp.logger.Infof("candidate %v was vetoed: %v", candidateAlias, vetoErr)
+
+		validCandidates = slices.DeleteFunc(slices.Clone(validCandidates), func(tablet *topodatapb.Tablet) bool {
+			return topoproto.TabletAliasEqual(tablet.Alias, candidate.Alias)
+		})
+		if len(validCandidates) == 0 {
+			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "all the valid candidates for emergency reparent were vetoed, the last one being %v: %v", candidateAlias, vetoErr)
+		}
+	}
+}
+
+// findPrimaryCandidate finds the best candidate for ERS promotion among the valid candidates.
+func (erp *EmergencyReparenter) findPrimaryCandidate(
+	intermediateSource *topodatapb.Tablet,
+	validCandidates []*topodatapb.Tablet,
+	tabletMap map[string]*topo.TabletInfo,
+	opts EmergencyReparentOptions,
+) (candidate *topodatapb.Tablet, err error) {
 	if len(validCandidates) == 0 {
 		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates for emergency reparent")
 	}
@@ -716,11 +1661,58 @@ func (erp *EmergencyReparenter) identifyPrimaryCandidate(
 	return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "unreachable - did not find a valid primary candidate even though the valid candidate list was non-empty")
 }
 
+// checkDurabilityReachable returns an error if none of the tablets of the shard could be promoted and make
+// forward progress under the durability policy, even if all the tablets that are not ignored were reachable.
+// If a specific tablet was requested, only that one is checked.
+func checkDurabilityReachable(durability Durabler, durabilityName string, tabletMap map[string]*topo.TabletInfo, opts EmergencyReparentOptions) error {
+	var tablets []*topodatapb.Tablet
+	for alias, tabletInfo := range tabletMap {
+		if !opts.IgnoreReplicas.Has(alias) {
+			tablets = append(tablets, tabletInfo.Tablet)
+		}
+	}
+
+	if opts.NewPrimaryAlias != nil {
+		requestedPrimaryAlias := topoproto.TabletAliasString(opts.NewPrimaryAlias)
+		requestedPrimaryInfo, ok := tabletMap[requestedPrimaryAlias]
+		if !ok {
+			// the tablet not being found is reported when looking for the candidates.
+			return nil
+		}
+		if PromotionRule(durability, requestedPrimaryInfo.Tablet) == promotionrule.MustNot {
+			return vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s has a must not promotion rule", requestedPrimaryAlias)
+		}
+		if !canEstablishForTablet(durability, requestedPrimaryInfo.Tablet, tablets) {
+			return vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s will not be able to make forward progress on being promoted", requestedPrimaryAlias)
+		}
+		return nil
+	}
+
+	for _, tablet := range tablets {
+		if PromotionRule(durability, tablet) != promotionrule.MustNot && canEstablishForTablet(durability, tablet, tablets) {
+			return nil
+		}
+	}
+	return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no candidate can satisfy durability policy %v", durabilityName)
+}
+
 // filterValidCandidates filters valid tablets, keeping only the ones which can successfully be promoted without any constraint failures and can make forward progress on being promoted
 func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb.Tablet, tabletsReachable []*topodatapb.Tablet, prevPrimary *topodatapb.Tablet, opts EmergencyReparentOptions) ([]*topodatapb.Tablet, error) {
-	var restrictedValidTablets []*topodatapb.Tablet
+	var (
+		restrictedValidTablets []*topodatapb.Tablet
+		avoidedTablets         []string
+	)
 	for _, tablet := range validTablets {
 		tabletAliasStr := topoproto.TabletAliasString(tablet.Alias)
+		// Remove tablets which we were asked to avoid promoting
+		if opts.AvoidPrimaryAliases.Has(tabletAliasStr) {
+			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it is in the avoid list", tabletAliasStr)
+			if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
+				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s is in the avoid list", tabletAliasStr)
+			}
+			avoidedTablets = append(avoidedTablets, tabletAliasStr)
+			continue
+		}
 		// Remove tablets which have MustNot promote rule since they must never be promoted
 		if PromotionRule(opts.durability, tablet) == promotionrule.MustNot {
 			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it has the Must Not promote rule", tabletAliasStr)
@@ -747,5 +1739,30 @@ func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb
 		}
 		restrictedValidTablets = append(restrictedValidTablets, tablet)
 	}
+	if len(restrictedValidTablets) == 0 && len(avoidedTablets) > 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "all candidates are in avoid list: %v", strings.Join(avoidedTablets, ", "))
+	}
+	if opts.ioErroredReplicas.Len() > 0 {
+		restrictedValidTablets = erp.excludeIOErroredTablets(restrictedValidTablets, opts)
+	}
 	return restrictedValidTablets, nil
 }
+
+// excludeIOErroredTablets removes the tablets whose IO thread was reporting an error before replication was stopped,
+// unless they were explicitly requested or no other tablet would be left.
+func (erp *EmergencyReparenter) excludeIOErroredTablets(tablets []*topodatapb.Tablet, opts EmergencyReparentOptions) []*topodatapb.Tablet {
+	var healthyTablets []*topodatapb.Tablet
+	for _, tablet := range tablets {
+		tabletAliasStr := topoproto.TabletAliasString(tablet.Alias)
+		if opts.ioErroredReplicas.Has(tabletAliasStr) && !topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
+			erp.logger.Infof("Removing %s from list of valid candidates for promotion because its IO thread was erroring", tabletAliasStr)
+			continue
+		}
+		healthyTablets = append(healthyTablets, tablet)
+	}
+	if len(healthyTablets) == 0 {
+		erp.logger.Warningf("All the valid candidates for promotion had an erroring IO thread, keeping them as candidates")
+		return tablets
+	}
+	return healthyTablets
+}
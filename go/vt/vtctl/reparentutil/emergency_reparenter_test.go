@@ -17,17 +17,26 @@ limitations under the License.
 package reparentutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/mysql/replication"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
@@ -36,10 +45,12 @@ import (
 	"vitess.io/vitess/go/vt/topotools/events"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/reparenttestutil"
+	"vitess.io/vitess/go/vt/vterrors"
 
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 func TestNewEmergencyReparenter(t *testing.T) {
@@ -123,11 +134,122 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 		// results
 		shouldErr        bool
 		errShouldContain string
+		semiSyncAckers   []string
 	}{
 		{
 			name:                 "success",
 			durability:           "none",
-			emergencyReparentOps: EmergencyReparentOptions{},
+			emergencyReparentOps: EmergencyReparentOptions{WaitReplicasTimeout: time.Minute},
+			tmc: &testutil.TabletManagerClient{
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000102": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000102": {
+						Result: "ok",
+						Error:  nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000100": nil,
+					"zone1-0000000101": nil,
+				},
+				StopReplicationAndGetStatusResults: map[string]struct {
+					StopStatus *replicationdatapb.StopReplicationStatus
+					Error      error
+				}{
+					"zone1-0000000100": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+							},
+						},
+					},
+					"zone1-0000000101": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+							},
+						},
+					},
+					"zone1-0000000102": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+							},
+						},
+					},
+				},
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000100": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+					},
+					"zone1-0000000101": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+					},
+					"zone1-0000000102": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+					},
+				},
+			},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
+						PrimaryAlias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Hostname: "most up-to-date position, wins election",
+				},
+			},
+			keyspace:  "testkeyspace",
+			shard:     "-",
+			cells:     []string{"zone1"},
+			shouldErr: false,
+		},
+		{
+			name:                 "success - non-serving shard with AllowNonServing",
+			durability:           "none",
+			emergencyReparentOps: EmergencyReparentOptions{AllowNonServing: true},
 			tmc: &testutil.TabletManagerClient{
 				PopulateReparentJournalResults: map[string]error{
 					"zone1-0000000102": nil,
@@ -233,6 +355,39 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			cells:     []string{"zone1"},
 			shouldErr: false,
 		},
+		{
+			name:                 "non-serving shard",
+			durability:           "none",
+			emergencyReparentOps: EmergencyReparentOptions{},
+			tmc:                  &testutil.TabletManagerClient{},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+					Shard: &topodatapb.Shard{
+						PrimaryAlias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+			},
+			keyspace:         "testkeyspace",
+			shard:            "-",
+			cells:            []string{"zone1"},
+			shouldErr:        true,
+			errShouldContain: "refusing to reparent a non-serving shard",
+		},
 		{
 			name:                 "success - 1 replica and 1 rdonly failure",
 			durability:           "semi_sync",
@@ -309,6 +464,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  100,
@@ -360,10 +516,11 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Shard:    "-",
 				},
 			},
-			keyspace:  "testkeyspace",
-			shard:     "-",
-			cells:     []string{"zone1"},
-			shouldErr: false,
+			keyspace:       "testkeyspace",
+			shard:          "-",
+			cells:          []string{"zone1"},
+			shouldErr:      false,
+			semiSyncAckers: []string{"zone1-0000000100", "zone1-0000000101"},
 		},
 		{
 			// Here, all our tablets are tied, so we're going to explicitly pick
@@ -440,6 +597,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  100,
@@ -549,6 +707,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  100,
@@ -589,6 +748,123 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			cells:     []string{"zone1"},
 			shouldErr: false,
 		},
+		{
+			name:       "requested primary without a readable demoted position",
+			durability: "none",
+			emergencyReparentOps: EmergencyReparentOptions{
+				RequireDemotedPrimaryPosition: true,
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			tmc: &testutil.TabletManagerClient{
+				DemotePrimaryResults: map[string]struct {
+					Status *replicationdatapb.PrimaryStatus
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Status: &replicationdatapb.PrimaryStatus{},
+					},
+				},
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000102": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000102": {
+						Result: "ok",
+						Error:  nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000100": nil,
+					"zone1-0000000101": nil,
+				},
+				StopReplicationAndGetStatusResults: map[string]struct {
+					StopStatus *replicationdatapb.StopReplicationStatus
+					Error      error
+				}{
+					"zone1-0000000100": { // This tablet claims PRIMARY, so is not running replication.
+						Error: mysql.ErrNotReplica,
+					},
+					"zone1-0000000101": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+							},
+						},
+					},
+					"zone1-0000000102": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+							},
+						},
+					},
+				},
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000101": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+					},
+					"zone1-0000000102": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+					},
+				},
+			},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
+						PrimaryAlias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_PRIMARY,
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Hostname: "most up-to-date position, wins election",
+				},
+			},
+			keyspace:         "testkeyspace",
+			shard:            "-",
+			cells:            []string{"zone1"},
+			shouldErr:        true,
+			errShouldContain: "requested primary elect zone1-0000000100 claims to be a primary but did not return a readable position when demoted",
+		},
 		{
 			name:                 "shard not found",
 			durability:           "none",
@@ -809,7 +1085,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			keyspace:         "testkeyspace",
 			shard:            "-",
 			cells:            []string{"zone1"},
-			errShouldContain: "no valid candidates for emergency reparent",
+			errShouldContain: "no candidate can satisfy durability policy none",
 		},
 		{
 			name:       "error waiting for relay logs to apply",
@@ -1083,6 +1359,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  100,
@@ -1192,6 +1469,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  100,
@@ -1337,7 +1615,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			shard:            "-",
 			cells:            []string{"zone1"},
 			shouldErr:        true,
-			errShouldContain: "no valid candidates for emergency reparent",
+			errShouldContain: "no candidate can satisfy durability policy none",
 		},
 		{
 			name:       "proposed primary - must not promotion rule",
@@ -1521,6 +1799,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone2",
 							Uid:  100,
@@ -1646,6 +1925,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone2",
 							Uid:  100,
@@ -1770,6 +2050,7 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 					Keyspace: "testkeyspace",
 					Name:     "-",
 					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
 						PrimaryAlias: &topodatapb.TabletAlias{
 							Cell: "zone1",
 							Uid:  101,
@@ -1810,6 +2091,132 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			shouldErr:        true,
 			errShouldContain: "proposed primary zone1-0000000102 will not be able to make forward progress on being promoted",
 		},
+		{
+			name:       "cross cell durability records the semi-sync ackers of the new primary",
+			durability: "cross_cell",
+			emergencyReparentOps: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+				WaitReplicasTimeout: time.Minute,
+			},
+			tmc: &testutil.TabletManagerClient{
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000102": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000102": {
+						Result: "ok",
+						Error:  nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000101": nil,
+					"zone2-0000000200": nil,
+				},
+				StopReplicationAndGetStatusResults: map[string]struct {
+					StopStatus *replicationdatapb.StopReplicationStatus
+					Error      error
+				}{
+					"zone1-0000000101": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+							},
+						},
+					},
+					"zone1-0000000102": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+							},
+						},
+					},
+					"zone2-0000000200": {
+						StopStatus: &replicationdatapb.StopReplicationStatus{
+							Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+							After: &replicationdatapb.Status{
+								SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+								RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+							},
+						},
+					},
+				},
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000101": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+					},
+					"zone1-0000000102": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+					},
+					"zone2-0000000200": {
+						"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+					},
+				},
+			},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
+						PrimaryAlias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_PRIMARY,
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Hostname: "proposed primary",
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone2",
+						Uid:  200,
+					},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+			},
+			keyspace:       "testkeyspace",
+			shard:          "-",
+			cells:          []string{"zone1", "zone2"},
+			shouldErr:      false,
+			semiSyncAckers: []string{"zone2-0000000200"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1856,6 +2263,282 @@ func TestEmergencyReparenter_reparentShardLocked(t *testing.T) {
 			}
 
 			assert.NoError(t, err)
+			assert.Equal(t, tt.emergencyReparentOps.WaitReplicasTimeout, ev.WaitReplicasTimeout)
+			assert.Positive(t, ev.ReplicaWaitTime)
+			assert.Equal(t, tt.semiSyncAckers, ev.SemiSyncAckers)
+		})
+	}
+}
+
+func TestEmergencyReparenter_abortOnConcurrentReparent(t *testing.T) {
+	defer func(interval time.Duration) {
+		shardTermCheckInterval = interval
+	}(shardTermCheckInterval)
+	shardTermCheckInterval = 10 * time.Millisecond
+
+	stopStatus := func(position string) struct {
+		StopStatus *replicationdatapb.StopReplicationStatus
+		Error      error
+	} {
+		return struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			StopStatus: &replicationdatapb.StopReplicationStatus{
+				Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+				After: &replicationdatapb.Status{
+					SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+					RelayLogPosition: position,
+				},
+			},
+		}
+	}
+	tmc := &testutil.TabletManagerClient{
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000102": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000102": {
+				Result: "ok",
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000101": nil,
+		},
+		StopReplicationAndGetStatusResults: map[string]struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+			"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000101": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+			},
+			"zone1-0000000102": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+			},
+		},
+		// applying the relay logs takes long enough for the concurrent reparent to happen
+		WaitForPositionDelays: map[string]time.Duration{
+			"zone1-0000000102": 10 * time.Second,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+		Shard: &topodatapb.Shard{
+			IsPrimaryServing: true,
+			PrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		},
+	})
+	for _, uid := range []uint32{100, 101, 102} {
+		testutil.AddTablets(ctx, t, ts, nil, &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		})
+	}
+	reparenttestutil.SetKeyspaceDurability(ctx, t, ts, "testkeyspace", "none")
+
+	lctx, unlock, lerr := ts.LockShard(ctx, "testkeyspace", "-", "test lock")
+	require.NoError(t, lerr)
+	defer unlock(&lerr)
+
+	// another reparent promotes zone1-0000000101 while we are waiting for the relay logs to apply
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, err := ts.UpdateShardFields(ctx, "testkeyspace", "-", func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}
+			si.PrimaryTermStartTime = protoutil.TimeToProto(time.Now())
+			return nil
+		})
+		assert.NoError(t, err)
+	}()
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	err := erp.reparentShardLocked(lctx, &events.Reparent{}, "testkeyspace", "-", EmergencyReparentOptions{
+		WaitReplicasTimeout:       time.Minute,
+		AbortOnConcurrentReparent: true,
+	})
+	assert.ErrorContains(t, err, "concurrent reparent detected: the primary of shard testkeyspace/- changed to zone1-0000000101")
+	// we did not wait for the relay logs to be applied
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+// shardTermTMC updates the shard record the way the tablets would: PromoteReplica
+// makes the promoted tablet the primary of the shard, and SetReplicationSource
+// on concurrentPrimary makes it the primary, as another reparent would.
+type shardTermTMC struct {
+	*testutil.TabletManagerClient
+	ts                *topo.Server
+	concurrentPrimary string
+}
+
+func (tmc *shardTermTMC) setShardPrimary(ctx context.Context, alias *topodatapb.TabletAlias) error {
+	_, err := tmc.ts.UpdateShardFields(ctx, "testkeyspace", "-", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = alias
+		si.PrimaryTermStartTime = protoutil.TimeToProto(time.Now())
+		return nil
+	})
+	return err
+}
+
+func (tmc *shardTermTMC) PromoteReplica(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool) (string, error) {
+	if err := tmc.setShardPrimary(ctx, tablet.Alias); err != nil {
+		return "", err
+	}
+	return tmc.TabletManagerClient.PromoteReplica(ctx, tablet, semiSync)
+}
+
+func (tmc *shardTermTMC) SetReplicationSource(ctx context.Context, tablet *topodatapb.Tablet, parent *topodatapb.TabletAlias, timeCreatedNS int64, waitPosition string, forceStartReplication bool, semiSync bool, heartbeatInterval float64) error {
+	if topoproto.TabletAliasString(tablet.Alias) == tmc.concurrentPrimary {
+		if err := tmc.setShardPrimary(ctx, tablet.Alias); err != nil {
+			return err
+		}
+	}
+	return tmc.TabletManagerClient.SetReplicationSource(ctx, tablet, parent, timeCreatedNS, waitPosition, forceStartReplication, semiSync, heartbeatInterval)
+}
+
+func TestEmergencyReparenter_abortOnConcurrentReparentWhileReparentingReplicas(t *testing.T) {
+	defer func(interval time.Duration) {
+		shardTermCheckInterval = interval
+	}(shardTermCheckInterval)
+	shardTermCheckInterval = 10 * time.Millisecond
+
+	tests := []struct {
+		name              string
+		concurrentPrimary string
+		errContains       string
+	}{
+		{
+			name: "promotion of the new primary is not a concurrent reparent",
+		},
+		{
+			name:              "concurrent reparent while pointing the replicas at the new primary",
+			concurrentPrimary: "zone1-0000000101",
+			errContains:       "concurrent reparent detected: the primary of shard testkeyspace/- changed to zone1-0000000101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopStatus := func(position string) struct {
+				StopStatus *replicationdatapb.StopReplicationStatus
+				Error      error
+			} {
+				return struct {
+					StopStatus *replicationdatapb.StopReplicationStatus
+					Error      error
+				}{
+					StopStatus: &replicationdatapb.StopReplicationStatus{
+						Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+						After: &replicationdatapb.Status{
+							SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+							RelayLogPosition: position,
+						},
+					},
+				}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+			tmc := &shardTermTMC{
+				TabletManagerClient: &testutil.TabletManagerClient{
+					PopulateReparentJournalResults: map[string]error{
+						"zone1-0000000102": nil,
+					},
+					PromoteReplicaResults: map[string]struct {
+						Result string
+						Error  error
+					}{
+						"zone1-0000000102": {
+							Result: "ok",
+						},
+					},
+					SetReplicationSourceResults: map[string]error{
+						"zone1-0000000101": nil,
+					},
+					// pointing the replica at the new primary takes long enough for the concurrent reparent to happen
+					SetReplicationSourceDelays: map[string]time.Duration{
+						"zone1-0000000101": time.Second,
+					},
+					StopReplicationAndGetStatusResults: map[string]struct {
+						StopStatus *replicationdatapb.StopReplicationStatus
+						Error      error
+					}{
+						"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+						"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+					},
+					WaitForPositionResults: map[string]map[string]error{
+						"zone1-0000000101": {
+							"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+						},
+						"zone1-0000000102": {
+							"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+						},
+					},
+				},
+				ts:                ts,
+				concurrentPrimary: tt.concurrentPrimary,
+			}
+
+			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+				Keyspace: "testkeyspace",
+				Name:     "-",
+				Shard: &topodatapb.Shard{
+					IsPrimaryServing: true,
+					PrimaryAlias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			})
+			for _, uid := range []uint32{100, 101, 102} {
+				testutil.AddTablets(ctx, t, ts, nil, &topodatapb.Tablet{
+					Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_REPLICA,
+				})
+			}
+			reparenttestutil.SetKeyspaceDurability(ctx, t, ts, "testkeyspace", "none")
+
+			lctx, unlock, lerr := ts.LockShard(ctx, "testkeyspace", "-", "test lock")
+			require.NoError(t, lerr)
+			defer unlock(&lerr)
+
+			erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+			start := time.Now()
+			err := erp.reparentShardLocked(lctx, &events.Reparent{}, "testkeyspace", "-", EmergencyReparentOptions{
+				WaitReplicasTimeout:       time.Minute,
+				AbortOnConcurrentReparent: true,
+			})
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errContains)
+			// we did not wait for the replica to be pointed at the new primary
+			assert.Less(t, time.Since(start), time.Second)
 		})
 	}
 }
@@ -1877,6 +2560,7 @@ func TestEmergencyReparenter_promotionOfNewPrimary(t *testing.T) {
 		shouldErr             bool
 		errShouldContain      string
 		initializationTest    bool
+		promotionResult       string
 	}{
 		{
 			name:                 "success",
@@ -1890,7 +2574,8 @@ func TestEmergencyReparenter_promotionOfNewPrimary(t *testing.T) {
 					Error  error
 				}{
 					"zone1-0000000100": {
-						Error: nil,
+						Result: "ok",
+						Error:  nil,
 					},
 				},
 				SetReplicationSourceResults: map[string]error{
@@ -1951,9 +2636,10 @@ func TestEmergencyReparenter_promotionOfNewPrimary(t *testing.T) {
 					},
 				},
 			},
-			keyspace:  "testkeyspace",
-			shard:     "-",
-			shouldErr: false,
+			keyspace:        "testkeyspace",
+			shard:           "-",
+			shouldErr:       false,
+			promotionResult: "ok",
 		},
 		{
 			name:                 "PromoteReplica error",
@@ -2291,6 +2977,7 @@ func TestEmergencyReparenter_promotionOfNewPrimary(t *testing.T) {
 			logger := logutil.NewMemoryLogger()
 			ev := &events.Reparent{ShardInfo: topo.ShardInfo{
 				Shard: &topodatapb.Shard{
+					IsPrimaryServing: true,
 					PrimaryAlias: &topodatapb.TabletAlias{
 						Cell: "zone1",
 						Uid:  100,
@@ -2336,6 +3023,8 @@ func TestEmergencyReparenter_promotionOfNewPrimary(t *testing.T) {
 			}
 
 			assert.NoError(t, err)
+			assert.Equal(t, tt.promotionResult, ev.PromotionResult)
+			assert.True(t, ev.ReparentJournalPopulated)
 		})
 	}
 }
@@ -2352,6 +3041,8 @@ func TestEmergencyReparenter_waitForAllRelayLogsToApply(t *testing.T) {
 		candidates map[string]replication.Position
 		tabletMap  map[string]*topo.TabletInfo
 		statusMap  map[string]*replicationdatapb.StopReplicationStatus
+		quorum     int
+		laggards   []string
 		shouldErr  bool
 	}{
 		{
@@ -2451,14 +3142,113 @@ func TestEmergencyReparenter_waitForAllRelayLogsToApply(t *testing.T) {
 			shouldErr: true,
 		},
 		{
-			name: "multiple tablets fail",
+			name: "one tablet fails, quorum reached",
 			tmc: &testutil.TabletManagerClient{
 				WaitForPositionResults: map[string]map[string]error{
 					"zone1-0000000100": {
 						"position1": nil,
 					},
 					"zone1-0000000101": {
-						"position2": nil,
+						"position1": nil,
+					},
+				},
+			},
+			candidates: map[string]replication.Position{
+				"zone1-0000000100": {},
+				"zone1-0000000101": {},
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+			},
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000100": {
+					After: &replicationdatapb.Status{
+						RelayLogPosition: "position1",
+					},
+				},
+				"zone1-0000000101": {
+					After: &replicationdatapb.Status{
+						RelayLogPosition: "position2", // cannot wait for the desired "position1", but one tablet is enough
+					},
+				},
+			},
+			quorum:    1,
+			laggards:  []string{"zone1-0000000101"},
+			shouldErr: false,
+		},
+		{
+			name: "one tablet fails, quorum not reached",
+			tmc: &testutil.TabletManagerClient{
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000100": {
+						"position1": nil,
+					},
+					"zone1-0000000101": {
+						"position1": nil,
+					},
+				},
+			},
+			candidates: map[string]replication.Position{
+				"zone1-0000000100": {},
+				"zone1-0000000101": {},
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+			},
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000100": {
+					After: &replicationdatapb.Status{
+						RelayLogPosition: "position1",
+					},
+				},
+				"zone1-0000000101": {
+					After: &replicationdatapb.Status{
+						RelayLogPosition: "position2", // cannot wait for the desired "position1", so we fail
+					},
+				},
+			},
+			quorum:    2,
+			shouldErr: true,
+		},
+		{
+			name: "multiple tablets fail",
+			tmc: &testutil.TabletManagerClient{
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000100": {
+						"position1": nil,
+					},
+					"zone1-0000000101": {
+						"position2": nil,
 					},
 					"zone1-0000000102": {
 						"position3": nil,
@@ -2573,19 +3363,79 @@ func TestEmergencyReparenter_waitForAllRelayLogsToApply(t *testing.T) {
 			t.Parallel()
 
 			erp := NewEmergencyReparenter(nil, tt.tmc, logger)
-			err := erp.waitForAllRelayLogsToApply(ctx, tt.candidates, tt.tabletMap, tt.statusMap, waitReplicasTimeout)
+			laggards, err := erp.waitForAllRelayLogsToApply(ctx, tt.candidates, tt.tabletMap, tt.statusMap, waitReplicasTimeout, tt.quorum)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
 			assert.NoError(t, err)
+			assert.Equal(t, tt.laggards, laggards)
 		})
 	}
 }
 
+func TestEmergencyReparenter_waitForAllRelayLogsToApplyQuorum(t *testing.T) {
+	tmc := &testutil.TabletManagerClient{
+		WaitForPositionDelays: map[string]time.Duration{
+			"zone1-0000000101": time.Minute,
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000100": {
+				"position1": nil,
+			},
+			"zone1-0000000101": {
+				"position1": nil,
+			},
+		},
+	}
+	tabletMap := map[string]*topo.TabletInfo{
+		"zone1-0000000100": {
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+		},
+		"zone1-0000000101": {
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+			},
+		},
+	}
+	statusMap := map[string]*replicationdatapb.StopReplicationStatus{
+		"zone1-0000000100": {
+			After: &replicationdatapb.Status{
+				RelayLogPosition: "position1",
+			},
+		},
+		"zone1-0000000101": {
+			After: &replicationdatapb.Status{
+				RelayLogPosition: "position1",
+			},
+		},
+	}
+	candidates := map[string]replication.Position{
+		"zone1-0000000100": {},
+		"zone1-0000000101": {},
+	}
+
+	erp := NewEmergencyReparenter(nil, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	laggards, err := erp.waitForAllRelayLogsToApply(context.Background(), candidates, tabletMap, statusMap, time.Minute, 1)
+	require.NoError(t, err)
+	// the slow tablet is not waited on once the quorum is reached
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, []string{"zone1-0000000101"}, laggards)
+}
+
 func TestEmergencyReparenterStats(t *testing.T) {
 	ersCounter.ResetAll()
+	ersBySourceCounter.ResetAll()
 	reparentShardOpTimings.Reset()
 
 	emergencyReparentOps := EmergencyReparentOptions{}
@@ -2703,8 +3553,9 @@ func TestEmergencyReparenterStats(t *testing.T) {
 	erp := NewEmergencyReparenter(ts, tmc, logger)
 
 	// run a successful ers
-	_, err := erp.ReparentShard(ctx, keyspace, shard, emergencyReparentOps)
+	ev, err := erp.ReparentShard(ctx, keyspace, shard, emergencyReparentOps)
 	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"StopReplicationAndGetStatus", "WaitForAllRelayLogsToApply", "FindMostAdvanced", "PromoteNewPrimary"}, maps.Keys(ev.Timings))
 
 	// check the counter values
 	require.EqualValues(t, map[string]int64{"testkeyspace.-.success": 1}, ersCounter.Counts())
@@ -2717,84 +3568,1270 @@ func TestEmergencyReparenterStats(t *testing.T) {
 	}
 
 	// run a failing ers
-	_, err = erp.ReparentShard(ctx, keyspace, shard, emergencyReparentOps)
+	ev, err = erp.ReparentShard(ctx, keyspace, shard, emergencyReparentOps)
 	require.Error(t, err)
+	// the timing of the failing step is recorded too
+	require.ElementsMatch(t, []string{"StopReplicationAndGetStatus", "WaitForAllRelayLogsToApply", "FindMostAdvanced"}, maps.Keys(ev.Timings))
 
 	// check the counter values
 	require.EqualValues(t, map[string]int64{"testkeyspace.-.success": 1, "testkeyspace.-.failure": 1}, ersCounter.Counts())
 	require.EqualValues(t, map[string]int64{"All": 2, "EmergencyReparentShard": 2}, reparentShardOpTimings.Counts())
+
+	// the operations without a source are counted as unknown
+	require.EqualValues(t, map[string]int64{"testkeyspace.-.unknown.success": 1, "testkeyspace.-.unknown.failure": 1}, ersBySourceCounter.Counts())
+
+	// run a failing ers tagged as automated
+	emergencyReparentOps.OperationSource = "auto"
+	_, err = erp.ReparentShard(ctx, keyspace, shard, emergencyReparentOps)
+	require.Error(t, err)
+
+	require.EqualValues(t, map[string]int64{"testkeyspace.-.success": 1, "testkeyspace.-.failure": 2}, ersCounter.Counts())
+	require.EqualValues(t, map[string]int64{
+		"testkeyspace.-.unknown.success": 1,
+		"testkeyspace.-.unknown.failure": 1,
+		"testkeyspace.-.auto.failure":    1,
+	}, ersBySourceCounter.Counts())
 }
 
-func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
-	sid1 := replication.SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
-	mysqlGTID1 := replication.Mysql56GTID{
-		Server:   sid1,
-		Sequence: 9,
+func TestEmergencyReparenterDryRun(t *testing.T) {
+	ersCounter.ResetAll()
+
+	running := func(relayLogPosition string) struct {
+		Position *replicationdatapb.Status
+		Error    error
+	} {
+		return struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			Position: &replicationdatapb.Status{
+				IoState:          int32(replication.ReplicationStateRunning),
+				SqlState:         int32(replication.ReplicationStateRunning),
+				SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+				RelayLogPosition: relayLogPosition,
+			},
+		}
 	}
-	mysqlGTID2 := replication.Mysql56GTID{
-		Server:   sid1,
-		Sequence: 10,
+	// Only the replication status of the tablets can be read: any call that
+	// would stop replication, promote a tablet or repoint one fails.
+	tmc := &testutil.TabletManagerClient{
+		ReplicationStatusResults: map[string]struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			"zone1-0000000100": running("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20"),
+			"zone1-0000000101": running("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+			"zone1-0000000102": running("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+		},
 	}
-	mysqlGTID3 := replication.Mysql56GTID{
-		Server:   sid1,
-		Sequence: 11,
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "most up-to-date position, wins election",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  103,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "unreachable",
+		},
 	}
 
-	positionMostAdvanced := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
-	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID1)
-	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID2)
-	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	positionIntermediate1 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
-	positionIntermediate1.GTIDSet = positionIntermediate1.GTIDSet.AddGTID(mysqlGTID1)
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+	})
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
 
-	positionIntermediate2 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
-	positionIntermediate2.GTIDSet = positionIntermediate2.GTIDSet.AddGTID(mysqlGTID1)
-	positionIntermediate2.GTIDSet = positionIntermediate2.GTIDSet.AddGTID(mysqlGTID2)
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	ev, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{DryRun: true})
+	require.NoError(t, err)
 
-	positionOnly2 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
-	positionOnly2.GTIDSet = positionOnly2.GTIDSet.AddGTID(mysqlGTID2)
+	assert.Equal(t, "zone1-0000000102", topoproto.TabletAliasString(ev.PlannedPrimary.Alias))
+	assert.Nil(t, ev.NewPrimary)
+	assert.Equal(t, []events.ReparentCandidate{
+		{Alias: "zone1-0000000102", Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-26"},
+		{Alias: "zone1-0000000101", Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-21"},
+		{Alias: "zone1-0000000100", Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-20"},
+	}, ev.Candidates)
+	// the replicas reached while gathering their status are expected to follow the planned primary.
+	assert.Equal(t, []string{"zone1-0000000100", "zone1-0000000101"}, ev.PredictedReplicas)
+	assert.Equal(t, []string{"zone1-0000000103"}, ev.PredictedFailedReplicas)
 
-	positionEmpty := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+	si, err := ts.GetShard(ctx, "testkeyspace", "-")
+	require.NoError(t, err)
+	assert.Equal(t, "zone1-0000000100", topoproto.TabletAliasString(si.PrimaryAlias))
+	assert.Empty(t, ersCounter.Counts())
+}
 
+func TestEmergencyReparenterBailIfHealthy(t *testing.T) {
 	tests := []struct {
-		name                 string
-		validCandidates      map[string]replication.Position
-		tabletMap            map[string]*topo.TabletInfo
-		emergencyReparentOps EmergencyReparentOptions
-		result               *topodatapb.Tablet
-		err                  string
+		name       string
+		fullStatus *replicationdatapb.FullStatus
+		noop       bool
 	}{
 		{
-			name: "choose most advanced",
-			validCandidates: map[string]replication.Position{
-				"zone1-0000000100": positionMostAdvanced,
-				"zone1-0000000101": positionIntermediate1,
-				"zone1-0000000102": positionIntermediate2,
-			},
-			tabletMap: map[string]*topo.TabletInfo{
-				"zone1-0000000100": {
-					Tablet: &topodatapb.Tablet{
-						Alias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  100,
-						},
-					},
+			name: "healthy primary",
+			fullStatus: &replicationdatapb.FullStatus{
+				PrimaryStatus: &replicationdatapb.PrimaryStatus{
+					Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
 				},
-				"zone1-0000000101": {
-					Tablet: &topodatapb.Tablet{
-						Alias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  101,
-						},
-					},
+			},
+			noop: true,
+		},
+		{
+			name: "read-only primary",
+			fullStatus: &replicationdatapb.FullStatus{
+				PrimaryStatus: &replicationdatapb.PrimaryStatus{
+					Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
 				},
-				"zone1-0000000102": {
-					Tablet: &topodatapb.Tablet{
-						Alias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  102,
+				ReadOnly: true,
+			},
+			noop: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ersCounter.ResetAll()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+				Keyspace: "testkeyspace",
+				Name:     "-",
+			})
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Type:     topodatapb.TabletType_PRIMARY,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			})
+
+			// Only the status of the primary is known, so anything past the
+			// health probe fails.
+			tmc := &testutil.TabletManagerClient{
+				FullStatusResult: tt.fullStatus,
+			}
+			erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+			ev, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{BailIfHealthy: true})
+			if !tt.noop {
+				require.Error(t, err)
+				require.EqualValues(t, map[string]int64{"testkeyspace.-.failure": 1}, ersCounter.Counts())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "shard already has a healthy primary zone1-0000000100, no action taken", ev.NoOpReason)
+			assert.Nil(t, ev.NewPrimary)
+			require.EqualValues(t, map[string]int64{"testkeyspace.-.noop": 1}, ersCounter.Counts())
+		})
+	}
+}
+
+func TestEmergencyReparenterErrantGTIDStats(t *testing.T) {
+	ersErrantGTIDCounter.ResetAll()
+	ersErrantGTIDTablets.ResetAll()
+
+	tmc := &testutil.TabletManagerClient{
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000102": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000102": {
+				Result: "ok",
+				Error:  nil,
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000100": nil,
+			"zone1-0000000101": nil,
+		},
+		StopReplicationAndGetStatusResults: map[string]struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			"zone1-0000000100": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+					},
+				},
+			},
+			"zone1-0000000101": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21,AAAAAAAA-71CA-11E1-9E33-C80AA9429562:1",
+					},
+				},
+			},
+			"zone1-0000000102": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+					},
+				},
+			},
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000100": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+			},
+			"zone1-0000000102": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+			},
+		},
+	}
+	shards := []*vtctldatapb.Shard{
+		{
+			Keyspace: "testkeyspace",
+			Name:     "-",
+		},
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "has errant GTIDs",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "most up-to-date position, wins election",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logutil.NewMemoryLogger()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, shards...)
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+		SkipShardCreation:   false,
+	}, tablets...)
+
+	erp := NewEmergencyReparenter(ts, tmc, logger)
+
+	ev, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"zone1-0000000101": "aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1"}, ev.ErrantGTIDs)
+	assert.Equal(t, map[string]string{
+		"zone1-0000000100": "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-21",
+		"zone1-0000000102": "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-26",
+	}, ev.CandidatePositions)
+	assert.Equal(t, "zone1-0000000102", topoproto.TabletAliasString(ev.NewPrimary.Alias))
+
+	require.EqualValues(t, map[string]int64{"testkeyspace.-": 1}, ersErrantGTIDCounter.Counts())
+	require.EqualValues(t, map[string]int64{"testkeyspace.-": 1}, ersErrantGTIDTablets.Counts())
+}
+
+func TestEmergencyReparenterOverallTimeout(t *testing.T) {
+	tmc := &testutil.TabletManagerClient{
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000101": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000101": {
+				Result: "ok",
+				Error:  nil,
+			},
+		},
+		// the promotion of the new primary never completes on its own
+		PromoteReplicaDelays: map[string]time.Duration{
+			"zone1-0000000101": time.Hour,
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000100": nil,
+		},
+		StopReplicationAndGetStatusResults: map[string]struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			"zone1-0000000100": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+					},
+				},
+			},
+			"zone1-0000000101": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+					},
+				},
+			},
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000100": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+			},
+			"zone1-0000000101": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+			},
+		},
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "most up-to-date position, wins election",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+	})
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	_, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{
+		WaitReplicasTimeout: time.Minute,
+		OverallTimeout:      500 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, vtrpcpb.Code_DEADLINE_EXCEEDED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "did not finish within OverallTimeout (500ms), during step PromoteNewPrimary")
+}
+
+func TestEmergencyReparenterOverallTimeoutReparentingReplicas(t *testing.T) {
+	tmc := &testutil.TabletManagerClient{
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000101": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000101": {
+				Result: "ok",
+				Error:  nil,
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000100": nil,
+		},
+		// the old primary hangs when it is pointed at the new primary, well past
+		// OverallTimeout but within WaitReplicasTimeout
+		SetReplicationSourceDelays: map[string]time.Duration{
+			"zone1-0000000100": time.Minute,
+		},
+		StopReplicationAndGetStatusResults: map[string]struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			"zone1-0000000100": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+					},
+				},
+			},
+			"zone1-0000000101": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+					},
+				},
+			},
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000100": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+			},
+			"zone1-0000000101": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+			},
+		},
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "most up-to-date position, wins election",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+	})
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	_, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{
+		WaitReplicasTimeout: 2 * time.Minute,
+		OverallTimeout:      500 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, vtrpcpb.Code_DEADLINE_EXCEEDED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "did not finish within OverallTimeout (500ms), during step PromoteNewPrimary")
+	assert.ErrorContains(t, err, "failed to wait for replicas to replicate from zone1-0000000101")
+}
+
+// setReplicationSourceCountingTMC counts the SetReplicationSource calls made
+// through it.
+type setReplicationSourceCountingTMC struct {
+	*testutil.TabletManagerClient
+	calls atomic.Int32
+}
+
+func (tmc *setReplicationSourceCountingTMC) SetReplicationSource(ctx context.Context, tablet *topodatapb.Tablet, parent *topodatapb.TabletAlias, timeCreatedNS int64, waitPosition string, forceStartReplication bool, semiSync bool, heartbeatInterval float64) error {
+	tmc.calls.Add(1)
+	return tmc.TabletManagerClient.SetReplicationSource(ctx, tablet, parent, timeCreatedNS, waitPosition, forceStartReplication, semiSync, heartbeatInterval)
+}
+
+func TestEmergencyReparenterOnPrimaryPromoted(t *testing.T) {
+	tests := []struct {
+		name        string
+		callbackErr error
+		errContains string
+	}{
+		{
+			name: "replicas are reparented after the callback",
+		},
+		{
+			name:        "callback error aborts the reparent",
+			callbackErr: errors.New("dns update failed"),
+			errContains: "OnPrimaryPromoted failed for new primary zone1-0000000101: dns update failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &setReplicationSourceCountingTMC{
+				TabletManagerClient: &testutil.TabletManagerClient{
+					PopulateReparentJournalResults: map[string]error{
+						"zone1-0000000101": nil,
+					},
+					PromoteReplicaResults: map[string]struct {
+						Result string
+						Error  error
+					}{
+						"zone1-0000000101": {
+							Result: "ok",
+							Error:  nil,
+						},
+					},
+					SetReplicationSourceResults: map[string]error{
+						"zone1-0000000100": nil,
+					},
+					StopReplicationAndGetStatusResults: map[string]struct {
+						StopStatus *replicationdatapb.StopReplicationStatus
+						Error      error
+					}{
+						"zone1-0000000100": {
+							StopStatus: &replicationdatapb.StopReplicationStatus{
+								Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+								After: &replicationdatapb.Status{
+									SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+									RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+								},
+							},
+						},
+						"zone1-0000000101": {
+							StopStatus: &replicationdatapb.StopReplicationStatus{
+								Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+								After: &replicationdatapb.Status{
+									SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+									RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+								},
+							},
+						},
+					},
+					WaitForPositionResults: map[string]map[string]error{
+						"zone1-0000000100": {
+							"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+						},
+						"zone1-0000000101": {
+							"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+						},
+					},
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+				Keyspace: "testkeyspace",
+				Name:     "-",
+			})
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Type:     topodatapb.TabletType_PRIMARY,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			}, &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+				Type:     topodatapb.TabletType_REPLICA,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				Hostname: "most up-to-date position, wins election",
+			})
+
+			var promoted *topodatapb.Tablet
+			erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+			ev, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{
+				WaitReplicasTimeout: time.Minute,
+				OnPrimaryPromoted: func(ctx context.Context, newPrimary *topodatapb.Tablet) error {
+					promoted = newPrimary
+					assert.NoError(t, topo.CheckShardLocked(ctx, "testkeyspace", "-"))
+					assert.Zero(t, tmc.calls.Load(), "replicas must not be reparented before the callback")
+					return tt.callbackErr
+				},
+			})
+			require.NotNil(t, promoted)
+			assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(promoted.Alias))
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				assert.Zero(t, tmc.calls.Load())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(ev.NewPrimary.Alias))
+			assert.EqualValues(t, 1, tmc.calls.Load())
+		})
+	}
+}
+
+func TestEmergencyReparenterDecisionSink(t *testing.T) {
+	tmc := &testutil.TabletManagerClient{
+		PopulateReparentJournalResults: map[string]error{
+			"zone1-0000000102": nil,
+		},
+		PromoteReplicaResults: map[string]struct {
+			Result string
+			Error  error
+		}{
+			"zone1-0000000102": {
+				Result: "ok",
+				Error:  nil,
+			},
+		},
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000100": nil,
+			"zone1-0000000101": nil,
+		},
+		StopReplicationAndGetStatusResults: map[string]struct {
+			StopStatus *replicationdatapb.StopReplicationStatus
+			Error      error
+		}{
+			"zone1-0000000100": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+					},
+				},
+			},
+			"zone1-0000000101": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21,AAAAAAAA-71CA-11E1-9E33-C80AA9429562:1",
+					},
+				},
+			},
+			"zone1-0000000102": {
+				StopStatus: &replicationdatapb.StopReplicationStatus{
+					Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+					After: &replicationdatapb.Status{
+						SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+						RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26",
+					},
+				},
+			},
+		},
+		WaitForPositionResults: map[string]map[string]error{
+			"zone1-0000000100": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21": nil,
+			},
+			"zone1-0000000102": {
+				"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26": nil,
+			},
+		},
+	}
+	shards := []*vtctldatapb.Shard{
+		{
+			Keyspace: "testkeyspace",
+			Name:     "-",
+		},
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type:     topodatapb.TabletType_PRIMARY,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "has errant GTIDs",
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			},
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Hostname: "most up-to-date position, wins election",
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logutil.NewMemoryLogger()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, shards...)
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+		SkipShardCreation:   false,
+	}, tablets...)
+
+	erp := NewEmergencyReparenter(ts, tmc, logger)
+
+	var sink bytes.Buffer
+	_, err := erp.ReparentShard(ctx, "testkeyspace", "-", EmergencyReparentOptions{DecisionSink: &sink})
+	require.NoError(t, err)
+
+	// the protobuf messages are written in their protojson form
+	assert.Contains(t, sink.String(), `"relayLogPosition":"MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"`)
+	var decision ElectionDecision
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &decision))
+	assert.Equal(t, "testkeyspace", decision.Keyspace)
+	assert.Equal(t, "-", decision.Shard)
+	assert.Equal(t, []string{"zone1-0000000101"}, decision.ErrantGTIDTablets)
+	assert.Equal(t, []string{"zone1-0000000102", "zone1-0000000100"}, decision.Candidates)
+	assert.Equal(t, "zone1-0000000102", decision.IntermediateSource)
+	assert.Equal(t, "zone1-0000000102", decision.NewPrimary)
+	assert.Equal(t, "neutral", decision.PromotionRules["zone1-0000000102"])
+	assert.Len(t, decision.StatusMap, 3)
+	assert.Equal(t, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26", decision.StatusMap["zone1-0000000102"].After.RelayLogPosition)
+	assert.Equal(t, "zone1-0000000100", decision.PreviousPrimary)
+	assert.Equal(t, []string{"zone1-0000000100", "zone1-0000000101", "zone1-0000000102"}, decision.ReachableTablets)
+	assert.Empty(t, decision.Laggards)
+	assert.Empty(t, decision.Error)
+
+	// the recorded input leads to the same choice when analyzed again.
+	replayed, err := ReplayElectionDecision(&decision, logger)
+	require.NoError(t, err)
+	assert.Equal(t, decision.Candidates, replayed.Candidates)
+	assert.Equal(t, decision.IntermediateSource, replayed.IntermediateSource)
+	assert.Equal(t, decision.NewPrimary, replayed.NewPrimary)
+}
+
+func TestReplayElectionDecisionLaggards(t *testing.T) {
+	stopStatus := func(relayLogPosition string) *replicationdatapb.StopReplicationStatus {
+		return &replicationdatapb.StopReplicationStatus{
+			Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+			After: &replicationdatapb.Status{
+				SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+				RelayLogPosition: relayLogPosition,
+			},
+		}
+	}
+	tablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			Type: tabletType,
+		}
+	}
+	decision := &ElectionDecision{
+		Keyspace:   "testkeyspace",
+		Shard:      "-",
+		Durability: "none",
+		Tablets: map[string]*topodatapb.Tablet{
+			"zone1-0000000100": tablet(100, topodatapb.TabletType_PRIMARY),
+			"zone1-0000000101": tablet(101, topodatapb.TabletType_REPLICA),
+			"zone1-0000000102": tablet(102, topodatapb.TabletType_REPLICA),
+		},
+		PreviousPrimary: "zone1-0000000100",
+		StatusMap: map[string]*replicationdatapb.StopReplicationStatus{
+			"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+			"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+		},
+		ReachableTablets: []string{"zone1-0000000101", "zone1-0000000102"},
+		// the least advanced tablet did not apply its relay logs in time
+		Laggards: []string{"zone1-0000000101"},
+	}
+
+	// the decision goes through JSON, as it would from a DecisionSink
+	data, err := json.Marshal(decision)
+	require.NoError(t, err)
+	var recorded ElectionDecision
+	require.NoError(t, json.Unmarshal(data, &recorded))
+	assert.Equal(t, decision.Laggards, recorded.Laggards)
+	assert.True(t, proto.Equal(decision.Tablets["zone1-0000000101"], recorded.Tablets["zone1-0000000101"]))
+
+	replayed, err := ReplayElectionDecision(&recorded, logutil.NewMemoryLogger())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone1-0000000102"}, replayed.Candidates)
+	assert.Equal(t, "zone1-0000000102", replayed.IntermediateSource)
+	assert.Equal(t, "zone1-0000000102", replayed.NewPrimary)
+
+	// the most advanced tablet cannot be left out, its transactions would be lost
+	recorded.Laggards = []string{"zone1-0000000102"}
+	_, err = ReplayElectionDecision(&recorded, logutil.NewMemoryLogger())
+	assert.ErrorContains(t, err, "candidate zone1-0000000102 could not apply its relay logs within the provided waitReplicasTimeout (0s), but it is ahead of the most advanced other candidate zone1-0000000101")
+}
+
+func TestReplayElectionDecisionOptions(t *testing.T) {
+	stopStatus := func(sourceHost string) *replicationdatapb.StopReplicationStatus {
+		return &replicationdatapb.StopReplicationStatus{
+			Before: &replicationdatapb.Status{
+				IoState:    int32(replication.ReplicationStateRunning),
+				SqlState:   int32(replication.ReplicationStateRunning),
+				SourceHost: sourceHost,
+				SourcePort: 3306,
+			},
+			After: &replicationdatapb.Status{
+				SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+				RelayLogPosition: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21",
+			},
+		}
+	}
+	tablet := func(uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			Type:          tabletType,
+			MysqlHostname: fmt.Sprintf("host%d", uid),
+			MysqlPort:     3306,
+		}
+	}
+
+	for _, direct := range []string{"zone1-0000000101", "zone1-0000000102"} {
+		t.Run(direct, func(t *testing.T) {
+			statusMap := map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("host102"),
+				"zone1-0000000102": stopStatus("host101"),
+			}
+			// the direct replica of the previous primary is tied with the other tablet
+			statusMap[direct] = stopStatus("host100")
+
+			decision := newElectionDecision("testkeyspace", "-", EmergencyReparentOptions{PreferDirectReplicas: true})
+			decision.Durability = "none"
+			decision.Tablets = map[string]*topodatapb.Tablet{
+				"zone1-0000000100": tablet(100, topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tablet(101, topodatapb.TabletType_REPLICA),
+				"zone1-0000000102": tablet(102, topodatapb.TabletType_REPLICA),
+			}
+			decision.PreviousPrimary = "zone1-0000000100"
+			decision.StatusMap = statusMap
+			decision.ReachableTablets = []string{"zone1-0000000101", "zone1-0000000102"}
+
+			data, err := json.Marshal(decision)
+			require.NoError(t, err)
+			var recorded ElectionDecision
+			require.NoError(t, json.Unmarshal(data, &recorded))
+			assert.True(t, recorded.PreferDirectReplicas)
+
+			replayed, err := ReplayElectionDecision(&recorded, logutil.NewMemoryLogger())
+			require.NoError(t, err)
+			assert.Equal(t, direct, replayed.IntermediateSource)
+			assert.Equal(t, direct, replayed.NewPrimary)
+		})
+	}
+}
+
+func TestChooseNewPrimary(t *testing.T) {
+	stopStatus := func(relayLogPosition string) *replicationdatapb.StopReplicationStatus {
+		return &replicationdatapb.StopReplicationStatus{
+			Before: &replicationdatapb.Status{IoState: int32(replication.ReplicationStateRunning), SqlState: int32(replication.ReplicationStateRunning)},
+			After: &replicationdatapb.Status{
+				SourceUuid:       "3E11FA47-71CA-11E1-9E33-C80AA9429562",
+				RelayLogPosition: relayLogPosition,
+			},
+		}
+	}
+	tabletInfo := func(uid uint32, tabletType topodatapb.TabletType) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			Type: tabletType,
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		statusMap map[string]*replicationdatapb.StopReplicationStatus
+		tabletMap map[string]*topo.TabletInfo
+		policy    string
+		opts      EmergencyReparentOptions
+		want      string
+		err       string
+	}{
+		{
+			name: "most advanced replica wins",
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+				"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tabletInfo(100, topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tabletInfo(101, topodatapb.TabletType_REPLICA),
+				"zone1-0000000102": tabletInfo(102, topodatapb.TabletType_REPLICA),
+			},
+			want: "zone1-0000000102",
+		}, {
+			name: "most advanced tablet cannot be promoted",
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+				"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tabletInfo(100, topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tabletInfo(101, topodatapb.TabletType_REPLICA),
+				"zone1-0000000102": tabletInfo(102, topodatapb.TabletType_RDONLY),
+			},
+			want: "zone1-0000000101",
+		}, {
+			name: "requested primary has errant GTIDs",
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21,AAAAAAAA-71CA-11E1-9E33-C80AA9429562:1"),
+				"zone1-0000000102": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000101": tabletInfo(101, topodatapb.TabletType_REPLICA),
+				"zone1-0000000102": tabletInfo(102, topodatapb.TabletType_REPLICA),
+			},
+			opts: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+			},
+			err: "requested primary elect zone1-0000000101 has errant GTIDs: aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1",
+		}, {
+			name: "durability policy given by the caller",
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-26"),
+				"zone2-0000000200": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000101": tabletInfo(101, topodatapb.TabletType_REPLICA),
+				"zone2-0000000200": {Tablet: &topodatapb.Tablet{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone2",
+						Uid:  200,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				}},
+			},
+			policy: "test",
+			// zone2-0000000200 is the preferred tablet of the "test" policy
+			want: "zone2-0000000200",
+		}, {
+			name: "unknown durability policy",
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": stopStatus("MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"),
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000101": tabletInfo(101, topodatapb.TabletType_REPLICA),
+			},
+			policy: "unknown",
+			err:    "durability policy unknown not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newPrimary, err := ChooseNewPrimary(tt.statusMap, tt.tabletMap, tt.policy, tt.opts)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, topoproto.TabletAliasString(newPrimary.Alias))
+		})
+	}
+}
+
+func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
+	sid1 := replication.SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	mysqlGTID1 := replication.Mysql56GTID{
+		Server:   sid1,
+		Sequence: 9,
+	}
+	mysqlGTID2 := replication.Mysql56GTID{
+		Server:   sid1,
+		Sequence: 10,
+	}
+	mysqlGTID3 := replication.Mysql56GTID{
+		Server:   sid1,
+		Sequence: 11,
+	}
+
+	positionMostAdvanced := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID1)
+	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID2)
+	positionMostAdvanced.GTIDSet = positionMostAdvanced.GTIDSet.AddGTID(mysqlGTID3)
+
+	positionIntermediate1 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+	positionIntermediate1.GTIDSet = positionIntermediate1.GTIDSet.AddGTID(mysqlGTID1)
+
+	positionIntermediate2 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+	positionIntermediate2.GTIDSet = positionIntermediate2.GTIDSet.AddGTID(mysqlGTID1)
+	positionIntermediate2.GTIDSet = positionIntermediate2.GTIDSet.AddGTID(mysqlGTID2)
+
+	positionOnly2 := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+	positionOnly2.GTIDSet = positionOnly2.GTIDSet.AddGTID(mysqlGTID2)
+
+	positionEmpty := replication.Position{GTIDSet: replication.Mysql56GTIDSet{}}
+
+	tests := []struct {
+		name                 string
+		validCandidates      map[string]replication.Position
+		tabletMap            map[string]*topo.TabletInfo
+		emergencyReparentOps EmergencyReparentOptions
+		result               *topodatapb.Tablet
+		err                  string
+	}{
+		{
+			name: "choose most advanced",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": positionMostAdvanced,
+				"zone1-0000000101": positionIntermediate1,
+				"zone1-0000000102": positionIntermediate2,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
+						},
+					},
+				},
+				"zone1-0000000404": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  404,
+						},
+						Hostname: "ignored tablet",
+					},
+				},
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+		}, {
+			name: "choose most advanced with the best promotion rule",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": positionMostAdvanced,
+				"zone1-0000000101": positionIntermediate1,
+				"zone1-0000000102": positionMostAdvanced,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+						Type: topodatapb.TabletType_PRIMARY,
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
+						},
+						Type: topodatapb.TabletType_RDONLY,
+					},
+				},
+				"zone1-0000000404": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  404,
+						},
+						Hostname: "ignored tablet",
+					},
+				},
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+		}, {
+			name: "choose most advanced with explicit request",
+			emergencyReparentOps: EmergencyReparentOptions{NewPrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			}},
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": positionMostAdvanced,
+				"zone1-0000000101": positionIntermediate1,
+				"zone1-0000000102": positionMostAdvanced,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+						Type: topodatapb.TabletType_PRIMARY,
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
+						},
+						Type: topodatapb.TabletType_RDONLY,
+					},
+				},
+				"zone1-0000000404": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  404,
+						},
+						Hostname: "ignored tablet",
+					},
+				},
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+		}, {
+			name: "split brain detection",
+			emergencyReparentOps: EmergencyReparentOptions{NewPrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			}},
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": positionOnly2,
+				"zone1-0000000101": positionIntermediate1,
+				"zone1-0000000102": positionEmpty,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+						Type: topodatapb.TabletType_PRIMARY,
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
 						},
+						Type: topodatapb.TabletType_RDONLY,
 					},
 				},
 				"zone1-0000000404": {
@@ -2807,19 +4844,417 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 					},
 				},
 			},
+			err: "split brain detected between servers",
+		}, {
+			name: "prefer direct replica at equal positions",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000101": positionMostAdvanced,
+				"zone1-0000000102": positionMostAdvanced,
+				"zone1-0000000103": positionIntermediate1,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+						Hostname: "chained replica",
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
+						},
+						Hostname: "direct replica",
+					},
+				},
+				"zone1-0000000103": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  103,
+						},
+					},
+				},
+			},
+			emergencyReparentOps: EmergencyReparentOptions{
+				PreferDirectReplicas: true,
+				directReplicas:       sets.New[string]("zone1-0000000102", "zone1-0000000103"),
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+		},
+		{
+			name: "avoid IO errored replica at equal positions",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000101": positionMostAdvanced,
+				"zone1-0000000102": positionMostAdvanced,
+				"zone1-0000000103": positionIntermediate1,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+						Hostname: "IO errored replica",
+					},
+				},
+				"zone1-0000000102": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  102,
+						},
+						Hostname: "healthy replica",
+					},
+				},
+				"zone1-0000000103": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  103,
+						},
+					},
+				},
+			},
+			emergencyReparentOps: EmergencyReparentOptions{
+				ExcludeIOErrored:  true,
+				ioErroredReplicas: sets.New[string]("zone1-0000000101"),
+			},
 			result: &topodatapb.Tablet{
 				Alias: &topodatapb.TabletAlias{
 					Cell: "zone1",
-					Uid:  100,
+					Uid:  102,
+				},
+			},
+		}, {
+			name: "requested primary with errant GTIDs",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": positionMostAdvanced,
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+			},
+			emergencyReparentOps: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+				errantGTIDs: map[string]string{
+					"zone1-0000000101": "aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1",
+				},
+			},
+			err: "requested primary elect zone1-0000000101 has errant GTIDs: aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1",
+		},
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			erp := NewEmergencyReparenter(nil, nil, logutil.NewMemoryLogger())
+
+			test.emergencyReparentOps.durability = durability
+			winningTablet, _, err := erp.findMostAdvanced(test.validCandidates, test.tabletMap, test.emergencyReparentOps)
+			if test.err != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, topoproto.TabletAliasEqual(test.result.Alias, winningTablet.Alias))
+			}
+		})
+	}
+}
+
+func TestEmergencyReparenter_preferRelayLogReceived(t *testing.T) {
+	durability, _ := GetDurabilityPolicy("none")
+	// zone1-0000000100 has executed more, but zone1-0000000101 has received more in its relay log.
+	statusMap := map[string]*replicationdatapb.StopReplicationStatus{
+		"zone1-0000000100": {
+			After: &replicationdatapb.Status{
+				Position:         "FilePos/mysql-bin.0001:100",
+				RelayLogPosition: "FilePos/mysql-bin.0001:100",
+			},
+		},
+		"zone1-0000000101": {
+			After: &replicationdatapb.Status{
+				Position:         "FilePos/mysql-bin.0001:50",
+				RelayLogPosition: "FilePos/mysql-bin.0001:200",
+			},
+		},
+	}
+	tabletMap := map[string]*topo.TabletInfo{
+		"zone1-0000000100": {
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Type: topodatapb.TabletType_REPLICA,
+			},
+		},
+		"zone1-0000000101": {
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
 				},
+				Type: topodatapb.TabletType_REPLICA,
 			},
-		}, {
-			name: "choose most advanced with the best promotion rule",
-			validCandidates: map[string]replication.Position{
-				"zone1-0000000100": positionMostAdvanced,
-				"zone1-0000000101": positionIntermediate1,
-				"zone1-0000000102": positionMostAdvanced,
+		},
+	}
+
+	tests := []struct {
+		name                   string
+		preferRelayLogReceived bool
+		winner                 string
+	}{
+		{
+			name:                   "executed position",
+			preferRelayLogReceived: false,
+			winner:                 "zone1-0000000100",
+		},
+		{
+			name:                   "relay log received",
+			preferRelayLogReceived: true,
+			winner:                 "zone1-0000000101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validCandidates, err := FindValidEmergencyReparentCandidates(statusMap, nil)
+			require.NoError(t, err)
+			if tt.preferRelayLogReceived {
+				useRelayLogReceivedPositions(validCandidates, statusMap)
+			}
+
+			erp := NewEmergencyReparenter(nil, nil, logutil.NewMemoryLogger())
+			winningTablet, _, err := erp.findMostAdvanced(validCandidates, tabletMap, EmergencyReparentOptions{durability: durability})
+			require.NoError(t, err)
+			assert.Equal(t, tt.winner, topoproto.TabletAliasString(winningTablet.Alias))
+		})
+	}
+}
+
+func TestEmergencyReparenter_candidateScorer(t *testing.T) {
+	durability, _ := GetDurabilityPolicy("none")
+	validCandidates := map[string]replication.Position{}
+	tabletMap := map[string]*topo.TabletInfo{}
+	for _, tablet := range []struct {
+		alias    *topodatapb.TabletAlias
+		position string
+	}{
+		{&topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"},
+		{&topodatapb.TabletAlias{Cell: "zone2", Uid: 101}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"},
+		{&topodatapb.TabletAlias{Cell: "zone2", Uid: 102}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20"},
+	} {
+		alias := topoproto.TabletAliasString(tablet.alias)
+		pos, err := replication.DecodePosition(tablet.position)
+		require.NoError(t, err)
+		validCandidates[alias] = pos
+		tabletMap[alias] = &topo.TabletInfo{
+			Tablet: &topodatapb.Tablet{
+				Alias: tablet.alias,
+				Type:  topodatapb.TabletType_REPLICA,
+			},
+		}
+	}
+
+	// zone2 has the better network, but zone2-0000000102 is behind.
+	preferZone2 := func(tablet *topodatapb.Tablet, _ replication.Position) int {
+		if tablet.Alias.Cell == "zone2" {
+			return 10
+		}
+		return 0
+	}
+
+	erp := NewEmergencyReparenter(nil, nil, logutil.NewMemoryLogger())
+	winningTablet, sorted, err := erp.findMostAdvanced(validCandidates, tabletMap, EmergencyReparentOptions{durability: durability, CandidateScorer: preferZone2})
+	require.NoError(t, err)
+	assert.Equal(t, "zone2-0000000101", topoproto.TabletAliasString(winningTablet.Alias))
+	var sortedAliases []string
+	for _, tablet := range sorted {
+		sortedAliases = append(sortedAliases, topoproto.TabletAliasString(tablet.Alias))
+	}
+	assert.Equal(t, []string{"zone2-0000000101", "zone1-0000000100", "zone2-0000000102"}, sortedAliases)
+
+	// without a scorer, the tablet behind still cannot win.
+	winningTablet, _, err = erp.findMostAdvanced(validCandidates, tabletMap, EmergencyReparentOptions{durability: durability})
+	require.NoError(t, err)
+	assert.Contains(t, []string{"zone1-0000000100", "zone2-0000000101"}, topoproto.TabletAliasString(winningTablet.Alias))
+}
+
+func TestEmergencyReparenter_findMostAdvancedPreferTabletTag(t *testing.T) {
+	durability, _ := GetDurabilityPolicy("none")
+	validCandidates := map[string]replication.Position{}
+	tabletMap := map[string]*topo.TabletInfo{}
+	for _, tablet := range []struct {
+		alias    *topodatapb.TabletAlias
+		position string
+		tier     string
+	}{
+		{&topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21", "low"},
+		{&topodatapb.TabletAlias{Cell: "zone1", Uid: 101}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21", "high"},
+		{&topodatapb.TabletAlias{Cell: "zone1", Uid: 102}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20", "high"},
+		{&topodatapb.TabletAlias{Cell: "zone1", Uid: 103}, "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-20", "low"},
+	} {
+		alias := topoproto.TabletAliasString(tablet.alias)
+		pos, err := replication.DecodePosition(tablet.position)
+		require.NoError(t, err)
+		validCandidates[alias] = pos
+		tabletMap[alias] = &topo.TabletInfo{
+			Tablet: &topodatapb.Tablet{
+				Alias: tablet.alias,
+				Type:  topodatapb.TabletType_REPLICA,
+				Tags:  map[string]string{"tier": tablet.tier},
+			},
+		}
+	}
+
+	erp := NewEmergencyReparenter(nil, nil, logutil.NewMemoryLogger())
+	opts := EmergencyReparentOptions{durability: durability, PreferTabletTag: "tier", PreferTabletTagValue: "high"}
+	winningTablet, sorted, err := erp.findMostAdvanced(validCandidates, tabletMap, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(winningTablet.Alias))
+	var sortedAliases []string
+	for _, tablet := range sorted {
+		sortedAliases = append(sortedAliases, topoproto.TabletAliasString(tablet.Alias))
+	}
+	// the tag only breaks ties, zone1-0000000102 stays behind the untagged zone1-0000000100.
+	assert.Equal(t, []string{"zone1-0000000101", "zone1-0000000100", "zone1-0000000102", "zone1-0000000103"}, sortedAliases)
+
+	// the tagged tablet that is behind is not preferred over the intermediate source
+	// when looking for a better candidate either.
+	candidate, err := erp.identifyPrimaryCandidate(tabletMap["zone1-0000000100"].Tablet, []*topodatapb.Tablet{
+		tabletMap["zone1-0000000100"].Tablet,
+		tabletMap["zone1-0000000102"].Tablet,
+	}, tabletMap, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "zone1-0000000100", topoproto.TabletAliasString(candidate.Alias))
+}
+
+func TestDropUnreadablePrimaryStatuses(t *testing.T) {
+	primaryStatusMap := map[string]*replicationdatapb.PrimaryStatus{
+		"zone1-0000000100": {Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-21"},
+		"zone1-0000000101": {},
+		"zone1-0000000102": {Position: "InvalidFlavor/1234"},
+	}
+	assert.Equal(t, []string{"zone1-0000000101", "zone1-0000000102"}, dropUnreadablePrimaryStatuses(primaryStatusMap))
+	assert.Len(t, primaryStatusMap, 1)
+	assert.Contains(t, primaryStatusMap, "zone1-0000000100")
+}
+
+func TestEmergencyReparenter_findIgnoredReplicas(t *testing.T) {
+	tabletMap := map[string]*topo.TabletInfo{}
+	for uid, tabletType := range map[uint32]topodatapb.TabletType{
+		100: topodatapb.TabletType_REPLICA,
+		101: topodatapb.TabletType_DRAINED,
+		102: topodatapb.TabletType_BACKUP,
+		103: topodatapb.TabletType_RESTORE,
+		104: topodatapb.TabletType_RDONLY,
+		105: topodatapb.TabletType_DRAINED,
+		404: topodatapb.TabletType_REPLICA,
+	} {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{
+			Tablet: &topodatapb.Tablet{
+				Alias: alias,
+				Type:  tabletType,
+			},
+		}
+	}
+	// zone1-0000000105 is not a candidate, e.g. because it has errant GTIDs, so
+	// it is not skipped because of its type.
+	validCandidates := map[string]replication.Position{
+		"zone1-0000000100": {},
+		"zone1-0000000101": {},
+		"zone1-0000000102": {},
+		"zone1-0000000103": {},
+		"zone1-0000000104": {},
+	}
+
+	ignored := findIgnoredReplicas(tabletMap, sets.New[string]("zone1-0000000404"), validCandidates)
+	assert.Equal(t, map[string]string{
+		"zone1-0000000101": IgnoredDrained,
+		"zone1-0000000102": IgnoredTakingBackup,
+		"zone1-0000000103": IgnoredByType,
+		"zone1-0000000404": IgnoredExplicitly,
+	}, ignored)
+}
+
+func TestEmergencyReparenter_errantGTIDSummary(t *testing.T) {
+	assert.Equal(t, "", errantGTIDSummary(nil))
+	assert.Equal(t, "zone1-0000000101 (aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1), zone1-0000000102 (bbbbbbbb-71ca-11e1-9e33-c80aa9429562:1-3)", errantGTIDSummary(map[string]string{
+		"zone1-0000000102": "bbbbbbbb-71ca-11e1-9e33-c80aa9429562:1-3",
+		"zone1-0000000101": "aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1",
+	}))
+}
+
+func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
+	tests := []struct {
+		name                  string
+		emergencyReparentOps  EmergencyReparentOptions
+		durabilityPolicy      string
+		tmc                   *testutil.TabletManagerClient
+		unlockTopo            bool
+		newPrimaryTabletAlias string
+		keyspace              string
+		shard                 string
+		tablets               []*topodatapb.Tablet
+		tabletMap             map[string]*topo.TabletInfo
+		statusMap             map[string]*replicationdatapb.StopReplicationStatus
+		shouldErr             bool
+		errShouldContain      string
+		remoteOpTimeout       time.Duration
+	}{
+		{
+			name:                 "success",
+			emergencyReparentOps: EmergencyReparentOptions{IgnoreReplicas: sets.New[string]("zone1-0000000404")},
+			tmc: &testutil.TabletManagerClient{
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Error: nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000101": nil,
+					"zone1-0000000102": nil,
+					"zone1-0000000404": assert.AnError, // okay, because we're ignoring it.
+				},
 			},
+			newPrimaryTabletAlias: "zone1-0000000100",
 			tabletMap: map[string]*topo.TabletInfo{
 				"zone1-0000000100": {
 					Tablet: &topodatapb.Tablet{
@@ -2827,7 +5262,7 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 							Cell: "zone1",
 							Uid:  100,
 						},
-						Type: topodatapb.TabletType_PRIMARY,
+						Hostname: "primary-elect",
 					},
 				},
 				"zone1-0000000101": {
@@ -2844,7 +5279,7 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 							Cell: "zone1",
 							Uid:  102,
 						},
-						Type: topodatapb.TabletType_RDONLY,
+						Hostname: "requires force start",
 					},
 				},
 				"zone1-0000000404": {
@@ -2857,23 +5292,48 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 					},
 				},
 			},
-			result: &topodatapb.Tablet{
-				Alias: &topodatapb.TabletAlias{
-					Cell: "zone1",
-					Uid:  100,
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
+				"zone1-0000000101": { // forceStart = false
+					Before: &replicationdatapb.Status{
+						IoState:  int32(replication.ReplicationStateStopped),
+						SqlState: int32(replication.ReplicationStateStopped),
+					},
+				},
+				"zone1-0000000102": { // forceStart = true
+					Before: &replicationdatapb.Status{
+						IoState:  int32(replication.ReplicationStateRunning),
+						SqlState: int32(replication.ReplicationStateRunning),
+					},
 				},
 			},
-		}, {
-			name: "choose most advanced with explicit request",
-			emergencyReparentOps: EmergencyReparentOptions{NewPrimaryAlias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  102,
-			}},
-			validCandidates: map[string]replication.Position{
-				"zone1-0000000100": positionMostAdvanced,
-				"zone1-0000000101": positionIntermediate1,
-				"zone1-0000000102": positionMostAdvanced,
+			keyspace:  "testkeyspace",
+			shard:     "-",
+			shouldErr: false,
+		},
+		{
+			name:                 "success - reparent replicas in batches",
+			emergencyReparentOps: EmergencyReparentOptions{ReparentBatchSize: 2},
+			tmc: &testutil.TabletManagerClient{
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Error: nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000101": nil,
+					"zone1-0000000102": nil,
+					"zone1-0000000103": assert.AnError,
+					"zone1-0000000104": nil,
+					"zone1-0000000105": nil,
+				},
 			},
+			newPrimaryTabletAlias: "zone1-0000000100",
 			tabletMap: map[string]*topo.TabletInfo{
 				"zone1-0000000100": {
 					Tablet: &topodatapb.Tablet{
@@ -2881,7 +5341,6 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 							Cell: "zone1",
 							Uid:  100,
 						},
-						Type: topodatapb.TabletType_PRIMARY,
 					},
 				},
 				"zone1-0000000101": {
@@ -2898,115 +5357,41 @@ func TestEmergencyReparenter_findMostAdvanced(t *testing.T) {
 							Cell: "zone1",
 							Uid:  102,
 						},
-						Type: topodatapb.TabletType_RDONLY,
-					},
-				},
-				"zone1-0000000404": {
-					Tablet: &topodatapb.Tablet{
-						Alias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  404,
-						},
-						Hostname: "ignored tablet",
-					},
-				},
-			},
-			result: &topodatapb.Tablet{
-				Alias: &topodatapb.TabletAlias{
-					Cell: "zone1",
-					Uid:  102,
-				},
-			},
-		}, {
-			name: "split brain detection",
-			emergencyReparentOps: EmergencyReparentOptions{NewPrimaryAlias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  102,
-			}},
-			validCandidates: map[string]replication.Position{
-				"zone1-0000000100": positionOnly2,
-				"zone1-0000000101": positionIntermediate1,
-				"zone1-0000000102": positionEmpty,
-			},
-			tabletMap: map[string]*topo.TabletInfo{
-				"zone1-0000000100": {
-					Tablet: &topodatapb.Tablet{
-						Alias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  100,
-						},
-						Type: topodatapb.TabletType_PRIMARY,
 					},
 				},
-				"zone1-0000000101": {
+				"zone1-0000000103": {
 					Tablet: &topodatapb.Tablet{
 						Alias: &topodatapb.TabletAlias{
 							Cell: "zone1",
-							Uid:  101,
+							Uid:  103,
 						},
 					},
 				},
-				"zone1-0000000102": {
+				"zone1-0000000104": {
 					Tablet: &topodatapb.Tablet{
 						Alias: &topodatapb.TabletAlias{
 							Cell: "zone1",
-							Uid:  102,
+							Uid:  104,
 						},
-						Type: topodatapb.TabletType_RDONLY,
 					},
 				},
-				"zone1-0000000404": {
+				"zone1-0000000105": {
 					Tablet: &topodatapb.Tablet{
 						Alias: &topodatapb.TabletAlias{
 							Cell: "zone1",
-							Uid:  404,
+							Uid:  105,
 						},
-						Hostname: "ignored tablet",
 					},
 				},
 			},
-			err: "split brain detected between servers",
-		},
-	}
-
-	durability, _ := GetDurabilityPolicy("none")
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			erp := NewEmergencyReparenter(nil, nil, logutil.NewMemoryLogger())
-
-			test.emergencyReparentOps.durability = durability
-			winningTablet, _, err := erp.findMostAdvanced(test.validCandidates, test.tabletMap, test.emergencyReparentOps)
-			if test.err != "" {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), test.err)
-			} else {
-				assert.NoError(t, err)
-				assert.True(t, topoproto.TabletAliasEqual(test.result.Alias, winningTablet.Alias))
-			}
-		})
-	}
-}
-
-func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
-	tests := []struct {
-		name                  string
-		emergencyReparentOps  EmergencyReparentOptions
-		tmc                   *testutil.TabletManagerClient
-		unlockTopo            bool
-		newPrimaryTabletAlias string
-		keyspace              string
-		shard                 string
-		tablets               []*topodatapb.Tablet
-		tabletMap             map[string]*topo.TabletInfo
-		statusMap             map[string]*replicationdatapb.StopReplicationStatus
-		shouldErr             bool
-		errShouldContain      string
-		remoteOpTimeout       time.Duration
-	}{
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{},
+			keyspace:  "testkeyspace",
+			shard:     "-",
+			shouldErr: false,
+		},
 		{
-			name:                 "success",
-			emergencyReparentOps: EmergencyReparentOptions{IgnoreReplicas: sets.New[string]("zone1-0000000404")},
+			name:                 "all replicas failed in batches",
+			emergencyReparentOps: EmergencyReparentOptions{ReparentBatchSize: 2},
 			tmc: &testutil.TabletManagerClient{
 				PopulateReparentJournalResults: map[string]error{
 					"zone1-0000000100": nil,
@@ -3020,9 +5405,9 @@ func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
 					},
 				},
 				SetReplicationSourceResults: map[string]error{
-					"zone1-0000000101": nil,
-					"zone1-0000000102": nil,
-					"zone1-0000000404": assert.AnError, // okay, because we're ignoring it.
+					"zone1-0000000101": assert.AnError,
+					"zone1-0000000102": assert.AnError,
+					"zone1-0000000103": assert.AnError,
 				},
 			},
 			newPrimaryTabletAlias: "zone1-0000000100",
@@ -3033,7 +5418,6 @@ func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
 							Cell: "zone1",
 							Uid:  100,
 						},
-						Hostname: "primary-elect",
 					},
 				},
 				"zone1-0000000101": {
@@ -3050,37 +5434,118 @@ func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
 							Cell: "zone1",
 							Uid:  102,
 						},
-						Hostname: "requires force start",
 					},
 				},
-				"zone1-0000000404": {
+				"zone1-0000000103": {
 					Tablet: &topodatapb.Tablet{
 						Alias: &topodatapb.TabletAlias{
 							Cell: "zone1",
-							Uid:  404,
+							Uid:  103,
 						},
-						Hostname: "ignored tablet",
 					},
 				},
 			},
-			statusMap: map[string]*replicationdatapb.StopReplicationStatus{
-				"zone1-0000000101": { // forceStart = false
-					Before: &replicationdatapb.Status{
-						IoState:  int32(replication.ReplicationStateStopped),
-						SqlState: int32(replication.ReplicationStateStopped),
+			statusMap:        map[string]*replicationdatapb.StopReplicationStatus{},
+			keyspace:         "testkeyspace",
+			shard:            "-",
+			shouldErr:        true,
+			errShouldContain: "3 replica(s) failed",
+		},
+		{
+			name:                 "success - semi-sync verified on new primary",
+			emergencyReparentOps: EmergencyReparentOptions{VerifySemiSyncEnabled: true},
+			durabilityPolicy:     "semi_sync",
+			tmc: &testutil.TabletManagerClient{
+				FullStatusResult: &replicationdatapb.FullStatus{
+					SemiSyncPrimaryEnabled: true,
+				},
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Error: nil,
 					},
 				},
-				"zone1-0000000102": { // forceStart = true
-					Before: &replicationdatapb.Status{
-						IoState:  int32(replication.ReplicationStateRunning),
-						SqlState: int32(replication.ReplicationStateRunning),
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000101": nil,
+				},
+			},
+			newPrimaryTabletAlias: "zone1-0000000100",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
 					},
 				},
 			},
+			statusMap: map[string]*replicationdatapb.StopReplicationStatus{},
 			keyspace:  "testkeyspace",
 			shard:     "-",
 			shouldErr: false,
 		},
+		{
+			name:                 "semi-sync not enabled on new primary",
+			emergencyReparentOps: EmergencyReparentOptions{VerifySemiSyncEnabled: true},
+			durabilityPolicy:     "semi_sync",
+			tmc: &testutil.TabletManagerClient{
+				FullStatusResult: &replicationdatapb.FullStatus{
+					SemiSyncPrimaryEnabled: false,
+				},
+				PopulateReparentJournalResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				PromoteReplicaResults: map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Error: nil,
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000101": nil,
+				},
+			},
+			newPrimaryTabletAlias: "zone1-0000000100",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+				"zone1-0000000101": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  101,
+						},
+					},
+				},
+			},
+			statusMap:        map[string]*replicationdatapb.StopReplicationStatus{},
+			keyspace:         "testkeyspace",
+			shard:            "-",
+			shouldErr:        true,
+			errShouldContain: "new primary zone1-0000000100 does not have semi-sync enabled after promotion",
+		},
 		{
 			name:                 "PromoteReplica error",
 			emergencyReparentOps: EmergencyReparentOptions{},
@@ -3443,75 +5908,425 @@ func TestEmergencyReparenter_reparentReplicas(t *testing.T) {
 					},
 				},
 			},
-			keyspace:         "testkeyspace",
-			shard:            "-",
-			shouldErr:        true,
-			errShouldContain: "failed to promote zone1-0000000100 to primary: primary-elect tablet zone1-0000000100 failed to be upgraded to primary: context deadline exceeded",
+			keyspace:         "testkeyspace",
+			shard:            "-",
+			shouldErr:        true,
+			errShouldContain: "failed to promote zone1-0000000100 to primary: primary-elect tablet zone1-0000000100 failed to be upgraded to primary: context deadline exceeded",
+		},
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.remoteOpTimeout != 0 {
+				oldTimeout := topo.RemoteOperationTimeout
+				topo.RemoteOperationTimeout = tt.remoteOpTimeout
+				defer func() {
+					topo.RemoteOperationTimeout = oldTimeout
+				}()
+			}
+
+			logger := logutil.NewMemoryLogger()
+			ev := &events.Reparent{
+				ShardInfo: topo.ShardInfo{
+					Shard: &topodatapb.Shard{
+						IsPrimaryServing: true,
+						PrimaryAlias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  000,
+						},
+					},
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+
+			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+				Keyspace: tt.keyspace,
+				Name:     tt.shard,
+			})
+
+			if !tt.unlockTopo {
+				var (
+					unlock func(*error)
+					lerr   error
+				)
+
+				ctx, unlock, lerr = ts.LockShard(ctx, tt.keyspace, tt.shard, "test lock")
+				require.NoError(t, lerr, "could not lock %s/%s for test", tt.keyspace, tt.shard)
+
+				defer func() {
+					unlock(&lerr)
+					require.NoError(t, lerr, "could not unlock %s/%s after test", tt.keyspace, tt.shard)
+				}()
+			}
+			tabletInfo := tt.tabletMap[tt.newPrimaryTabletAlias]
+
+			tt.emergencyReparentOps.durability = durability
+			if tt.durabilityPolicy != "" {
+				tt.emergencyReparentOps.durability, _ = GetDurabilityPolicy(tt.durabilityPolicy)
+			}
+
+			erp := NewEmergencyReparenter(ts, tt.tmc, logger)
+			_, err := erp.reparentReplicas(ctx, ev, tabletInfo.Tablet, tt.tabletMap, tt.statusMap, tt.emergencyReparentOps, false /* intermediateReparent */)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errShouldContain)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEmergencyReparenter_reparentReplicasInBatches(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	tabletMap := map[string]*topo.TabletInfo{}
+	tmc := &testutil.TabletManagerClient{
+		SetReplicationSourceResults: map[string]error{},
+		SetReplicationSourceDelays:  map[string]time.Duration{},
+	}
+	for uid := uint32(100); uid <= 104; uid++ {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		aliasStr := topoproto.TabletAliasString(alias)
+		tabletMap[aliasStr] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias}}
+		if uid != 100 {
+			tmc.SetReplicationSourceResults[aliasStr] = nil
+			tmc.SetReplicationSourceDelays[aliasStr] = delay
+		}
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+	opts := EmergencyReparentOptions{
+		WaitReplicasTimeout: time.Minute,
+		ReparentBatchSize:   2,
+		durability:          durability,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	replicas, err := erp.reparentReplicas(ctx, &events.Reparent{}, tabletMap["zone1-0000000100"].Tablet, tabletMap, nil, opts, true /* intermediateReparent */)
+	require.NoError(t, err)
+	assert.Len(t, replicas, 4)
+	// the 4 replicas are reparented in 2 waves of 2
+	assert.GreaterOrEqual(t, time.Since(start), 2*delay)
+}
+
+func TestEmergencyReparenter_reparentReplicasBatchTimeout(t *testing.T) {
+	const waitReplicasTimeout = 200 * time.Millisecond
+
+	tabletMap := map[string]*topo.TabletInfo{}
+	for uid := uint32(100); uid <= 102; uid++ {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias}}
+	}
+	tmc := &testutil.TabletManagerClient{
+		SetReplicationSourceResults: map[string]error{
+			"zone1-0000000101": nil,
+			"zone1-0000000102": nil,
+		},
+		SetReplicationSourceDelays: map[string]time.Duration{
+			// the first wave succeeds
+			"zone1-0000000101": waitReplicasTimeout * 3 / 5,
+			// the second wave shares WaitReplicasTimeout with the first one, and
+			// has too little of it left to succeed
+			"zone1-0000000102": waitReplicasTimeout * 3 / 5,
+		},
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+	opts := EmergencyReparentOptions{
+		WaitReplicasTimeout: waitReplicasTimeout,
+		ReparentBatchSize:   1,
+		durability:          durability,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	replicas, err := erp.reparentReplicas(ctx, &events.Reparent{}, tabletMap["zone1-0000000100"].Tablet, tabletMap, nil, opts, true /* intermediateReparent */)
+	require.NoError(t, err)
+	require.Len(t, replicas, 1)
+	assert.Equal(t, "zone1-0000000101", topoproto.TabletAliasString(replicas[0].Alias))
+}
+
+// setReplicationSourceDoneTMC records the tablets that SetReplicationSource
+// succeeded on.
+type setReplicationSourceDoneTMC struct {
+	*testutil.TabletManagerClient
+	mu   sync.Mutex
+	done []string
+}
+
+func (tmc *setReplicationSourceDoneTMC) SetReplicationSource(ctx context.Context, tablet *topodatapb.Tablet, parent *topodatapb.TabletAlias, timeCreatedNS int64, waitPosition string, forceStartReplication bool, semiSync bool, heartbeatInterval float64) error {
+	err := tmc.TabletManagerClient.SetReplicationSource(ctx, tablet, parent, timeCreatedNS, waitPosition, forceStartReplication, semiSync, heartbeatInterval)
+	if err == nil {
+		tmc.mu.Lock()
+		defer tmc.mu.Unlock()
+		tmc.done = append(tmc.done, topoproto.TabletAliasString(tablet.Alias))
+	}
+	return err
+}
+
+func (tmc *setReplicationSourceDoneTMC) reparented() []string {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return slices.Clone(tmc.done)
+}
+
+func TestEmergencyReparenter_reparentReplicasWaitsForAllBatches(t *testing.T) {
+	const delay = 500 * time.Millisecond
+
+	tabletMap := map[string]*topo.TabletInfo{}
+	for uid := uint32(100); uid <= 102; uid++ {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias}}
+	}
+	tmc := &setReplicationSourceDoneTMC{
+		TabletManagerClient: &testutil.TabletManagerClient{
+			PopulateReparentJournalResults: map[string]error{
+				"zone1-0000000100": nil,
+			},
+			PromoteReplicaResults: map[string]struct {
+				Result string
+				Error  error
+			}{
+				"zone1-0000000100": {
+					Result: "ok",
+				},
+			},
+			SetReplicationSourceResults: map[string]error{
+				"zone1-0000000101": nil,
+				"zone1-0000000102": nil,
+			},
+			SetReplicationSourceDelays: map[string]time.Duration{
+				"zone1-0000000102": delay,
+			},
+		},
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+	opts := EmergencyReparentOptions{
+		WaitReplicasTimeout: time.Minute,
+		ReparentBatchSize:   1,
+		durability:          durability,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	ev := &events.Reparent{
+		ShardInfo: topo.ShardInfo{
+			Shard: &topodatapb.Shard{
+				PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			},
+		},
+	}
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	start := time.Now()
+	_, err := erp.reparentReplicas(ctx, ev, tabletMap["zone1-0000000100"].Tablet, tabletMap, nil, opts, false /* intermediateReparent */)
+	require.NoError(t, err)
+	// we returned once all the waves were done, while the shard would still be locked
+	assert.GreaterOrEqual(t, time.Since(start), delay)
+	assert.Equal(t, []string{"zone1-0000000101", "zone1-0000000102"}, tmc.reparented())
+}
+
+func TestEmergencyReparenter_reparentReplicasBatchesAborted(t *testing.T) {
+	const delay = 500 * time.Millisecond
+
+	tabletMap := map[string]*topo.TabletInfo{}
+	for uid := uint32(100); uid <= 102; uid++ {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias}}
+	}
+	tmc := &setReplicationSourceDoneTMC{
+		TabletManagerClient: &testutil.TabletManagerClient{
+			SetReplicationSourceResults: map[string]error{
+				"zone1-0000000101": nil,
+				"zone1-0000000102": nil,
+			},
+			SetReplicationSourceDelays: map[string]time.Duration{
+				"zone1-0000000101": delay,
+			},
+		},
+	}
+
+	durability, _ := GetDurabilityPolicy("none")
+	opts := EmergencyReparentOptions{
+		WaitReplicasTimeout: time.Minute,
+		ReparentBatchSize:   1,
+		OverallTimeout:      delay / 2,
+		durability:          durability,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.OverallTimeout)
+	defer cancel()
+	ts := memorytopo.NewServer(context.Background(), "zone1")
+	defer ts.Close()
+
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	_, err := erp.reparentReplicas(ctx, &events.Reparent{}, tabletMap["zone1-0000000100"].Tablet, tabletMap, nil, opts, true /* intermediateReparent */)
+	require.ErrorContains(t, err, "context deadline exceeded")
+
+	// the first wave carries on, but the second one is never started once the reparent is aborted
+	assert.Eventually(t, func() bool {
+		return len(tmc.reparented()) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	time.Sleep(delay)
+	assert.Equal(t, []string{"zone1-0000000101"}, tmc.reparented())
+}
+
+func TestEmergencyReparenter_skipReplicaTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		tabletTypes map[uint32]topodatapb.TabletType
+		reattached  int32
+		skipped     []string
+	}{
+		{
+			name: "rdonly tablets are skipped",
+			tabletTypes: map[uint32]topodatapb.TabletType{
+				101: topodatapb.TabletType_REPLICA,
+				102: topodatapb.TabletType_RDONLY,
+				103: topodatapb.TabletType_RDONLY,
+			},
+			reattached: 1,
+			skipped:    []string{"zone1-0000000102", "zone1-0000000103"},
+		}, {
+			name: "all replicas are skipped",
+			tabletTypes: map[uint32]topodatapb.TabletType{
+				101: topodatapb.TabletType_RDONLY,
+				102: topodatapb.TabletType_RDONLY,
+			},
+			reattached: 0,
+			skipped:    []string{"zone1-0000000101", "zone1-0000000102"},
 		},
 	}
 
-	durability, _ := GetDurabilityPolicy("none")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.remoteOpTimeout != 0 {
-				oldTimeout := topo.RemoteOperationTimeout
-				topo.RemoteOperationTimeout = tt.remoteOpTimeout
-				defer func() {
-					topo.RemoteOperationTimeout = oldTimeout
-				}()
+			newPrimary := &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				Type:  topodatapb.TabletType_REPLICA,
 			}
-
-			logger := logutil.NewMemoryLogger()
-			ev := &events.Reparent{
-				ShardInfo: topo.ShardInfo{
-					Shard: &topodatapb.Shard{
-						PrimaryAlias: &topodatapb.TabletAlias{
-							Cell: "zone1",
-							Uid:  000,
+			tabletMap := map[string]*topo.TabletInfo{
+				"zone1-0000000100": {Tablet: newPrimary},
+			}
+			tmc := &setReplicationSourceCountingTMC{
+				TabletManagerClient: &testutil.TabletManagerClient{
+					PopulateReparentJournalResults: map[string]error{
+						"zone1-0000000100": nil,
+					},
+					PromoteReplicaResults: map[string]struct {
+						Result string
+						Error  error
+					}{
+						"zone1-0000000100": {
+							Result: "ok",
 						},
 					},
+					SetReplicationSourceResults: map[string]error{},
 				},
 			}
+			for uid, tabletType := range tt.tabletTypes {
+				alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+				tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias, Type: tabletType}}
+				tmc.SetReplicationSourceResults[topoproto.TabletAliasString(alias)] = nil
+			}
+
+			durability, _ := GetDurabilityPolicy("none")
+			opts := EmergencyReparentOptions{
+				WaitReplicasTimeout: time.Minute,
+				SkipReplicaTypes:    []topodatapb.TabletType{topodatapb.TabletType_RDONLY},
+				durability:          durability,
+			}
+			ev := &events.Reparent{
+				ShardInfo: *topo.NewShardInfo("testkeyspace", "-", &topodatapb.Shard{PrimaryAlias: newPrimary.Alias}, nil),
+			}
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 			ts := memorytopo.NewServer(ctx, "zone1")
 			defer ts.Close()
 
-			testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
-				Keyspace: tt.keyspace,
-				Name:     tt.shard,
-			})
+			erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+			_, err := erp.reparentReplicas(ctx, ev, newPrimary, tabletMap, nil, opts, false /* intermediateReparent */)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reattached, tmc.calls.Load())
+			assert.Equal(t, tt.skipped, ev.SkippedReplicas)
+			assert.True(t, ev.ReparentJournalPopulated)
+		})
+	}
+}
 
-			if !tt.unlockTopo {
-				var (
-					unlock func(*error)
-					lerr   error
-				)
+func TestEmergencyReparenter_verifyReplicasConverging(t *testing.T) {
+	defer func(interval time.Duration) {
+		replicaConvergenceInterval = interval
+	}(replicaConvergenceInterval)
+	replicaConvergenceInterval = 10 * time.Millisecond
 
-				ctx, unlock, lerr = ts.LockShard(ctx, tt.keyspace, tt.shard, "test lock")
-				require.NoError(t, lerr, "could not lock %s/%s for test", tt.keyspace, tt.shard)
+	const (
+		primaryPosition = "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-10"
+		stuckPosition   = "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-7"
+	)
 
-				defer func() {
-					unlock(&lerr)
-					require.NoError(t, lerr, "could not unlock %s/%s after test", tt.keyspace, tt.shard)
-				}()
-			}
-			tabletInfo := tt.tabletMap[tt.newPrimaryTabletAlias]
+	tabletMap := map[string]*topo.TabletInfo{}
+	for uid := uint32(100); uid <= 103; uid++ {
+		alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}
+		tabletMap[topoproto.TabletAliasString(alias)] = &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: alias}}
+	}
+	tmc := &testutil.TabletManagerClient{
+		PrimaryPositionResults: map[string]struct {
+			Position string
+			Error    error
+		}{
+			"zone1-0000000100": {Position: primaryPosition},
+		},
+		ReplicationStatusResults: map[string]struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			"zone1-0000000101": {Position: &replicationdatapb.Status{Position: primaryPosition}},
+			// the position of this replica does not advance between the samples
+			"zone1-0000000102": {Position: &replicationdatapb.Status{Position: stuckPosition}},
+			// this replica is ignored, so its position is never sampled
+			"zone1-0000000103": {Error: assert.AnError},
+		},
+	}
+	opts := EmergencyReparentOptions{
+		IgnoreReplicas: sets.New[string]("zone1-0000000103"),
+	}
 
-			tt.emergencyReparentOps.durability = durability
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
 
-			erp := NewEmergencyReparenter(ts, tt.tmc, logger)
-			_, err := erp.reparentReplicas(ctx, ev, tabletInfo.Tablet, tt.tabletMap, tt.statusMap, tt.emergencyReparentOps, false /* intermediateReparent */)
-			if tt.shouldErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errShouldContain)
-				return
-			}
+	erp := NewEmergencyReparenter(ts, tmc, logutil.NewMemoryLogger())
+	warnings := erp.verifyReplicasConverging(ctx, tabletMap["zone1-0000000100"].Tablet, tabletMap, opts)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "replica zone1-0000000102 appears stuck")
 
-			assert.NoError(t, err)
-		})
-	}
+	// the replicas can't be checked at all if we can't get the position of the primary
+	tmc.PrimaryPositionResults = nil
+	warnings = erp.verifyReplicasConverging(ctx, tabletMap["zone1-0000000100"].Tablet, tabletMap, opts)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "failed to get the position of the new primary zone1-0000000100")
 }
 
 func TestEmergencyReparenter_promoteIntermediateSource(t *testing.T) {
@@ -4279,6 +7094,111 @@ func TestEmergencyReparenter_identifyPrimaryCandidate(t *testing.T) {
 					Uid:  102,
 				},
 			},
+		}, {
+			name: "vetoed candidate is replaced by the next best one",
+			emergencyReparentOps: EmergencyReparentOptions{VetoCandidate: func(candidate *topodatapb.Tablet) error {
+				if candidate.Alias.Uid == 100 {
+					return errors.New("kernel upgrade in progress")
+				}
+				return nil
+			}},
+			intermediateSource: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			validCandidates: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Type: topodatapb.TabletType_RDONLY,
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				},
+			},
+			tabletMap: nil,
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+		}, {
+			name: "all candidates vetoed",
+			emergencyReparentOps: EmergencyReparentOptions{VetoCandidate: func(candidate *topodatapb.Tablet) error {
+				return errors.New("kernel upgrade in progress")
+			}},
+			intermediateSource: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			validCandidates: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				},
+			},
+			tabletMap: nil,
+			err:       "all the valid candidates for emergency reparent were vetoed, the last one being zone1-0000000101: kernel upgrade in progress",
+		}, {
+			name: "explicitly requested candidate vetoed",
+			emergencyReparentOps: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				VetoCandidate: func(candidate *topodatapb.Tablet) error {
+					return errors.New("kernel upgrade in progress")
+				},
+			},
+			validCandidates: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+				},
+			},
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": {
+					Tablet: &topodatapb.Tablet{
+						Alias: &topodatapb.TabletAlias{
+							Cell: "zone1",
+							Uid:  100,
+						},
+					},
+				},
+			},
+			err: "requested candidate zone1-0000000100 was vetoed: kernel upgrade in progress",
 		},
 	}
 
@@ -4372,6 +7292,84 @@ func TestParentContextCancelled(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCheckDurabilityReachable(t *testing.T) {
+	tablet := func(uid uint32, cell string, tabletType topodatapb.TabletType) *topo.TabletInfo {
+		return &topo.TabletInfo{
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: cell,
+					Uid:  uid,
+				},
+				Type: tabletType,
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		durability       string
+		tabletMap        map[string]*topo.TabletInfo
+		opts             EmergencyReparentOptions
+		errShouldContain string
+	}{
+		{
+			name:       "a candidate can establish",
+			durability: "semi_sync",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tablet(100, "zone1", topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tablet(101, "zone1", topodatapb.TabletType_REPLICA),
+			},
+		}, {
+			name:       "no ackers for any candidate",
+			durability: "semi_sync",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tablet(100, "zone1", topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tablet(101, "zone1", topodatapb.TabletType_RDONLY),
+			},
+			errShouldContain: "no candidate can satisfy durability policy semi_sync",
+		}, {
+			name:       "ignored tablets cannot ack",
+			durability: "cross_cell",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tablet(100, "zone1", topodatapb.TabletType_PRIMARY),
+				"zone2-0000000101": tablet(101, "zone2", topodatapb.TabletType_REPLICA),
+			},
+			opts: EmergencyReparentOptions{
+				IgnoreReplicas: sets.New[string]("zone2-0000000101"),
+			},
+			errShouldContain: "no candidate can satisfy durability policy cross_cell",
+		}, {
+			name:       "requested primary cannot establish",
+			durability: "cross_cell",
+			tabletMap: map[string]*topo.TabletInfo{
+				"zone1-0000000100": tablet(100, "zone1", topodatapb.TabletType_PRIMARY),
+				"zone1-0000000101": tablet(101, "zone1", topodatapb.TabletType_REPLICA),
+				"zone2-0000000102": tablet(102, "zone2", topodatapb.TabletType_REPLICA),
+			},
+			opts: EmergencyReparentOptions{
+				NewPrimaryAlias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  102,
+				},
+				IgnoreReplicas: sets.New[string]("zone1-0000000100", "zone1-0000000101"),
+			},
+			errShouldContain: "proposed primary zone2-0000000102 will not be able to make forward progress on being promoted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			durability, err := GetDurabilityPolicy(tt.durability)
+			require.NoError(t, err)
+			err = checkDurabilityReachable(durability, tt.durability, tt.tabletMap, tt.opts)
+			if tt.errShouldContain != "" {
+				require.ErrorContains(t, err, tt.errShouldContain)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestEmergencyReparenter_filterValidCandidates(t *testing.T) {
 	var (
 		primaryTablet = &topodatapb.Tablet{
@@ -4490,6 +7488,65 @@ func TestEmergencyReparenter_filterValidCandidates(t *testing.T) {
 				NewPrimaryAlias: primaryTablet.Alias,
 			},
 			errShouldContain: "proposed primary zone-1-0000000001 will not be able to make forward progress on being promoted",
+		}, {
+			name:             "filter IO errored",
+			durability:       "none",
+			validTablets:     []*topodatapb.Tablet{primaryTablet, replicaTablet, replicaCrossCellTablet},
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				ExcludeIOErrored:  true,
+				ioErroredReplicas: sets.New[string]("zone-1-0000000002"),
+			},
+			filteredTablets: []*topodatapb.Tablet{primaryTablet, replicaCrossCellTablet},
+		}, {
+			name:             "keep IO errored if there is no other candidate",
+			durability:       "none",
+			validTablets:     []*topodatapb.Tablet{replicaTablet, replicaCrossCellTablet},
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				ExcludeIOErrored:  true,
+				ioErroredReplicas: sets.New[string]("zone-1-0000000002", "zone-2-0000000002"),
+			},
+			filteredTablets: []*topodatapb.Tablet{replicaTablet, replicaCrossCellTablet},
+		}, {
+			name:             "keep requested primary even if IO errored",
+			durability:       "none",
+			validTablets:     []*topodatapb.Tablet{primaryTablet, replicaTablet},
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				ExcludeIOErrored:  true,
+				NewPrimaryAlias:   replicaTablet.Alias,
+				ioErroredReplicas: sets.New[string]("zone-1-0000000002"),
+			},
+			filteredTablets: []*topodatapb.Tablet{primaryTablet, replicaTablet},
+		}, {
+			name:             "filter avoided",
+			durability:       "none",
+			validTablets:     []*topodatapb.Tablet{primaryTablet, replicaTablet, replicaCrossCellTablet},
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				AvoidPrimaryAliases: sets.New[string]("zone-1-0000000001", "zone-2-0000000002"),
+			},
+			filteredTablets: []*topodatapb.Tablet{replicaTablet},
+		}, {
+			name:             "error - all candidates avoided",
+			durability:       "none",
+			validTablets:     []*topodatapb.Tablet{primaryTablet, replicaTablet},
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				AvoidPrimaryAliases: sets.New[string]("zone-1-0000000001", "zone-1-0000000002"),
+			},
+			errShouldContain: "all candidates are in avoid list: zone-1-0000000001, zone-1-0000000002",
+		}, {
+			name:             "error - requested primary avoided",
+			durability:       "none",
+			validTablets:     allTablets,
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				NewPrimaryAlias:     replicaTablet.Alias,
+				AvoidPrimaryAliases: sets.New[string]("zone-1-0000000002"),
+			},
+			errShouldContain: "proposed primary zone-1-0000000002 is in the avoid list",
 		},
 	}
 	for _, tt := range tests {
@@ -23,7 +23,6 @@ import (
 
 	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/mysql/replication"
-	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/log"
@@ -46,8 +45,19 @@ func FindValidEmergencyReparentCandidates(
 	statusMap map[string]*replicationdatapb.StopReplicationStatus,
 	primaryStatusMap map[string]*replicationdatapb.PrimaryStatus,
 ) (map[string]replication.Position, error) {
+	positionMap, _, err := findValidEmergencyReparentCandidates(statusMap, primaryStatusMap)
+	return positionMap, err
+}
+
+// findValidEmergencyReparentCandidates is FindValidEmergencyReparentCandidates,
+// also returning the errant GTID set of each tablet it left out for having one.
+func findValidEmergencyReparentCandidates(
+	statusMap map[string]*replicationdatapb.StopReplicationStatus,
+	primaryStatusMap map[string]*replicationdatapb.PrimaryStatus,
+) (map[string]replication.Position, map[string]string, error) {
 	replicationStatusMap := make(map[string]*replication.ReplicationStatus, len(statusMap))
 	positionMap := make(map[string]replication.Position)
+	errantGTIDMap := make(map[string]string)
 
 	// Build out replication status list from proto types.
 	for alias, statuspb := range statusMap {
@@ -79,11 +89,11 @@ func FindValidEmergencyReparentCandidates(
 	}
 
 	if isGTIDBased && emptyRelayPosErrorRecorder.HasErrors() {
-		return nil, emptyRelayPosErrorRecorder.Error()
+		return nil, nil, emptyRelayPosErrorRecorder.Error()
 	}
 
 	if isGTIDBased && isNonGTIDBased {
-		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "encountered mix of GTID-based and non GTID-based relay logs")
+		return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "encountered mix of GTID-based and non GTID-based relay logs")
 	}
 
 	// Create relevant position list of errant GTID-based positions for later
@@ -101,7 +111,7 @@ func FindValidEmergencyReparentCandidates(
 		// in the earlier loop, but let's be doubly sure.
 		relayLogGTIDSet, ok := status.RelayLogPosition.GTIDSet.(replication.Mysql56GTIDSet)
 		if !ok {
-			return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "we got a filled-in relay log position, but it's not of type Mysql56GTIDSet, even though we've determined we need to use GTID based assesment")
+			return nil, nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "we got a filled-in relay log position, but it's not of type Mysql56GTIDSet, even though we've determined we need to use GTID based assesment")
 		}
 
 		// We need to remove this alias's status from the list, otherwise the
@@ -119,11 +129,12 @@ func FindValidEmergencyReparentCandidates(
 		case err != nil:
 			// Could not look up GTIDs to determine if we have any. It's not
 			// safe to continue.
-			return nil, err
+			return nil, nil, err
 		case len(errantGTIDs) != 0:
 			// This tablet has errant GTIDs. It's not a valid candidate for
 			// reparent, so don't insert it into the final mapping.
 			log.Errorf("skipping %v because we detected errant GTIDs - %v", alias, errantGTIDs)
+			errantGTIDMap[alias] = errantGTIDs.String()
 			continue
 		}
 
@@ -134,13 +145,13 @@ func FindValidEmergencyReparentCandidates(
 	for alias, primaryStatus := range primaryStatusMap {
 		executedPosition, err := replication.DecodePosition(primaryStatus.Position)
 		if err != nil {
-			return nil, vterrors.Wrapf(err, "could not decode a primary status executed position for tablet %v: %v", alias, err)
+			return nil, nil, vterrors.Wrapf(err, "could not decode a primary status executed position for tablet %v: %v", alias, err)
 		}
 
 		positionMap[alias] = executedPosition
 	}
 
-	return positionMap, nil
+	return positionMap, errantGTIDMap, nil
 }
 
 // ReplicaWasRunning returns true if a StopReplicationStatus indicates that the
@@ -250,8 +261,7 @@ func stopReplicationAndBuildStatusMaps(
 
 		stopReplicationStatus, err := tmc.StopReplicationAndGetStatus(groupCtx, tabletInfo.Tablet, replicationdatapb.StopReplicationMode_IOTHREADONLY)
 		if err != nil {
-			sqlErr, isSQLErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
-			if isSQLErr && sqlErr != nil && sqlErr.Number() == sqlerror.ERNotReplica {
+			if isNotReplicaError(err) {
 				var primaryStatus *replicationdatapb.PrimaryStatus
 
 				primaryStatus, err = tmc.DemotePrimary(groupCtx, tabletInfo.Tablet)
@@ -64,8 +64,10 @@ func TestFindValidEmergencyReparentCandidates(t *testing.T) {
 		// point is, the combination of (1) whether the test should error and
 		// (2) the set of keys we expect in the map is enough to fully assert on
 		// the correctness of the behavior of this functional unit.
-		expected  []string
-		shouldErr bool
+		expected []string
+		// expectedErrant holds the errant GTID sets of the tablets left out.
+		expectedErrant map[string]string
+		shouldErr      bool
 	}{
 		{
 			name: "success",
@@ -181,7 +183,10 @@ func TestFindValidEmergencyReparentCandidates(t *testing.T) {
 					Position: "MySQL56/3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
 				},
 			},
-			expected:  []string{"r1", "p1"},
+			expected: []string{"r1", "p1"},
+			expectedErrant: map[string]string{
+				"errant": "aaaaaaaa-71ca-11e1-9e33-c80aa9429562:1",
+			},
 			shouldErr: false,
 		},
 		{
@@ -208,13 +213,18 @@ func TestFindValidEmergencyReparentCandidates(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			actual, err := FindValidEmergencyReparentCandidates(tt.statusMap, tt.primaryStatusMap)
+			actual, errant, err := findValidEmergencyReparentCandidates(tt.statusMap, tt.primaryStatusMap)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
 			assert.NoError(t, err)
+			if tt.expectedErrant == nil {
+				assert.Empty(t, errant)
+			} else {
+				assert.Equal(t, tt.expectedErrant, errant)
+			}
 
 			keys := make([]string, 0, len(actual))
 			for key := range actual {
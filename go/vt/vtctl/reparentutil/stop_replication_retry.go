@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// stopReplicationRetryingTabletManagerClient is the TabletManagerClient used to
+// stop replication when StopReplicationRetries is set. It retries the calls to
+// StopReplicationAndGetStatus of each tablet that fail, until the context is
+// done.
+type stopReplicationRetryingTabletManagerClient struct {
+	tmclient.TabletManagerClient
+
+	retries int
+	delay   time.Duration
+	logger  logutil.Logger
+}
+
+// StopReplicationAndGetStatus calls StopReplicationAndGetStatus on the tablet,
+// retrying it up to retries times if it fails. A tablet reporting that it is
+// not a replica is not retried, since that is an answer rather than a failure.
+func (c stopReplicationRetryingTabletManagerClient) StopReplicationAndGetStatus(ctx context.Context, tablet *topodatapb.Tablet, mode replicationdatapb.StopReplicationMode) (*replicationdatapb.StopReplicationStatus, error) {
+	for attempt := 0; ; attempt++ {
+		status, err := c.TabletManagerClient.StopReplicationAndGetStatus(ctx, tablet, mode)
+		if err == nil || attempt >= c.retries || isNotReplicaError(err) {
+			return status, err
+		}
+		c.logger.Warningf("failed to stop replication on %v, retrying (%d/%d): %v", topoproto.TabletAliasString(tablet.Alias), attempt+1, c.retries, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(c.delay):
+		}
+	}
+}
+
+// isNotReplicaError returns true if the error is the ERNotReplica SQL error.
+func isNotReplicaError(err error) bool {
+	sqlErr, isSQLErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	return isSQLErr && sqlErr != nil && sqlErr.Number() == sqlerror.ERNotReplica
+}
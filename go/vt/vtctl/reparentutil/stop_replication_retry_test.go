@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// flakyStopReplicationTMClient fails the first failures calls to
+// StopReplicationAndGetStatus with err.
+type flakyStopReplicationTMClient struct {
+	tmclient.TabletManagerClient
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyStopReplicationTMClient) StopReplicationAndGetStatus(ctx context.Context, tablet *topodatapb.Tablet, mode replicationdatapb.StopReplicationMode) (*replicationdatapb.StopReplicationStatus, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return &replicationdatapb.StopReplicationStatus{}, nil
+}
+
+func TestStopReplicationRetryingTabletManagerClient(t *testing.T) {
+	errTransient := errors.New("connection reset by peer")
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		retries   int
+		delay     time.Duration
+		timeout   time.Duration
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "succeeds after retries",
+			failures:  2,
+			err:       errTransient,
+			retries:   2,
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up after retries",
+			failures:  3,
+			err:       errTransient,
+			retries:   2,
+			wantErr:   errTransient,
+			wantCalls: 3,
+		},
+		{
+			name:      "not a replica is not retried",
+			failures:  1,
+			err:       mysql.ErrNotReplica,
+			retries:   2,
+			wantErr:   mysql.ErrNotReplica,
+			wantCalls: 1,
+		},
+		{
+			name:      "retries stop with the context",
+			failures:  3,
+			err:       errTransient,
+			retries:   2,
+			delay:     time.Hour,
+			timeout:   10 * time.Millisecond,
+			wantErr:   errTransient,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			flaky := &flakyStopReplicationTMClient{failures: tt.failures, err: tt.err}
+			tmc := stopReplicationRetryingTabletManagerClient{
+				TabletManagerClient: flaky,
+				retries:             tt.retries,
+				delay:               tt.delay,
+				logger:              logutil.NewMemoryLogger(),
+			}
+			_, err := tmc.StopReplicationAndGetStatus(ctx, &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}, replicationdatapb.StopReplicationMode_IOTHREADONLY)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, flaky.calls)
+		})
+	}
+}
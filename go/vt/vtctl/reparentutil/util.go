@@ -17,8 +17,10 @@ limitations under the License.
 package reparentutil
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +30,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/log"
@@ -47,6 +50,7 @@ var (
 	reparentShardOpTimings = stats.NewTimings("ReparentShardOperationTimings", "Timings of reparent shard operations", "Operation")
 	failureResult          = "failure"
 	successResult          = "success"
+	noopResult             = "noop"
 )
 
 // ElectNewPrimary finds a tablet that should become a primary after reparent.
@@ -319,6 +323,126 @@ func restrictValidCandidates(validCandidates map[string]replication.Position, ta
 	return restrictedValidCandidates, nil
 }
 
+// restrictGTIDModeCandidates removes the candidates whose position isn't GTID based, which is the case for
+// tablets that are not running with GTID_MODE=ON. It fails if the requested new primary is removed, or if
+// no candidate is left.
+func restrictGTIDModeCandidates(validCandidates map[string]replication.Position, newPrimaryAlias *topodatapb.TabletAlias) (map[string]replication.Position, error) {
+	restrictedValidCandidates := make(map[string]replication.Position)
+	for candidate, position := range validCandidates {
+		if _, isFilePos := position.GTIDSet.(replication.FilePosGTID); position.IsZero() || isFilePos {
+			log.Infof("Removing %s from list of valid candidates for promotion because it is not operating in GTID mode", candidate)
+			if newPrimaryAlias != nil && candidate == topoproto.TabletAliasString(newPrimaryAlias) {
+				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s is not operating in GTID mode", candidate)
+			}
+			continue
+		}
+		restrictedValidCandidates[candidate] = position
+	}
+	if len(restrictedValidCandidates) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no valid candidates operating in GTID mode for emergency reparent")
+	}
+	return restrictedValidCandidates, nil
+}
+
+// findDirectReplicas returns the aliases of the tablets in the status map which were replicating
+// directly from the given primary before replication was stopped on them.
+func findDirectReplicas(statusMap map[string]*replicationdatapb.StopReplicationStatus, primary *topodatapb.Tablet) sets.Set[string] {
+	directReplicas := sets.New[string]()
+	if primary == nil || primary.MysqlHostname == "" {
+		return directReplicas
+	}
+	for alias, status := range statusMap {
+		if status.Before == nil {
+			continue
+		}
+		if status.Before.SourceHost == primary.MysqlHostname && status.Before.SourcePort == primary.MysqlPort {
+			directReplicas.Insert(alias)
+		}
+	}
+	return directReplicas
+}
+
+// preferDirectReplica moves the first direct replica that is tied with the first tablet of the sorted list,
+// both in position and in promotion rule, to the front of the list.
+func preferDirectReplica(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler, directReplicas sets.Set[string]) {
+	preferTiedTablet(tablets, positions, durability, func(tablet *topodatapb.Tablet) bool {
+		return directReplicas.Has(topoproto.TabletAliasString(tablet.Alias))
+	})
+}
+
+// avoidIOErroredReplica moves the first tablet without an IO thread error that is tied with the first tablet
+// of the sorted list, both in position and in promotion rule, to the front of the list.
+func avoidIOErroredReplica(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler, ioErroredReplicas sets.Set[string]) {
+	preferTiedTablet(tablets, positions, durability, func(tablet *topodatapb.Tablet) bool {
+		return !ioErroredReplicas.Has(topoproto.TabletAliasString(tablet.Alias))
+	})
+}
+
+// preferTiedTablet moves the first preferred tablet that is tied with the first tablet of the sorted list,
+// both in position and in promotion rule, to the front of the list.
+func preferTiedTablet(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler, isPreferred func(*topodatapb.Tablet) bool) {
+	if len(tablets) == 0 || isPreferred(tablets[0]) {
+		return
+	}
+	bestRule := PromotionRule(durability, tablets[0])
+	for i := 1; i < len(tablets); i++ {
+		if !positions[i].Equal(positions[0]) || PromotionRule(durability, tablets[i]) != bestRule {
+			return
+		}
+		if isPreferred(tablets[i]) {
+			tablets[0], tablets[i] = tablets[i], tablets[0]
+			positions[0], positions[i] = positions[i], positions[0]
+			return
+		}
+	}
+}
+
+// sortTiedTabletsByScore reorders each run of consecutive tablets of the sorted list that are tied,
+// both in position and in promotion rule, by decreasing score. Tablets with the same score keep
+// their order.
+func sortTiedTabletsByScore(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler, scorer func(*topodatapb.Tablet, replication.Position) int) {
+	for start := 0; start < len(tablets); {
+		rule := PromotionRule(durability, tablets[start])
+		end := start + 1
+		for end < len(tablets) && positions[end].Equal(positions[start]) && PromotionRule(durability, tablets[end]) == rule {
+			end++
+		}
+
+		scores := make(map[*topodatapb.Tablet]int, end-start)
+		for i := start; i < end; i++ {
+			scores[tablets[i]] = scorer(tablets[i], positions[i])
+		}
+		// the positions of the run are all equal, so only the tablets need to move.
+		slices.SortStableFunc(tablets[start:end], func(a, b *topodatapb.Tablet) int {
+			return cmp.Compare(scores[b], scores[a])
+		})
+		start = end
+	}
+}
+
+// preferTaggedTablets moves the tablets whose tag with the given key has the given value ahead of the other
+// tablets of the sorted list they are tied with, both in position and in promotion rule.
+func preferTaggedTablets(tablets []*topodatapb.Tablet, positions []replication.Position, durability Durabler, key, value string) {
+	sortTiedTabletsByScore(tablets, positions, durability, func(tablet *topodatapb.Tablet, _ replication.Position) int {
+		if tag, ok := tablet.Tags[key]; ok && tag == value {
+			return 1
+		}
+		return 0
+	})
+}
+
+// findIOErroredReplicas returns the aliases of the tablets in the status map whose IO thread
+// was reporting an error before replication was stopped on them.
+func findIOErroredReplicas(statusMap map[string]*replicationdatapb.StopReplicationStatus) sets.Set[string] {
+	ioErroredReplicas := sets.New[string]()
+	for alias, status := range statusMap {
+		if status.Before != nil && status.Before.LastIoError != "" {
+			ioErroredReplicas.Insert(alias)
+		}
+	}
+	return ioErroredReplicas
+}
+
 func findCandidate(
 	intermediateSource *topodatapb.Tablet,
 	possibleCandidates []*topodatapb.Tablet,
@@ -28,6 +28,7 @@ import (
 	"vitess.io/vitess/go/mysql/replication"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
@@ -1352,6 +1353,89 @@ func TestRestrictValidCandidates(t *testing.T) {
 	}
 }
 
+func TestRestrictGTIDModeCandidates(t *testing.T) {
+	gtidPos := replication.MustParsePosition(replication.Mysql56FlavorID, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	filePos := replication.Position{GTIDSet: replication.FilePosGTID{File: "binlog.000001", Pos: 4}}
+
+	tests := []struct {
+		name             string
+		validCandidates  map[string]replication.Position
+		newPrimaryAlias  *topodatapb.TabletAlias
+		result           map[string]replication.Position
+		errShouldContain string
+	}{
+		{
+			name: "remove non-GTID candidates",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": gtidPos,
+				"zone1-0000000101": filePos,
+				"zone1-0000000102": {},
+			},
+			result: map[string]replication.Position{
+				"zone1-0000000100": gtidPos,
+			},
+		},
+		{
+			name: "requested primary is not in GTID mode",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000100": gtidPos,
+				"zone1-0000000101": filePos,
+			},
+			newPrimaryAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			errShouldContain: "proposed primary zone1-0000000101 is not operating in GTID mode",
+		},
+		{
+			name: "no candidate in GTID mode",
+			validCandidates: map[string]replication.Position{
+				"zone1-0000000101": filePos,
+				"zone1-0000000102": {},
+			},
+			errShouldContain: "no valid candidates operating in GTID mode",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := restrictGTIDModeCandidates(test.validCandidates, test.newPrimaryAlias)
+			if test.errShouldContain != "" {
+				require.ErrorContains(t, err, test.errShouldContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.result, res)
+		})
+	}
+}
+
+func Test_findDirectReplicas(t *testing.T) {
+	primary := &topodatapb.Tablet{
+		Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		MysqlHostname: "primary-host",
+		MysqlPort:     3306,
+	}
+	statusMap := map[string]*replicationdatapb.StopReplicationStatus{
+		"zone1-0000000101": {
+			Before: &replicationdatapb.Status{SourceHost: "primary-host", SourcePort: 3306},
+		},
+		"zone1-0000000102": {
+			Before: &replicationdatapb.Status{SourceHost: "replica-host", SourcePort: 3306},
+		},
+		"zone1-0000000103": {
+			Before: &replicationdatapb.Status{SourceHost: "primary-host", SourcePort: 3307},
+		},
+		"zone1-0000000104": {},
+	}
+
+	directReplicas := findDirectReplicas(statusMap, primary)
+	require.Equal(t, []string{"zone1-0000000101"}, sets.List(directReplicas))
+
+	directReplicas = findDirectReplicas(statusMap, nil)
+	require.Zero(t, directReplicas.Len())
+}
+
 func Test_findCandidate(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -1615,3 +1699,22 @@ func TestGetBackupCandidates(t *testing.T) {
 		})
 	}
 }
+
+func Test_findIOErroredReplicas(t *testing.T) {
+	statusMap := map[string]*replicationdatapb.StopReplicationStatus{
+		"zone1-0000000101": {
+			Before: &replicationdatapb.Status{
+				IoState:     int32(replication.ReplicationStateConnecting),
+				LastIoError: "error connecting to source",
+			},
+		},
+		"zone1-0000000102": {
+			Before: &replicationdatapb.Status{
+				IoState: int32(replication.ReplicationStateRunning),
+			},
+		},
+		"zone1-0000000103": {},
+	}
+
+	assert.ElementsMatch(t, []string{"zone1-0000000101"}, sets.List(findIOErroredReplicas(statusMap)))
+}
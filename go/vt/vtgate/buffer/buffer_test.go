@@ -284,6 +284,56 @@ func testPassthrough1(t *testing.T, fail failover) {
 	}
 }
 
+// TestDelay tests that a buffered request records how long it was buffered in
+// the Delay of its context.
+func TestDelay(t *testing.T) {
+	testAllImplementations(t, testDelay1)
+}
+
+func testDelay1(t *testing.T, fail failover) {
+	resetVariables()
+	defer checkVariables(t)
+
+	now := time.Now()
+	cfg := NewDefaultConfig()
+	cfg.Enabled = true
+	cfg.Shards = map[string]bool{
+		topoproto.KeyspaceShardString(keyspace, shard): true,
+	}
+	cfg.now = func() time.Time { return now }
+
+	b := New(cfg)
+	fail(b, oldPrimary, keyspace, shard, now)
+
+	// A request that is not buffered has no delay.
+	ctx, passthroughDelay := WithDelay(context.Background())
+	if retryDone, err := b.WaitForFailoverEnd(ctx, keyspace, shard, nil); err != nil || retryDone != nil {
+		t.Fatalf("requests with no error must never be buffered. err: %v retryDone: %v", err, retryDone)
+	}
+	assert.Zero(t, passthroughDelay.Get())
+
+	ctx, delay := WithDelay(context.Background())
+	stopped := issueRequest(ctx, t, b, failoverErr)
+	if err := waitForRequestsInFlight(b, 1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	now = now.Add(1 * time.Second)
+	fail(b, newPrimary, keyspace, shard, now)
+	if err := <-stopped; err != nil {
+		t.Fatalf("request should have been buffered and not returned an error: %v", err)
+	}
+	assert.GreaterOrEqual(t, delay.Get(), 50*time.Millisecond)
+
+	if err := waitForState(b, stateIdle); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitForPoolSlots(b, cfg.Size); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestLastReparentTooRecentBufferingSkipped tests that buffering is skipped if
 // we see the reparent (end) *before* any request failures due to it.
 // We must not start buffering because we already observed the trigger for
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buffer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type delayKey struct{}
+
+// Delay records how long the requests of a query were buffered before they
+// were retried or failed. A query can send requests to several shards, so it keeps the
+// longest of their delays.
+type Delay struct {
+	max atomic.Int64
+}
+
+// WithDelay returns a context whose requests record how long they were
+// buffered in the returned Delay.
+func WithDelay(ctx context.Context) (context.Context, *Delay) {
+	d := &Delay{}
+	return context.WithValue(ctx, delayKey{}, d), d
+}
+
+// Get returns the longest time a request was buffered, or 0 if none of the
+// requests was buffered.
+func (d *Delay) Get() time.Duration {
+	return time.Duration(d.max.Load())
+}
+
+func (d *Delay) record(delay time.Duration) {
+	for {
+		current := d.max.Load()
+		if int64(delay) <= current || d.max.CompareAndSwap(current, int64(delay)) {
+			return
+		}
+	}
+}
+
+// recordDelay records the buffering delay of a request in the Delay of its
+// context, if it has one.
+func recordDelay(ctx context.Context, delay time.Duration) {
+	if d, ok := ctx.Value(delayKey{}).(*Delay); ok {
+		d.record(delay)
+	}
+}
@@ -385,6 +385,10 @@ func (sb *shardBuffer) waitForRequestFinish(e *entry, releaseSlot, async bool) {
 // wait blocks while the request is buffered during the failover.
 // See Buffer.WaitForFailoverEnd() for the API contract of the return values.
 func (sb *shardBuffer) wait(ctx context.Context, e *entry) (RetryDoneFunc, error) {
+	// the delay is recorded even if the request fails, as it was still held
+	// up by the failover.
+	start := time.Now()
+	defer func() { recordDelay(ctx, time.Since(start)) }()
 	select {
 	case <-ctx.Done():
 		sb.remove(e)
@@ -833,7 +833,7 @@ func (cached *Projection) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(64)
+		size += int64(72)
 	}
 	// field Cols []string
 	{
@@ -855,6 +855,26 @@ func (cached *Projection) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Input.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Stats *vitess.io/vitess/go/vt/vtgate/engine.ProjectionStats
+	size += cached.Stats.CachedSize(true)
+	return size
+}
+func (cached *ProjectionStats) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(56)
+	}
+	// field rows []sync/atomic.Int64
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.rows)) * int64(8))
+	}
+	// field evalCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.evalCols)) * int64(8))
+	}
 	return size
 }
 func (cached *RenameFields) CachedSize(alloc bool) int64 {
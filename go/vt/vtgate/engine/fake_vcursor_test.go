@@ -243,6 +243,9 @@ func (t *noopVCursor) InTransactionAndIsDML() bool {
 	panic("implement me")
 }
 
+func (t *noopVCursor) RecordProjectionEval(int, time.Duration) {
+}
+
 func (t *noopVCursor) FindRoutedTable(sqlparser.TableName) (*vindexes.Table, error) {
 	panic("implement me")
 }
@@ -348,6 +351,10 @@ func (t *noopVCursor) Execute(ctx context.Context, method string, query string,
 	panic("unimplemented")
 }
 
+func (t *noopVCursor) ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error) {
+	panic("unimplemented")
+}
+
 func (t *noopVCursor) ExecuteMultiShard(ctx context.Context, primitive Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	panic("unimplemented")
 }
@@ -552,6 +559,11 @@ func (f *loggingVCursor) Execute(ctx context.Context, method string, query strin
 	return f.nextResult()
 }
 
+func (f *loggingVCursor) ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error) {
+	f.log = append(f.log, fmt.Sprintf("ExecuteOnTabletType %s %s %v", tabletType.String(), query, printBindVars(bindVars)))
+	return f.nextResult()
+}
+
 func (f *loggingVCursor) ExecuteMultiShard(ctx context.Context, primitive Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteMultiShard %v%v %v", printResolvedShardQueries(rss, queries), rollbackOnError, canAutocommit))
 	res, err := f.nextResult()
@@ -1021,13 +1021,6 @@ func TestInsertShardedIgnoreOwned(t *testing.T) {
 		"3|\x00",
 		"4|\x00",
 	)
-	ksid0 := sqltypes.MakeTestResult(
-		sqltypes.MakeTestFields(
-			"to",
-			"varbinary",
-		),
-		"\x00",
-	)
 	noresult := &sqltypes.Result{}
 	vc := newDMLTestVCursor("-20", "20-")
 	vc.shardForKsid = []string{"20-", "-20"}
@@ -1037,14 +1030,25 @@ func TestInsertShardedIgnoreOwned(t *testing.T) {
 		// insert lkp2
 		noresult,
 		// fail one verification (row 3)
-		ksid0,
-		noresult,
-		ksid0,
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"from1|toc",
+				"int64|varbinary",
+			),
+			"5|\x00",
+			"8|\x00",
+		),
 		// insert lkp1
 		noresult,
 		// verify lkp1 (only two rows to verify)
-		ksid0,
-		ksid0,
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"from|toc",
+				"int64|varbinary",
+			),
+			"13|\x00",
+			"16|\x00",
+		),
 	}
 
 	_, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
@@ -1059,15 +1063,16 @@ func TestInsertShardedIgnoreOwned(t *testing.T) {
 			`from1_0: type:INT64 value:"5" from1_1: type:INT64 value:"7" from1_2: type:INT64 value:"8" ` +
 			`from2_0: type:INT64 value:"9" from2_1: type:INT64 value:"11" from2_2: type:INT64 value:"12" ` +
 			`toc_0: type:VARBINARY value:"\x00" toc_1: type:VARBINARY value:"\x00" toc_2: type:VARBINARY value:"\x00" true`,
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"5" toc: type:VARBINARY value:"\x00" false`,
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"7" toc: type:VARBINARY value:"\x00" false`,
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"8" toc: type:VARBINARY value:"\x00" false`,
+		`Execute select from1, toc from lkp2 where from1 in ::from1 and toc in ::toc ` +
+			`from1: type:TUPLE values:{type:INT64 value:"5"} values:{type:INT64 value:"7"} values:{type:INT64 value:"8"} ` +
+			`toc: type:TUPLE values:{type:VARBINARY value:"\x00"} values:{type:VARBINARY value:"\x00"} values:{type:VARBINARY value:"\x00"} false`,
 		`Execute insert ignore into lkp1(from, toc) values(:from_0, :toc_0), (:from_1, :toc_1) ` +
 			`from_0: type:INT64 value:"13" from_1: type:INT64 value:"16" ` +
 			`toc_0: type:VARBINARY value:"\x00" toc_1: type:VARBINARY value:"\x00" true`,
 		// row 2 is out because it failed Verify. Only two verifications from lkp1.
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"13" toc: type:VARBINARY value:"\x00" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"16" toc: type:VARBINARY value:"\x00" false`,
+		`Execute select from, toc from lkp1 where from in ::from and toc in ::toc ` +
+			`from: type:TUPLE values:{type:INT64 value:"13"} values:{type:INT64 value:"16"} ` +
+			`toc: type:TUPLE values:{type:VARBINARY value:"\x00"} values:{type:VARBINARY value:"\x00"} false`,
 		`ResolveDestinations sharded [value:"0" value:"2"] Destinations:DestinationKeyspaceID(00),DestinationKeyspaceID(00)`,
 		// Bind vars for rows 2 may be missing because they were not sent.
 		`ExecuteMultiShard ` +
@@ -1258,24 +1263,21 @@ func TestInsertShardedUnownedVerify(t *testing.T) {
 		nil,
 	)
 
-	// nonemptyResult will cause the lookup verify queries to succeed.
-	nonemptyResult := sqltypes.MakeTestResult(
-		sqltypes.MakeTestFields(
-			"c1",
-			"int64",
-		),
-		"1",
-	)
+	// verifyResult will cause the lookup verify query to succeed for all the ids.
+	ksids := []string{"\x16k@\xb4J\xbaK\xd6", "\x06\xe7\xea\"Βp\x8f", "N\xb1\x90ɢ\xfa\x16\x9c"}
+	verifyResult := func(ids ...int64) *sqltypes.Result {
+		result := &sqltypes.Result{}
+		for i, id := range ids {
+			result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarBinary(ksids[i])})
+		}
+		return result
+	}
 
 	vc := newDMLTestVCursor("-20", "20-")
 	vc.shardForKsid = []string{"20-", "-20", "20-"}
 	vc.results = []*sqltypes.Result{
-		nonemptyResult,
-		nonemptyResult,
-		nonemptyResult,
-		nonemptyResult,
-		nonemptyResult,
-		nonemptyResult,
+		verifyResult(4, 5, 6),
+		verifyResult(10, 11, 12),
 	}
 	_, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
 	if err != nil {
@@ -1284,12 +1286,12 @@ func TestInsertShardedUnownedVerify(t *testing.T) {
 	vc.ExpectLog(t, []string{
 		// Perform verification for each colvindex.
 		// Note that only first column of each colvindex is used.
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"4" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" false`,
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"5" toc: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" false`,
-		`Execute select from1 from lkp2 where from1 = :from1 and toc = :toc from1: type:INT64 value:"6" toc: type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"10" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"11" toc: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"12" toc: type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c" false`,
+		`Execute select from1, toc from lkp2 where from1 in ::from1 and toc in ::toc ` +
+			`from1: type:TUPLE values:{type:INT64 value:"4"} values:{type:INT64 value:"5"} values:{type:INT64 value:"6"} ` +
+			`toc: type:TUPLE values:{type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6"} values:{type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f"} values:{type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c"} false`,
+		`Execute select from, toc from lkp1 where from in ::from and toc in ::toc ` +
+			`from: type:TUPLE values:{type:INT64 value:"10"} values:{type:INT64 value:"11"} values:{type:INT64 value:"12"} ` +
+			`toc: type:TUPLE values:{type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6"} values:{type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f"} values:{type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c"} false`,
 		// Based on shardForKsid, values returned will be 20-, -20, 20-.
 		`ResolveDestinations sharded [value:"0" value:"1" value:"2"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(06e7ea22ce92708f),DestinationKeyspaceID(4eb190c9a2fa169c)`,
 		`ExecuteMultiShard ` +
@@ -1372,23 +1374,15 @@ func TestInsertShardedIgnoreUnownedVerify(t *testing.T) {
 		nil,
 	)
 
-	// nonemptyResult will cause the lookup verify queries to succeed.
-	nonemptyResult := sqltypes.MakeTestResult(
-		sqltypes.MakeTestFields(
-			"c1",
-			"int64",
-		),
-		"1",
-	)
-
 	vc := newDMLTestVCursor("-20", "20-")
 	vc.shardForKsid = []string{"20-", "-20"}
-	vc.results = []*sqltypes.Result{
-		nonemptyResult,
+	vc.results = []*sqltypes.Result{{
 		// fail verification of second row.
-		{},
-		nonemptyResult,
-	}
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewInt64(10), sqltypes.NewVarBinary("\x16k@\xb4J\xbaK\xd6")},
+			{sqltypes.NewInt64(12), sqltypes.NewVarBinary("N\xb1\x90ɢ\xfa\x16\x9c")},
+		},
+	}}
 	_, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{
 		"v1": sqltypes.StringBindVariable("a"), "v2": sqltypes.StringBindVariable("b"), "v3": sqltypes.StringBindVariable("c"),
 	}, false)
@@ -1398,9 +1392,9 @@ func TestInsertShardedIgnoreUnownedVerify(t *testing.T) {
 	vc.ExpectLog(t, []string{
 		// Perform verification for each colvindex.
 		// Note that only first column of each colvindex is used.
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"10" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"11" toc: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" false`,
-		`Execute select from from lkp1 where from = :from and toc = :toc from: type:INT64 value:"12" toc: type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c" false`,
+		`Execute select from, toc from lkp1 where from in ::from and toc in ::toc ` +
+			`from: type:TUPLE values:{type:INT64 value:"10"} values:{type:INT64 value:"11"} values:{type:INT64 value:"12"} ` +
+			`toc: type:TUPLE values:{type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6"} values:{type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f"} values:{type:VARBINARY value:"N\xb1\x90ɢ\xfa\x16\x9c"} false`,
 		// Based on shardForKsid, values returned will be 20-, -20.
 		`ResolveDestinations sharded [value:"0" value:"2"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(4eb190c9a2fa169c)`,
 		`ExecuteMultiShard ` +
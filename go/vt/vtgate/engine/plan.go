@@ -45,6 +45,9 @@ type Plan struct {
 	RowsReturned uint64 // Total number of rows
 	RowsAffected uint64 // Total number of rows
 	Errors       uint64 // Total number of errors
+
+	ProjectionEvalRows uint64 // Total number of rows evaluated by instrumented projections
+	ProjectionEvalTime uint64 // Total evaluation time of instrumented projections
 }
 
 // AddStats updates the plan execution statistics
@@ -57,6 +60,13 @@ func (p *Plan) AddStats(execCount uint64, execTime time.Duration, shardQueries,
 	atomic.AddUint64(&p.Errors, errors)
 }
 
+// AddProjectionEvalStats updates the evaluation statistics of the instrumented
+// projections of the plan
+func (p *Plan) AddProjectionEvalStats(rows uint64, evalTime time.Duration) {
+	atomic.AddUint64(&p.ProjectionEvalRows, rows)
+	atomic.AddUint64(&p.ProjectionEvalTime, uint64(evalTime))
+}
+
 // Stats returns a copy of the plan execution statistics
 func (p *Plan) Stats() (execCount uint64, execTime time.Duration, shardQueries, rowsAffected, rowsReturned, errors uint64) {
 	execCount = atomic.LoadUint64(&p.ExecCount)
@@ -87,6 +97,9 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned uint64                `json:",omitempty"`
 		Errors       uint64                `json:",omitempty"`
 		TablesUsed   []string              `json:",omitempty"`
+
+		ProjectionEvalRows uint64        `json:",omitempty"`
+		ProjectionEvalTime time.Duration `json:",omitempty"`
 	}{
 		QueryType:    p.Type.String(),
 		Original:     p.Original,
@@ -98,6 +111,9 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsReturned: atomic.LoadUint64(&p.RowsReturned),
 		Errors:       atomic.LoadUint64(&p.Errors),
 		TablesUsed:   p.TablesUsed,
+
+		ProjectionEvalRows: atomic.LoadUint64(&p.ProjectionEvalRows),
+		ProjectionEvalTime: time.Duration(atomic.LoadUint64(&p.ProjectionEvalTime)),
 	}
 
 	b := new(bytes.Buffer)
@@ -57,6 +57,7 @@ type (
 		ExceedsMaxMemoryRows(numRows int) bool
 
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
+		ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
 		// Execute the given primitive
@@ -94,8 +95,13 @@ type (
 
 		ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error)
 
+		InTransaction() bool
 		InTransactionAndIsDML() bool
 
+		// RecordProjectionEval adds the rows evaluated by an instrumented
+		// Projection, and the time the evaluation took, to the stats of the query.
+		RecordProjectionEval(rows int, evalTime time.Duration)
+
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 
 		FindRoutedTable(tablename sqlparser.TableName) (*vindexes.Table, error)
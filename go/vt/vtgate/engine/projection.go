@@ -19,10 +19,13 @@ package engine
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -30,6 +33,30 @@ import (
 
 var _ Primitive = (*Projection)(nil)
 
+var (
+	projectionStatsEnabled atomic.Bool
+
+	projectionEvalRows = stats.NewCounter("ProjectionEvalRows", "Number of rows evaluated by instrumented vtgate projections")
+	projectionEvalTime = stats.NewCounterDuration("ProjectionEvalTime", "Time spent evaluating expressions in instrumented vtgate projections")
+)
+
+// SetProjectionStatsEnabled turns the evaluation instrumentation of newly planned
+// projections on or off. It is set from the --enable-projection-stats vtgate flag.
+// Instrumented projections record their evaluation in their Stats, in the stats
+// of the query through VCursor.RecordProjectionEval, and in the process-wide
+// ProjectionEvalRows and ProjectionEvalTime counters.
+// The instrumentation is decided when a projection is planned, so the plans already
+// in the plan cache keep their setting: the plan cache has to be cleared for a change
+// to apply to queries that have been planned before.
+func SetProjectionStatsEnabled(enabled bool) {
+	projectionStatsEnabled.Store(enabled)
+}
+
+// ProjectionStatsEnabled returns true if newly planned projections should be instrumented.
+func ProjectionStatsEnabled() bool {
+	return projectionStatsEnabled.Load()
+}
+
 // Projection can evaluate expressions and project the results
 type Projection struct {
 	noTxNeeded
@@ -37,6 +64,51 @@ type Projection struct {
 	Cols  []string
 	Exprs []evalengine.Expr
 	Input Primitive
+
+	// Stats records evaluation statistics for this projection.
+	// It is nil unless instrumentation has been enabled during planning.
+	Stats *ProjectionStats
+}
+
+// ProjectionStats keeps track of how many rows each evalengine column of a
+// Projection has evaluated, and how long the evaluation took in total.
+type ProjectionStats struct {
+	rows     []atomic.Int64
+	evalCols []int
+	evalTime atomic.Int64
+}
+
+// NewProjectionStats returns a ProjectionStats for a projection with numCols columns,
+// where the columns at the evalCols offsets are evaluated by the evalengine.
+func NewProjectionStats(numCols int, evalCols []int) *ProjectionStats {
+	return &ProjectionStats{
+		rows:     make([]atomic.Int64, numCols),
+		evalCols: evalCols,
+	}
+}
+
+// RowsEvaluated returns the number of rows the column at the given offset has evaluated.
+func (ps *ProjectionStats) RowsEvaluated(col int) int64 {
+	if col < 0 || col >= len(ps.rows) {
+		return 0
+	}
+	return ps.rows[col].Load()
+}
+
+// EvalTime returns the total time spent evaluating the expressions of the projection.
+func (ps *ProjectionStats) EvalTime() time.Duration {
+	return time.Duration(ps.evalTime.Load())
+}
+
+func (ps *ProjectionStats) record(vcursor VCursor, rows int, start time.Time) {
+	elapsed := time.Since(start)
+	for _, col := range ps.evalCols {
+		ps.rows[col].Add(int64(rows))
+	}
+	ps.evalTime.Add(int64(elapsed))
+	projectionEvalRows.Add(int64(rows))
+	projectionEvalTime.Add(elapsed)
+	vcursor.RecordProjectionEval(rows, elapsed)
 }
 
 // RouteType implements the Primitive interface
@@ -62,6 +134,10 @@ func (p *Projection) TryExecute(ctx context.Context, vcursor VCursor, bindVars m
 	}
 
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	var start time.Time
+	if p.Stats != nil {
+		start = time.Now()
+	}
 	var resultRows []sqltypes.Row
 	for _, row := range result.Rows {
 		resultRow := make(sqltypes.Row, 0, len(p.Exprs))
@@ -75,6 +151,9 @@ func (p *Projection) TryExecute(ctx context.Context, vcursor VCursor, bindVars m
 		}
 		resultRows = append(resultRows, resultRow)
 	}
+	if p.Stats != nil {
+		p.Stats.record(vcursor, len(resultRows), start)
+	}
 	if wantfields {
 		result.Fields, err = p.evalFields(env, result.Fields, vcursor.ConnCollation())
 		if err != nil {
@@ -111,6 +190,10 @@ func (p *Projection) TryStreamExecute(ctx context.Context, vcursor VCursor, bind
 		if err != nil {
 			return err
 		}
+		var start time.Time
+		if p.Stats != nil {
+			start = time.Now()
+		}
 		resultRows := make([]sqltypes.Row, 0, len(qr.Rows))
 		for _, r := range qr.Rows {
 			resultRow := make(sqltypes.Row, 0, len(p.Exprs))
@@ -124,6 +207,9 @@ func (p *Projection) TryStreamExecute(ctx context.Context, vcursor VCursor, bind
 			}
 			resultRows = append(resultRows, resultRow)
 		}
+		if p.Stats != nil {
+			p.Stats.record(vcursor, len(resultRows), start)
+		}
 		qr.Rows = resultRows
 		return callback(qr)
 	})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -122,6 +123,72 @@ func TestProjectionStreaming(t *testing.T) {
 		qr.Rows))
 }
 
+// projectionEvalVCursor records the projection evaluation stats of the query.
+type projectionEvalVCursor struct {
+	noopVCursor
+	rows     int
+	evalTime time.Duration
+}
+
+func (vc *projectionEvalVCursor) RecordProjectionEval(rows int, evalTime time.Duration) {
+	vc.rows += rows
+	vc.evalTime += evalTime
+}
+
+func TestProjectionStats(t *testing.T) {
+	expr := &sqlparser.BinaryExpr{
+		Operator: sqlparser.MultOp,
+		Left:     &sqlparser.Offset{V: 0},
+		Right:    &sqlparser.Offset{V: 1},
+	}
+	evalExpr, err := evalengine.Translate(expr, &evalengine.Config{
+		Environment: vtenv.NewTestEnv(),
+		Collation:   collations.MySQL8().DefaultConnectionCharset(),
+	})
+	require.NoError(t, err)
+	colExpr := evalengine.NewColumn(0, evalengine.NewType(sqltypes.Uint64, collations.CollationBinaryID), nil)
+	newInput := func() *fakePrimitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("a|b", "uint64|uint64"),
+				"3|2",
+				"1|0",
+				"1|2",
+			)},
+		}
+	}
+	proj := &Projection{
+		Cols:  []string{"a", "apa"},
+		Exprs: []evalengine.Expr{colExpr, evalExpr},
+		Input: newInput(),
+	}
+
+	// without stats, nothing is recorded
+	vc := &projectionEvalVCursor{}
+	_, err = proj.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.Nil(t, proj.Stats)
+	assert.Zero(t, vc.rows)
+
+	proj.Stats = NewProjectionStats(2, []int{1})
+	proj.Input = newInput()
+	_, err = proj.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, proj.Stats.RowsEvaluated(0))
+	assert.EqualValues(t, 3, proj.Stats.RowsEvaluated(1))
+	assert.Positive(t, proj.Stats.EvalTime())
+	// the stats of the query are recorded too
+	assert.Equal(t, 3, vc.rows)
+	assert.Equal(t, proj.Stats.EvalTime(), vc.evalTime)
+
+	proj.Input = newInput()
+	vc = &projectionEvalVCursor{}
+	_, err = wrapStreamExecute(proj, vc, nil, true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, proj.Stats.RowsEvaluated(1))
+	assert.Equal(t, 3, vc.rows)
+}
+
 func TestEmptyInput(t *testing.T) {
 	expr := &sqlparser.BinaryExpr{
 		Operator: sqlparser.MultOp,
@@ -44,7 +44,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/logstats"
-	_ "vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
@@ -1255,6 +1255,66 @@ func TestSelectEqual(t *testing.T) {
 	utils.MustMatch(t, wantQueries, sbclookup.Queries)
 }
 
+func TestSelectLookupReadTarget(t *testing.T) {
+	var primary, replica *sandboxconn.SandboxConn
+	executor, ctx := createExecutorEnvCallback(t, func(shard, ks string, tabletType topodatapb.TabletType, conn *sandboxconn.SandboxConn) {
+		if ks != KsTestUnsharded {
+			return
+		}
+		switch tabletType {
+		case topodatapb.TabletType_PRIMARY:
+			primary = conn
+		case topodatapb.TabletType_REPLICA:
+			replica = conn
+		}
+	})
+	vindex, err := vindexes.CreateVindex("lookup_hash", "name_user_map", map[string]string{
+		"table":       "name_user_map",
+		"from":        "name",
+		"to":          "user_id",
+		"read_target": "replica",
+	})
+	require.NoError(t, err)
+	ks := executor.vschema.Keyspaces[KsTestSharded]
+	ks.Vindexes["name_user_map"] = vindex
+	for _, table := range ks.Tables {
+		for _, cv := range table.ColumnVindexes {
+			if cv.Name == "name_user_map" {
+				cv.Vindex = vindex
+			}
+		}
+	}
+	replica.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("b|a", "varbinary|varbinary"),
+		"foo|1",
+	)})
+	vars, err := sqltypes.BuildBindVariable([]any{sqltypes.NewVarChar("foo")})
+	require.NoError(t, err)
+	wantQueries := []*querypb.BoundQuery{{
+		Sql: "select `name`, user_id from name_user_map where `name` in ::name",
+		BindVariables: map[string]*querypb.BindVariable{
+			"name": vars,
+		},
+	}}
+
+	// the lookup reads from the replicas, while the session targets the primary.
+	_, err = executorExec(ctx, executor, &vtgatepb.Session{TargetString: "@primary", Autocommit: true}, "select id from user where name = 'foo'", nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, wantQueries, replica.Queries)
+	assert.Empty(t, primary.Queries)
+
+	// in a transaction, the lookup reads from the session target, so that it
+	// sees the lookup rows written by the transaction.
+	replica.Queries = nil
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	_, err = executorExec(ctx, executor, session, "begin", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select id from user where name = 'foo'", nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, wantQueries, primary.Queries)
+	assert.Empty(t, replica.Queries)
+}
+
 func TestSelectINFromOR(t *testing.T) {
 	executor, sbc1, _, _, ctx := createExecutorEnv(t)
 	executor.pv = querypb.ExecuteOptions_Gen4
@@ -52,6 +52,12 @@ type LogStats struct {
 	SessionUUID    string
 	CachedPlan     bool
 	ActiveKeyspace string // ActiveKeyspace is the selected keyspace `use ks`
+
+	// ProjectionEvalRows and ProjectionEvalTime are the rows evaluated by the
+	// instrumented vtgate projections of the query, and the time it took.
+	// They are only recorded with --enable-projection-stats.
+	ProjectionEvalRows uint64
+	ProjectionEvalTime time.Duration
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -177,6 +183,10 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Strings(stats.TablesUsed)
 	log.Key("ActiveKeyspace")
 	log.String(stats.ActiveKeyspace)
+	log.Key("ProjectionEvalRows")
+	log.Uint(stats.ProjectionEvalRows)
+	log.Key("ProjectionEvalTime")
+	log.Duration(stats.ProjectionEvalTime)
 
 	return log.Flush(w)
 }
@@ -31,7 +31,7 @@ func buildPlanForBypass(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vsc
 		return nil, err
 	}
 	switch dest := vschema.Destination().(type) {
-	case key.DestinationExactKeyRange:
+	case key.DestinationExactKeyRange, key.DestinationExactKeyRanges:
 		if _, ok := stmt.(*sqlparser.Insert); ok {
 			return nil, vterrors.VT03023(vschema.TargetString())
 		}
@@ -407,20 +407,28 @@ func transformProjection(ctx *plancontext.PlanningContext, op *operators.Project
 
 	var evalengineExprs []evalengine.Expr
 	var columnNames []string
-	for _, pe := range ap {
+	var evalCols []int
+	for i, pe := range ap {
 		ee, err := getEvalEngineExpr(ctx, pe)
 		if err != nil {
 			return nil, err
 		}
+		if _, isEval := pe.Info.(*operators.EvalEngine); isEval {
+			evalCols = append(evalCols, i)
+		}
 		evalengineExprs = append(evalengineExprs, ee)
 		columnNames = append(columnNames, pe.Original.ColumnName())
 	}
 
-	return &engine.Projection{
+	proj := &engine.Projection{
 		Input: src,
 		Cols:  columnNames,
 		Exprs: evalengineExprs,
-	}, nil
+	}
+	if engine.ProjectionStatsEnabled() {
+		proj.Stats = engine.NewProjectionStats(len(evalengineExprs), evalCols)
+	}
+	return proj, nil
 }
 
 // offsetInInputOrder returns true if the columns are in the same order as the input
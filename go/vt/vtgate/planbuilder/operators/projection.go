@@ -208,6 +208,46 @@ func createSimpleProjection(ctx *plancontext.PlanningContext, selExprs []sqlpars
 	return p
 }
 
+// mergeSimpleProjection merges a simple projection sitting on top of this projection
+// into it. The offsets of the simple projection are composed with the columns of this
+// projection, and the names it uses for its output are applied to the merged columns.
+// Returns false and leaves this projection untouched if the offsets can't be composed.
+func (p *Projection) mergeSimpleProjection(simple *Projection) bool {
+	if p.isDerived() || simple.Source != p {
+		return false
+	}
+	ap, err := p.GetAliasedProjections()
+	if err != nil {
+		return false
+	}
+	simpleAp, err := simple.GetAliasedProjections()
+	if err != nil {
+		return false
+	}
+
+	newColumns := make(AliasedProjections, 0, len(simpleAp))
+	for _, pe := range simpleAp {
+		offset, ok := pe.Info.(Offset)
+		if !ok || int(offset) < 0 || int(offset) >= len(ap) {
+			return false
+		}
+		inner := ap[offset]
+		if inner.Info == nil {
+			// the column has not been planned yet, so we don't know how to evaluate it
+			return false
+		}
+		newColumns = append(newColumns, &ProjExpr{
+			Original: pe.Original,
+			EvalExpr: inner.EvalExpr,
+			ColExpr:  inner.ColExpr,
+			Info:     inner.Info,
+		})
+	}
+
+	p.Columns = newColumns
+	return true
+}
+
 // canPush returns false if the projection has subquery expressions in it and the subqueries have not yet
 // been settled. Once they have settled, we know where to push the projection, but if we push too early
 // the projection can end up in the wrong branch of joins
@@ -388,7 +428,7 @@ func (p *Projection) addColumn(
 	}
 
 	pe := newProjExprWithInner(ae, expr)
-	if !push {
+	if !push || isNullColumn(expr) || (ctx.VSchema.PreferVTGateEvaluation() && p.canEvaluateOnVTGate(ctx, expr)) {
 		return p.addProjExpr(pe)
 	}
 
@@ -399,6 +439,49 @@ func (p *Projection) addColumn(
 	return p.addProjExpr(pe)
 }
 
+// isNullColumn returns true for a NULL literal. It does not depend on anything our input produces,
+// so it is evaluated by the projection itself instead of being added as a spurious column to the input.
+// Other literals are still pushed down, since they let the projection be merged into a route.
+func isNullColumn(expr sqlparser.Expr) bool {
+	_, ok := expr.(*sqlparser.NullVal)
+	return ok
+}
+
+// canEvaluateOnVTGate returns true if the expression only depends on columns that our input is already
+// fetching, and can be evaluated by the evalengine. In that case, it is cheaper to evaluate the expression
+// on the vtgate than to add it as an extra column to our input.
+func (p *Projection) canEvaluateOnVTGate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) bool {
+	if p.isDerived() {
+		return false
+	}
+	switch expr.(type) {
+	case *sqlparser.ColName, *sqlparser.WeightStringFuncExpr:
+		// plain columns have to be fetched anyway, and weight strings
+		// need to be calculated by mysql
+		return false
+	}
+	if sqlparser.ContainsAggregation(expr) {
+		return false
+	}
+
+	columns := 0
+	_, err := evalengine.Translate(expr, &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			offset := p.Source.FindCol(ctx, col, false)
+			if offset < 0 {
+				return 0, vterrors.VT13001(fmt.Sprintf("column %s is not fetched by the input", sqlparser.String(col)))
+			}
+			columns++
+			return offset, nil
+		},
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	})
+	// expressions that do not use any columns are just as cheap to evaluate on mysql
+	return err == nil && columns > 0
+}
+
 func (po Offset) expr()             {}
 func (po *EvalEngine) expr()        {}
 func (po SubQueryExpression) expr() {}
@@ -856,7 +856,11 @@ func addTruncationOrProjectionToReturnOutput(ctx *plancontext.PlanningContext, s
 		return output
 	}
 
-	return createSimpleProjection(ctx, selExprs, output)
+	proj := createSimpleProjection(ctx, selExprs, output)
+	if src, ok := output.(*Projection); ok && src.mergeSimpleProjection(proj) {
+		return src
+	}
+	return proj
 }
 
 func colNamesAlign(expected, actual sqlparser.SelectExprs) bool {
@@ -438,6 +438,21 @@ func (s *planTestSuite) TestTPCH() {
 	s.testFile("tpch_cases.json", vschemaWrapper, false)
 }
 
+// TestPreferVTGateEvaluation tests the planning when expressions that only depend on already
+// fetched columns are evaluated on the vtgate instead of being added as columns to the route.
+func (s *planTestSuite) TestPreferVTGateEvaluation() {
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:                loadSchema(s.T(), "vschemas/schema.json", true),
+		TabletType_:      topodatapb.TabletType_PRIMARY,
+		SysVarEnabled:    true,
+		PreferVTGateEval: true,
+		TestBuilder:      TestBuilder,
+		Env:              vtenv.NewTestEnv(),
+	}
+
+	s.testFile("vtgate_evaluation_cases.json", vschemaWrapper, false)
+}
+
 func BenchmarkOLTP(b *testing.B) {
 	benchmarkWorkload(b, "oltp")
 }
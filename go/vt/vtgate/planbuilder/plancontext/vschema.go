@@ -79,6 +79,10 @@ type VSchema interface {
 	// IsViewsEnabled returns true if Vitess manages the views.
 	IsViewsEnabled() bool
 
+	// PreferVTGateEvaluation returns true if the planner should evaluate expressions on the vtgate
+	// when the columns they depend on are already fetched, instead of fetching them from the route.
+	PreferVTGateEvaluation() bool
+
 	// GetUDV returns user defined value from the variable passed.
 	GetUDV(name string) *querypb.BindVariable
 
@@ -18,6 +18,7 @@ package planbuilder
 
 import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -38,6 +39,11 @@ func WireupRoute(ctx *plancontext.PlanningContext, eroute *engine.Route, sel sql
 	if !ok {
 		return eroute, nil
 	}
+	// a planned lookup query runs with the session target, so lookups that
+	// read from their own tablet type are left to the Map of the vindex.
+	if readTarget, ok := planableVindex.(vindexes.LookupReadTarget); ok && readTarget.ReadTabletType() != topodatapb.TabletType_UNKNOWN {
+		return eroute, nil
+	}
 
 	query, args := planableVindex.Query()
 	stmt, reserved, err := ctx.VSchema.Environment().Parser().Parse2(query)
@@ -210,6 +210,14 @@ func (session *SafeSession) SetQueryTimeout(queryTimeout int64) {
 	session.QueryTimeout = queryTimeout
 }
 
+// SetBufferingDelay records how long the last query was buffered during a
+// failover, so that clients can tell a failover from a slow query.
+func (session *SafeSession) SetBufferingDelay(delay time.Duration) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.BufferingDelayMs = delay.Milliseconds()
+}
+
 // GetQueryTimeout gets the query timeout
 func (session *SafeSession) GetQueryTimeout() int64 {
 	session.mu.Lock()
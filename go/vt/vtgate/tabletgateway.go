@@ -306,7 +306,12 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 			break
 		}
 
-		gw.shuffleTablets(gw.localCell, tablets)
+		// prefer the tablets of the cell named in the target, if any.
+		cell := gw.localCell
+		if target.Cell != "" {
+			cell = target.Cell
+		}
+		gw.shuffleTablets(cell, tablets)
 
 		var th *discovery.TabletHealth
 		// skip tablets we tried before
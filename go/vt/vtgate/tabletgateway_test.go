@@ -152,6 +152,33 @@ func TestTabletGatewayShuffleTablets(t *testing.T) {
 	}
 }
 
+func TestTabletGatewayTargetCell(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &fakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell1")
+	defer tg.Close(ctx)
+
+	sbcCell1 := hc.AddTestTablet("cell1", "1.1.1.1", 1001, "ks", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+	sbcCell2 := hc.AddTestTablet("cell2", "1.1.1.2", 1001, "ks", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+
+	// the tablets of the cell of the target are preferred over the local ones.
+	target := &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: topodatapb.TabletType_REPLICA, Cell: "cell2"}
+	for i := 0; i < 10; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 0, sbcCell1.ExecCount.Load())
+	assert.EqualValues(t, 10, sbcCell2.ExecCount.Load())
+
+	// other cells are used if the cell of the target has no healthy tablet.
+	target.Cell = "cell3"
+	_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, sbcCell1.ExecCount.Load()+sbcCell2.ExecCount.Load())
+}
+
 func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
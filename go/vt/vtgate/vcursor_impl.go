@@ -97,10 +97,13 @@ type VSchemaOperator interface {
 // vcursorImpl implements the VCursor functionality used by dependent
 // packages to call back into VTGate.
 type vcursorImpl struct {
-	safeSession    *SafeSession
-	keyspace       string
-	tabletType     topodatapb.TabletType
-	destination    key.Destination
+	safeSession *SafeSession
+	keyspace    string
+	tabletType  topodatapb.TabletType
+	destination key.Destination
+	// cell is the cell whose tablets the queries should prefer, as named in
+	// the target string, or empty to prefer the local cell.
+	cell           string
 	marginComments sqlparser.MarginComments
 	executor       iExecute
 	resolver       *srvtopo.Resolver
@@ -146,6 +149,7 @@ func newVCursorImpl(
 	if err != nil {
 		return nil, err
 	}
+	_, cell := topoprotopb.SplitTargetCell(safeSession.TargetString)
 
 	var ts *topo.Server
 	// We don't have access to the underlying TopoServer if this vtgate is
@@ -179,6 +183,7 @@ func newVCursorImpl(
 		keyspace:            keyspace,
 		tabletType:          tabletType,
 		destination:         destination,
+		cell:                cell,
 		marginComments:      marginComments,
 		executor:            executor,
 		logStats:            logStats,
@@ -549,6 +554,23 @@ func (vc *vcursorImpl) Execute(ctx context.Context, method string, query string,
 	return qr, err
 }
 
+// ExecuteOnTabletType executes the query in a new autocommit session whose target
+// is the one of the current session, but on the given tablet type.
+func (vc *vcursorImpl) ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error) {
+	keyspace, _, dest, err := topoprotopb.ParseDestination(vc.safeSession.TargetString, vc.tabletType)
+	if err != nil {
+		return nil, err
+	}
+	target, err := topoprotopb.DestinationToString(keyspace, tabletType, dest)
+	if err != nil {
+		return nil, err
+	}
+	session := NewAutocommitSession(vc.safeSession.Session)
+	session.logging = vc.safeSession.logging
+	session.TargetString = target
+	return vc.executor.Execute(ctx, nil, method, session, vc.marginComments.Leading+query+vc.marginComments.Trailing, bindVars)
+}
+
 // markSavepoint opens an internal savepoint before executing the original query.
 // This happens only when rollback is allowed and no other savepoint was executed
 // and the query is executed in an explicit transaction (i.e. started by the client).
@@ -646,6 +668,12 @@ func (vc *vcursorImpl) ExecuteKeyspaceID(ctx context.Context, keyspace string, k
 	return qr, vterrors.Aggregate(errs)
 }
 
+// RecordProjectionEval is part of the engine.VCursor interface.
+func (vc *vcursorImpl) RecordProjectionEval(rows int, evalTime time.Duration) {
+	atomic.AddUint64(&vc.logStats.ProjectionEvalRows, uint64(rows))
+	atomic.AddInt64((*int64)(&vc.logStats.ProjectionEvalTime), int64(evalTime))
+}
+
 func (vc *vcursorImpl) InTransactionAndIsDML() bool {
 	if !vc.safeSession.InTransaction() {
 		return false
@@ -710,6 +738,7 @@ func (vc *vcursorImpl) ResolveDestinations(ctx context.Context, keyspace string,
 			return nil, nil, err
 		}
 	}
+	vc.setTargetCell(rss)
 	return rss, values, err
 }
 
@@ -724,9 +753,21 @@ func (vc *vcursorImpl) ResolveDestinationsMultiCol(ctx context.Context, keyspace
 			return nil, nil, err
 		}
 	}
+	vc.setTargetCell(rss)
 	return rss, values, err
 }
 
+// setTargetCell sets the cell of the resolved shards to the one named in the
+// target string, so that the gateway prefers its tablets.
+func (vc *vcursorImpl) setTargetCell(rss []*srvtopo.ResolvedShard) {
+	if vc.cell == "" {
+		return
+	}
+	for _, rs := range rss {
+		rs.Target.Cell = vc.cell
+	}
+}
+
 func (vc *vcursorImpl) Session() engine.SessionActions {
 	return vc
 }
@@ -1296,6 +1337,10 @@ func (vc *vcursorImpl) IsViewsEnabled() bool {
 	return enableViews
 }
 
+func (vc *vcursorImpl) PreferVTGateEvaluation() bool {
+	return preferVTGateEvaluation
+}
+
 func (vc *vcursorImpl) GetUDV(name string) *querypb.BindVariable {
 	return vc.safeSession.GetUDV(name)
 }
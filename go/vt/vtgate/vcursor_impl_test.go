@@ -259,6 +259,30 @@ func TestSetTarget(t *testing.T) {
 	}
 }
 
+func TestVCursorTargetCell(t *testing.T) {
+	r, _, _, _, ctx := createExecutorEnv(t)
+	for _, tc := range []struct {
+		targetString string
+		cell         string
+	}{{
+		targetString: KsTestSharded + "@replica|cell2",
+		cell:         "cell2",
+	}, {
+		targetString: KsTestSharded + "@replica",
+	}} {
+		t.Run(tc.targetString, func(t *testing.T) {
+			vc, err := newVCursorImpl(NewSafeSession(&vtgatepb.Session{TargetString: tc.targetString}), sqlparser.MarginComments{}, r, nil, r.vm, r.VSchema(), r.resolver.resolver, nil, false, querypb.ExecuteOptions_Gen4)
+			require.NoError(t, err)
+			rss, _, err := vc.ResolveDestinations(ctx, KsTestSharded, nil, []key.Destination{key.DestinationAllShards{}})
+			require.NoError(t, err)
+			require.NotEmpty(t, rss)
+			for _, rs := range rss {
+				require.Equal(t, tc.cell, rs.Target.Cell)
+			}
+		})
+	}
+}
+
 func TestKeyForPlan(t *testing.T) {
 	type testCase struct {
 		vschema               *vindexes.VSchema
@@ -33,6 +33,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 const (
@@ -294,6 +295,12 @@ type clCommon struct {
 // newCLCommon is commone code for the consistent lookup vindexes.
 func newCLCommon(name string, m map[string]string) (*clCommon, error) {
 	lu := &clCommon{name: name}
+	// consistent lookups read their lookup table in the transactions that
+	// keep it consistent with the owner table, so they cannot read it from
+	// another tablet type.
+	if _, ok := m[lookupInternalParamReadTarget]; ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s is not supported by consistent lookup vindexes", lookupInternalParamReadTarget)
+	}
 	var err error
 	lu.writeOnly, err = boolFromMap(m, consistentLookupParamWriteOnly)
 	if err != nil {
@@ -113,6 +113,18 @@ func TestConsistentLookupInit(t *testing.T) {
 	}
 }
 
+func TestConsistentLookupReadTarget(t *testing.T) {
+	for _, vindexType := range []string{"consistent_lookup", "consistent_lookup_unique"} {
+		_, err := CreateVindex(vindexType, vindexType, map[string]string{
+			"table":       "t",
+			"from":        "fromc",
+			"to":          "toc",
+			"read_target": "replica",
+		})
+		require.EqualError(t, err, "read_target is not supported by consistent lookup vindexes")
+	}
+}
+
 func TestConsistentLookupMap(t *testing.T) {
 	lookup := createConsistentLookup(t, "consistent_lookup", false)
 	vc := &loggingVCursor{}
@@ -556,6 +568,10 @@ func (vc *loggingVCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	return vtgatepb.CommitOrder_PRE
 }
 
+func (vc *loggingVCursor) InTransaction() bool {
+	return false
+}
+
 func (vc *loggingVCursor) InTransactionAndIsDML() bool {
 	return false
 }
@@ -597,6 +613,10 @@ func (vc *loggingVCursor) ExecuteKeyspaceID(ctx context.Context, keyspace string
 	return vc.execute(ctx, "ExecuteKeyspaceID", query, bindVars, rollbackOnError)
 }
 
+func (vc *loggingVCursor) ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error) {
+	return vc.execute(ctx, "ExecuteOnTabletType"+tabletType.String(), query, bindVars, false)
+}
+
 func (vc *loggingVCursor) execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool) (*sqltypes.Result, error) {
 	if vc.index >= len(vc.results) {
 		return nil, fmt.Errorf("ran out of results to return: %s", query)
@@ -17,38 +17,111 @@ limitations under the License.
 package vindexes
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 const (
 	lookupParamNoVerify  = "no_verify"
 	lookupParamWriteOnly = "write_only"
+
+	lookupUniqueParamOnDuplicate = "on_duplicate"
+
+	onDuplicateError = "error"
+	onDuplicateFirst = "first"
 )
 
 var (
-	_ SingleColumn    = (*LookupUnique)(nil)
-	_ Lookup          = (*LookupUnique)(nil)
-	_ LookupPlanable  = (*LookupUnique)(nil)
-	_ ParamValidating = (*LookupUnique)(nil)
-	_ SingleColumn    = (*LookupNonUnique)(nil)
-	_ Lookup          = (*LookupNonUnique)(nil)
-	_ LookupPlanable  = (*LookupNonUnique)(nil)
-	_ ParamValidating = (*LookupNonUnique)(nil)
+	_ SingleColumn     = (*LookupUnique)(nil)
+	_ Lookup           = (*LookupUnique)(nil)
+	_ LookupPlanable   = (*LookupUnique)(nil)
+	_ ParamValidating  = (*LookupUnique)(nil)
+	_ LookupReadTarget = (*LookupUnique)(nil)
+	_ SingleColumn     = (*LookupNonUnique)(nil)
+	_ Lookup           = (*LookupNonUnique)(nil)
+	_ LookupPlanable   = (*LookupNonUnique)(nil)
+	_ ParamValidating  = (*LookupNonUnique)(nil)
+	_ LookupReadTarget = (*LookupNonUnique)(nil)
 
 	lookupParams = append(
 		append(make([]string, 0), lookupCommonParams...),
 		lookupParamNoVerify,
 		lookupParamWriteOnly,
 	)
+
+	lookupUniqueParams = append(
+		append(make([]string, 0), lookupParams...),
+		lookupUniqueParamOnDuplicate,
+	)
+
+	lookupCalls = stats.NewCountersWithMultiLabels(
+		"VindexLookupCalls",
+		"Map and Verify calls, including the lookups of planned queries, that read the table of lookup vindexes by vindex and operation",
+		[]string{"Vindex", "Operation"})
+	lookupRows = stats.NewCountersWithMultiLabels(
+		"VindexLookupRows",
+		"Ids looked up in the table of lookup vindexes by vindex and whether a row was found or missing",
+		[]string{"Vindex", "Result"})
+	lookupDuplicates = stats.NewCountersWithSingleLabel(
+		"VindexLookupDuplicates",
+		"Ids that have more than one row in the table of lookup_unique vindexes with on_duplicate=first, by vindex",
+		"Vindex")
+
+	// logDuplicates is throttled, as duplicates are found on the Map path of
+	// every query that reads them: VindexLookupDuplicates counts all of them.
+	logDuplicates = logutil.NewThrottledLogger("VindexLookupDuplicates", 1*time.Minute)
 )
 
+// recordLookupMap updates the lookup stats of the vindex for the lookup of a
+// Map call, or of a planned query, whose results are given to MapResult.
+func recordLookupMap(name string, destinations []key.Destination) {
+	lookupCalls.Add([]string{name, "Map"}, 1)
+	var found, missing int64
+	for _, dest := range destinations {
+		if _, none := dest.(key.DestinationNone); none {
+			missing++
+		} else {
+			found++
+		}
+	}
+	recordLookupRows(name, found, missing)
+}
+
+// recordLookupVerify updates the lookup stats of the vindex for a Verify call.
+func recordLookupVerify(name string, verified []bool) {
+	lookupCalls.Add([]string{name, "Verify"}, 1)
+	var found, missing int64
+	for _, ok := range verified {
+		if ok {
+			found++
+		} else {
+			missing++
+		}
+	}
+	recordLookupRows(name, found, missing)
+}
+
+func recordLookupRows(name string, found, missing int64) {
+	if found > 0 {
+		lookupRows.Add([]string{name, "Found"}, found)
+	}
+	if missing > 0 {
+		lookupRows.Add([]string{name, "Missing"}, missing)
+	}
+}
+
 func init() {
 	Register("lookup", newLookup)
 	Register("lookup_unique", newLookupUnique)
@@ -122,7 +195,9 @@ func (ln *LookupNonUnique) Map(ctx context.Context, vcursor VCursor, ids []sqlty
 	return ln.MapResult(ids, results)
 }
 
-// MapResult implements the LookupPlanable interface
+// MapResult implements the LookupPlanable interface. It is where the lookup
+// stats of the vindex are recorded, since planned queries read the lookup
+// table themselves and only call MapResult.
 func (ln *LookupNonUnique) MapResult(ids []sqltypes.Value, results []*sqltypes.Result) ([]key.Destination, error) {
 	out := make([]key.Destination, 0, len(ids))
 	if ln.writeOnly {
@@ -146,6 +221,7 @@ func (ln *LookupNonUnique) MapResult(ids []sqltypes.Value, results []*sqltypes.R
 		}
 		out = append(out, key.DestinationKeyspaceIDs(ksids))
 	}
+	recordLookupMap(ln.name, out)
 	return out, nil
 }
 
@@ -158,7 +234,12 @@ func (ln *LookupNonUnique) Verify(ctx context.Context, vcursor VCursor, ids []sq
 		}
 		return out, nil
 	}
-	return ln.lkp.Verify(ctx, vcursor, ids, ksidsToValues(ksids))
+	out, err := ln.lkp.VerifyBatch(ctx, vcursor, ids, ksidsToValues(ksids))
+	if err != nil {
+		return nil, err
+	}
+	recordLookupVerify(ln.name, out)
+	return out, nil
 }
 
 // Create reserves the id by inserting it into the vindex table.
@@ -191,6 +272,11 @@ func (ln *LookupNonUnique) Query() (selQuery string, arguments []string) {
 	return ln.lkp.query()
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (ln *LookupNonUnique) ReadTabletType() topodatapb.TabletType {
+	return ln.lkp.readTabletType
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (ln *LookupNonUnique) UnknownParams() []string {
 	return ln.unknownParams
@@ -208,6 +294,8 @@ func (ln *LookupNonUnique) UnknownParams() []string {
 //	autocommit: setting this to "true" will cause inserts to upsert and deletes to be ignored.
 //	write_only: in this mode, Map functions return the full keyrange causing a full scatter.
 //	no_verify: in this mode, Verify will always succeed.
+//	read_target: "primary", "replica" or "rdonly", the tablet type that Map reads the lookup table from outside of transactions.
+//	autocommit_create, autocommit_delete, autocommit_update: override autocommit for that operation only.
 func newLookup(name string, m map[string]string) (Vindex, error) {
 	lookup := &LookupNonUnique{
 		name:          name,
@@ -228,11 +316,12 @@ func newLookup(name string, m map[string]string) (Vindex, error) {
 		return nil, err
 	}
 
-	// if autocommit is on for non-unique lookup, upsert should also be on.
-	upsert := cc.autocommit || cc.multiShardAutocommit
+	// if creates are autocommitted for non-unique lookup, upsert should also be on.
+	upsert := cc.createAutocommit()
 	if err := lookup.lkp.Init(m, cc.autocommit, upsert, cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lookup.lkp.initOperationAutocommit(cc)
 	return lookup, nil
 }
 
@@ -244,6 +333,24 @@ func ksidsToValues(ksids [][]byte) []sqltypes.Value {
 	return values
 }
 
+// distinctValues returns the distinct values of ids, in the order of their
+// first occurrence, and for each of the ids its position in the distinct values.
+func distinctValues(ids []sqltypes.Value) (distinct []sqltypes.Value, positions []int) {
+	seen := make(map[string]int, len(ids))
+	distinct = make([]sqltypes.Value, 0, len(ids))
+	positions = make([]int, 0, len(ids))
+	for _, id := range ids {
+		pos, ok := seen[id.String()]
+		if !ok {
+			pos = len(distinct)
+			seen[id.String()] = pos
+			distinct = append(distinct, id)
+		}
+		positions = append(positions, pos)
+	}
+	return distinct, positions
+}
+
 //====================================================================
 
 // LookupUnique defines a vindex that uses a lookup table.
@@ -253,6 +360,7 @@ type LookupUnique struct {
 	name          string
 	writeOnly     bool
 	noVerify      bool
+	onDuplicate   string
 	lkp           lookupInternal
 	unknownParams []string
 }
@@ -280,10 +388,15 @@ func (lu *LookupUnique) AutoCommitEnabled() bool {
 //
 //	autocommit: setting this to "true" will cause deletes to be ignored.
 //	write_only: in this mode, Map functions return the full keyrange causing a full scatter.
+//	read_target: "primary", "replica" or "rdonly", the tablet type that Map reads the lookup table from outside of transactions.
+//	on_duplicate: "error" (default) to fail Map when an id has many rows in the table, or "first"
+//	  to map it to the lowest of their keyspace ids instead.
+//	autocommit_create, autocommit_delete, autocommit_update: override autocommit for that operation only.
 func newLookupUnique(name string, m map[string]string) (Vindex, error) {
 	lu := &LookupUnique{
 		name:          name,
-		unknownParams: FindUnknownParams(m, lookupParams),
+		onDuplicate:   onDuplicateError,
+		unknownParams: FindUnknownParams(m, lookupUniqueParams),
 	}
 
 	cc, err := parseCommonConfig(m)
@@ -300,10 +413,18 @@ func newLookupUnique(name string, m map[string]string) (Vindex, error) {
 		return nil, err
 	}
 
+	if onDuplicate, ok := m[lookupUniqueParamOnDuplicate]; ok {
+		if onDuplicate != onDuplicateError && onDuplicate != onDuplicateFirst {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s value must be '%s' or '%s': '%s'", lookupUniqueParamOnDuplicate, onDuplicateError, onDuplicateFirst, onDuplicate)
+		}
+		lu.onDuplicate = onDuplicate
+	}
+
 	// Don't allow upserts for unique vindexes.
 	if err := lu.lkp.Init(m, cc.autocommit, false /* upsert */, cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lu.lkp.initOperationAutocommit(cc)
 	return lu, nil
 }
 
@@ -336,13 +457,46 @@ func (lu *LookupUnique) Map(ctx context.Context, vcursor VCursor, ids []sqltypes
 		}
 		return out, nil
 	}
+	// the same id can be repeated many times, e.g. in an IN clause: look up
+	// each distinct id once and fan the results out to the original positions.
+	distinct, positions := distinctValues(ids)
+	results, err := lu.lkp.Lookup(ctx, vcursor, distinct, vtgatepb.CommitOrder_NORMAL)
+	if err != nil {
+		return nil, err
+	}
+	destinations, err := lu.MapResult(distinct, results)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]key.Destination, 0, len(ids))
+	for _, pos := range positions {
+		out = append(out, destinations[pos])
+	}
+	return out, nil
+}
+
+// MapToValues returns, for each of the ids, the values of the to column that
+// were found for it in the lookup table, e.g. to see why Map routed a query
+// where it did. The lookup table is read even if the vindex is write only.
+func (lu *LookupUnique) MapToValues(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([][]sqltypes.Value, error) {
 	results, err := lu.lkp.Lookup(ctx, vcursor, ids, vtgatepb.CommitOrder_NORMAL)
 	if err != nil {
 		return nil, err
 	}
-	return lu.MapResult(ids, results)
+	out := make([][]sqltypes.Value, 0, len(ids))
+	for _, result := range results {
+		values := make([]sqltypes.Value, 0, len(result.Rows))
+		for _, row := range result.Rows {
+			values = append(values, row[0])
+		}
+		out = append(out, values)
+	}
+	return out, nil
 }
 
+// MapResult implements the LookupPlanable interface. It is where the lookup
+// stats of the vindex are recorded, since planned queries read the lookup
+// table themselves and only call MapResult.
 func (lu *LookupUnique) MapResult(ids []sqltypes.Value, results []*sqltypes.Result) ([]key.Destination, error) {
 	out := make([]key.Destination, 0, len(ids))
 	for i, result := range results {
@@ -356,9 +510,27 @@ func (lu *LookupUnique) MapResult(ids []sqltypes.Value, results []*sqltypes.Resu
 			}
 			out = append(out, key.DestinationKeyspaceID(rowBytes))
 		default:
-			return nil, fmt.Errorf("Lookup.Map: unexpected multiple results from vindex %s: %v", lu.lkp.Table, ids[i])
+			if lu.onDuplicate != onDuplicateFirst {
+				return nil, fmt.Errorf("Lookup.Map: unexpected multiple results from vindex %s: %v", lu.lkp.Table, ids[i])
+			}
+			// pick the lowest keyspace id, so the choice does not depend on
+			// the order of the rows.
+			var first []byte
+			for j, row := range result.Rows {
+				rowBytes, err := row[0].ToBytes()
+				if err != nil {
+					return nil, err
+				}
+				if j == 0 || bytes.Compare(rowBytes, first) < 0 {
+					first = rowBytes
+				}
+			}
+			logDuplicates.Warningf("Lookup.Map: %d results from vindex %s for %v, using the lowest keyspace id", len(result.Rows), lu.lkp.Table, ids[i])
+			lookupDuplicates.Add(lu.name, 1)
+			out = append(out, key.DestinationKeyspaceID(first))
 		}
 	}
+	recordLookupMap(lu.name, out)
 	return out, nil
 }
 
@@ -371,7 +543,12 @@ func (lu *LookupUnique) Verify(ctx context.Context, vcursor VCursor, ids []sqlty
 		}
 		return out, nil
 	}
-	return lu.lkp.Verify(ctx, vcursor, ids, ksidsToValues(ksids))
+	out, err := lu.lkp.VerifyBatch(ctx, vcursor, ids, ksidsToValues(ksids))
+	if err != nil {
+		return nil, err
+	}
+	recordLookupVerify(lu.name, out)
+	return out, nil
 }
 
 // Create reserves the id by inserting it into the vindex table.
@@ -407,6 +584,11 @@ func (lu *LookupUnique) Query() (string, []string) {
 	return lu.lkp.query()
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (lu *LookupUnique) ReadTabletType() topodatapb.TabletType {
+	return lu.lkp.readTabletType
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (ln *LookupUnique) UnknownParams() []string {
 	return ln.unknownParams
@@ -33,14 +33,16 @@ const (
 )
 
 var (
-	_ SingleColumn    = (*LookupHash)(nil)
-	_ Lookup          = (*LookupHash)(nil)
-	_ LookupPlanable  = (*LookupHash)(nil)
-	_ ParamValidating = (*LookupHash)(nil)
-	_ SingleColumn    = (*LookupHashUnique)(nil)
-	_ Lookup          = (*LookupHashUnique)(nil)
-	_ LookupPlanable  = (*LookupHashUnique)(nil)
-	_ ParamValidating = (*LookupHashUnique)(nil)
+	_ SingleColumn     = (*LookupHash)(nil)
+	_ Lookup           = (*LookupHash)(nil)
+	_ LookupPlanable   = (*LookupHash)(nil)
+	_ ParamValidating  = (*LookupHash)(nil)
+	_ LookupReadTarget = (*LookupHash)(nil)
+	_ SingleColumn     = (*LookupHashUnique)(nil)
+	_ Lookup           = (*LookupHashUnique)(nil)
+	_ LookupPlanable   = (*LookupHashUnique)(nil)
+	_ ParamValidating  = (*LookupHashUnique)(nil)
+	_ LookupReadTarget = (*LookupHashUnique)(nil)
 
 	lookupHashParams = append(
 		append(make([]string, 0), lookupCommonParams...),
@@ -92,11 +94,12 @@ func newLookupHash(name string, m map[string]string) (Vindex, error) {
 		return nil, err
 	}
 
-	// if autocommit is on for non-unique lookup, upsert should also be on.
-	upsert := cc.autocommit || cc.multiShardAutocommit
+	// if creates are autocommitted for non-unique lookup, upsert should also be on.
+	upsert := cc.createAutocommit()
 	if err := lh.lkp.Init(m, cc.autocommit, upsert, cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lh.lkp.initOperationAutocommit(cc)
 	return lh, nil
 }
 
@@ -180,6 +183,11 @@ func (lh *LookupHash) Query() (selQuery string, arguments []string) {
 	return lh.lkp.query()
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (lh *LookupHash) ReadTabletType() topodatapb.TabletType {
+	return lh.lkp.readTabletType
+}
+
 // AllowBatch implements the LookupPlanable interface
 func (lh *LookupHash) AllowBatch() bool {
 	return lh.lkp.BatchLookup
@@ -306,6 +314,7 @@ func newLookupHashUnique(name string, m map[string]string) (Vindex, error) {
 	if err := lhu.lkp.Init(m, cc.autocommit, false /* upsert */, cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lhu.lkp.initOperationAutocommit(cc)
 	return lhu, nil
 }
 
@@ -438,6 +447,11 @@ func (lhu *LookupHashUnique) Query() (selQuery string, arguments []string) {
 	return lhu.lkp.query()
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (lhu *LookupHashUnique) ReadTabletType() topodatapb.TabletType {
+	return lhu.lkp.readTabletType
+}
+
 // GetCommitOrder implements the LookupPlanable interface
 func (lhu *LookupHashUnique) GetCommitOrder() vtgatepb.CommitOrder {
 	return vtgatepb.CommitOrder_NORMAL
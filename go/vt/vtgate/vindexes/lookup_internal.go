@@ -26,6 +26,7 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -39,6 +40,9 @@ const (
 
 	lookupCommonParamAutocommit           = "autocommit"
 	lookupCommonParamMultiShardAutocommit = "multi_shard_autocommit"
+	lookupCommonParamAutocommitCreate     = "autocommit_create"
+	lookupCommonParamAutocommitDelete     = "autocommit_delete"
+	lookupCommonParamAutocommitUpdate     = "autocommit_update"
 
 	lookupInternalParamTable       = "table"
 	lookupInternalParamFrom        = "from"
@@ -46,6 +50,7 @@ const (
 	lookupInternalParamIgnoreNulls = "ignore_nulls"
 	lookupInternalParamBatchLookup = "batch_lookup"
 	lookupInternalParamReadLock    = "read_lock"
+	lookupInternalParamReadTarget  = "read_target"
 )
 
 var (
@@ -55,11 +60,22 @@ var (
 		readLockNone:      "",
 	}
 
+	// readTargets are the tablet types that lookup selects can be forced onto
+	// with the read_target param.
+	readTargets = map[string]topodatapb.TabletType{
+		"primary": topodatapb.TabletType_PRIMARY,
+		"replica": topodatapb.TabletType_REPLICA,
+		"rdonly":  topodatapb.TabletType_RDONLY,
+	}
+
 	// lookupCommonParams are used only by lookup_* vindexes.
 	lookupCommonParams = append(
 		append(make([]string, 0), lookupInternalParams...),
 		lookupCommonParamAutocommit,
 		lookupCommonParamMultiShardAutocommit,
+		lookupCommonParamAutocommitCreate,
+		lookupCommonParamAutocommitDelete,
+		lookupCommonParamAutocommitUpdate,
 	)
 
 	// lookupInternalParams are used by both lookup_* vindexes and the newer
@@ -71,6 +87,7 @@ var (
 		lookupInternalParamIgnoreNulls,
 		lookupInternalParamBatchLookup,
 		lookupInternalParamReadLock,
+		lookupInternalParamReadTarget,
 	}
 )
 
@@ -85,7 +102,17 @@ type lookupInternal struct {
 	IgnoreNulls             bool     `json:"ignore_nulls,omitempty"`
 	BatchLookup             bool     `json:"batch_lookup,omitempty"`
 	ReadLock                string   `json:"read_lock,omitempty"`
+	ReadTarget              string   `json:"read_target,omitempty"`
 	sel, selTxDml, ver, del string   // sel: map query, ver: verify query, del: delete query
+	verBatch                string   // verBatch: verify query for many ids at once
+
+	// createAutocommit, deleteAutocommit and updateAutocommit are the
+	// autocommit of each write operation, which default to Autocommit.
+	createAutocommit, deleteAutocommit, updateAutocommit bool
+
+	// readTabletType is the tablet type of ReadTarget, or UNKNOWN if the
+	// lookup table is read from the tablet type of the session.
+	readTabletType topodatapb.TabletType
 }
 
 func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit, upsert, multiShardAutocommit bool) error {
@@ -112,6 +139,14 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 		}
 		lkp.ReadLock = readLock
 	}
+	if readTarget, ok := lookupQueryParams[lookupInternalParamReadTarget]; ok {
+		tabletType, valid := readTargets[readTarget]
+		if !valid {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s value must be 'primary', 'replica' or 'rdonly': '%s'", lookupInternalParamReadTarget, readTarget)
+		}
+		lkp.ReadTarget = readTarget
+		lkp.readTabletType = tabletType
+	}
 
 	lkp.Autocommit = autocommit
 	lkp.Upsert = upsert
@@ -119,6 +154,9 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 		lkp.Autocommit = true
 		lkp.MultiShardAutocommit = true
 	}
+	lkp.createAutocommit = lkp.Autocommit
+	lkp.deleteAutocommit = lkp.Autocommit
+	lkp.updateAutocommit = lkp.Autocommit
 
 	// TODO @rafael: update sel and ver to support multi column vindexes. This will be done
 	// as part of face 2 of https://github.com/vitessio/vitess/issues/3481
@@ -134,6 +172,7 @@ func (lkp *lookupInternal) Init(lookupQueryParams map[string]string, autocommit,
 		lkp.selTxDml = lkp.sel
 	}
 	lkp.ver = fmt.Sprintf("select %s from %s where %s = :%s and %s = :%s", lkp.FromColumns[0], lkp.Table, lkp.FromColumns[0], lkp.FromColumns[0], lkp.To, lkp.To)
+	lkp.verBatch = fmt.Sprintf("select %s, %s from %s where %s in ::%s and %s in ::%s", lkp.FromColumns[0], lkp.To, lkp.Table, lkp.FromColumns[0], lkp.FromColumns[0], lkp.To, lkp.To)
 	lkp.del = lkp.initDelStmt()
 	return nil
 }
@@ -148,11 +187,21 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 		co = vtgatepb.CommitOrder_AUTOCOMMIT
 	}
 	var sel string
-	if vcursor.InTransactionAndIsDML() {
+	inTxDML := vcursor.InTransactionAndIsDML()
+	if inTxDML {
 		sel = lkp.selTxDml
 	} else {
 		sel = lkp.sel
 	}
+	// the read target only applies outside of transactions: a lookup that runs
+	// in a transaction must see the rows that the transaction wrote.
+	onReadTarget := lkp.readTabletType != topodatapb.TabletType_UNKNOWN && !vcursor.InTransaction()
+	execute := func(bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+		if onReadTarget {
+			return vcursor.ExecuteOnTabletType(ctx, "VindexLookup", sel, bindVars, lkp.readTabletType)
+		}
+		return vcursor.Execute(ctx, "VindexLookup", sel, bindVars, false /* rollbackOnError */, co)
+	}
 	if ids[0].IsIntegral() || lkp.BatchLookup {
 		// for integral types, batch query all ids and then map them back to the input order
 		vars, err := sqltypes.BuildBindVariable(ids)
@@ -162,7 +211,7 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 		bindVars := map[string]*querypb.BindVariable{
 			lkp.FromColumns[0]: vars,
 		}
-		result, err := vcursor.Execute(ctx, "VindexLookup", sel, bindVars, false /* rollbackOnError */, co)
+		result, err := execute(bindVars)
 		if err != nil {
 			return nil, vterrors.Wrap(err, "lookup.Map")
 		}
@@ -187,7 +236,7 @@ func (lkp *lookupInternal) Lookup(ctx context.Context, vcursor VCursor, ids []sq
 				lkp.FromColumns[0]: vars,
 			}
 			var result *sqltypes.Result
-			result, err = vcursor.Execute(ctx, "VindexLookup", sel, bindVars, false /* rollbackOnError */, co)
+			result, err = execute(bindVars)
 			if err != nil {
 				return nil, vterrors.Wrap(err, "lookup.Map")
 			}
@@ -228,6 +277,61 @@ func (lkp *lookupInternal) VerifyCustom(ctx context.Context, vcursor VCursor, id
 	return out, nil
 }
 
+// VerifyBatch returns true if ids map to values, like Verify, but verifies all
+// of them with a single query when they can be batched like in Lookup.
+func (lkp *lookupInternal) VerifyBatch(ctx context.Context, vcursor VCursor, ids, values []sqltypes.Value) ([]bool, error) {
+	co := vtgatepb.CommitOrder_NORMAL
+	if lkp.Autocommit {
+		co = vtgatepb.CommitOrder_AUTOCOMMIT
+	}
+	if len(ids) == 0 || !(ids[0].IsIntegral() || lkp.BatchLookup) {
+		// for non integral and binary type, fallback to send query per id
+		return lkp.VerifyCustom(ctx, vcursor, ids, values, co)
+	}
+
+	// Both the from and the to values are bound, so that only the rows of the
+	// pairs being verified are read, as the verify query of each pair would.
+	fromVars, err := sqltypes.BuildBindVariable(ids)
+	if err != nil {
+		return nil, err
+	}
+	toVars, err := sqltypes.BuildBindVariable(values)
+	if err != nil {
+		return nil, err
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		lkp.FromColumns[0]: fromVars,
+		lkp.To:             toVars,
+	}
+	result, err := vcursor.Execute(ctx, "VindexVerify", lkp.verBatch, bindVars, false /* rollbackOnError */, co)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "lookup.Verify")
+	}
+	resultMap := make(map[string][][]byte)
+	for _, row := range result.Rows {
+		to, err := row[1].ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		resultMap[row[0].ToString()] = append(resultMap[row[0].ToString()], to)
+	}
+
+	out := make([]bool, len(ids))
+	for i, id := range ids {
+		want, err := values[i].ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		for _, to := range resultMap[id.ToString()] {
+			if bytes.Equal(to, want) {
+				out[i] = true
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
 type sorter struct {
 	rowsColValues [][]sqltypes.Value
 	toValues      []sqltypes.Value
@@ -273,7 +377,7 @@ func (v *sorter) Swap(i, j int) {
 // Create(vcursor, [[value_a0, value_b0,], [value_a1, value_b1]], [binary(value_c0), binary(value_c1)])
 // Notice that toValues contains the computed binary value of the keyspace_id.
 func (lkp *lookupInternal) Create(ctx context.Context, vcursor VCursor, rowsColValues [][]sqltypes.Value, toValues []sqltypes.Value, ignoreMode bool) error {
-	if lkp.Autocommit {
+	if lkp.createAutocommit {
 		return lkp.createCustom(ctx, vcursor, rowsColValues, toValues, ignoreMode, vtgatepb.CommitOrder_AUTOCOMMIT)
 	}
 	return lkp.createCustom(ctx, vcursor, rowsColValues, toValues, ignoreMode, vtgatepb.CommitOrder_NORMAL)
@@ -369,9 +473,13 @@ nextRow:
 // Delete(vcursor, [[valuea, valueb]], 52CB7B1B31B2222E)
 func (lkp *lookupInternal) Delete(ctx context.Context, vcursor VCursor, rowsColValues [][]sqltypes.Value, value sqltypes.Value, co vtgatepb.CommitOrder) error {
 	// In autocommit mode, it's not safe to delete. So, it's a no-op.
-	if lkp.Autocommit {
+	if lkp.deleteAutocommit {
 		return nil
 	}
+	return lkp.deleteCustom(ctx, vcursor, rowsColValues, value, co)
+}
+
+func (lkp *lookupInternal) deleteCustom(ctx context.Context, vcursor VCursor, rowsColValues [][]sqltypes.Value, value sqltypes.Value, co vtgatepb.CommitOrder) error {
 	if len(rowsColValues) == 0 {
 		// This code is unreachable. It's just a failsafe.
 		return nil
@@ -397,10 +505,14 @@ func (lkp *lookupInternal) Delete(ctx context.Context, vcursor VCursor, rowsColV
 
 // Update implements the update functionality.
 func (lkp *lookupInternal) Update(ctx context.Context, vcursor VCursor, oldValues []sqltypes.Value, ksid []byte, toValue sqltypes.Value, newValues []sqltypes.Value) error {
-	if err := lkp.Delete(ctx, vcursor, [][]sqltypes.Value{oldValues}, toValue, vtgatepb.CommitOrder_NORMAL); err != nil {
+	if lkp.updateAutocommit {
+		// In autocommit mode, it's not safe to delete, so only the new entry is created.
+		return lkp.createCustom(ctx, vcursor, [][]sqltypes.Value{newValues}, []sqltypes.Value{toValue}, false /* ignoreMode */, vtgatepb.CommitOrder_AUTOCOMMIT)
+	}
+	if err := lkp.deleteCustom(ctx, vcursor, [][]sqltypes.Value{oldValues}, toValue, vtgatepb.CommitOrder_NORMAL); err != nil {
 		return err
 	}
-	return lkp.Create(ctx, vcursor, [][]sqltypes.Value{newValues}, []sqltypes.Value{toValue}, false /* ignoreMode */)
+	return lkp.createCustom(ctx, vcursor, [][]sqltypes.Value{newValues}, []sqltypes.Value{toValue}, false /* ignoreMode */, vtgatepb.CommitOrder_NORMAL)
 }
 
 func (lkp *lookupInternal) initDelStmt() string {
@@ -423,6 +535,10 @@ func (lkp *lookupInternal) query() (selQuery string, arguments []string) {
 type commonConfig struct {
 	autocommit           bool
 	multiShardAutocommit bool
+
+	// autocommitCreate, autocommitDelete and autocommitUpdate override
+	// autocommit for one write operation, they are nil when not set.
+	autocommitCreate, autocommitDelete, autocommitUpdate *bool
 }
 
 func parseCommonConfig(m map[string]string) (*commonConfig, error) {
@@ -434,9 +550,53 @@ func parseCommonConfig(m map[string]string) (*commonConfig, error) {
 	if c.multiShardAutocommit, err = boolFromMap(m, lookupCommonParamMultiShardAutocommit); err != nil {
 		return nil, err
 	}
+	overrides := []struct {
+		param    string
+		override **bool
+	}{
+		{lookupCommonParamAutocommitCreate, &c.autocommitCreate},
+		{lookupCommonParamAutocommitDelete, &c.autocommitDelete},
+		{lookupCommonParamAutocommitUpdate, &c.autocommitUpdate},
+	}
+	for _, o := range overrides {
+		if _, ok := m[o.param]; !ok {
+			continue
+		}
+		value, err := boolFromMap(m, o.param)
+		if err != nil {
+			return nil, err
+		}
+		// multi shard autocommit inserts can't be part of a transaction.
+		if !value && c.multiShardAutocommit {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s cannot be 'false' when %s is 'true'", o.param, lookupCommonParamMultiShardAutocommit)
+		}
+		*o.override = &value
+	}
 	return &c, nil
 }
 
+// createAutocommit returns whether the lookup vindex creates its entries
+// with autocommit.
+func (c *commonConfig) createAutocommit() bool {
+	return c.operationAutocommit(c.autocommitCreate)
+}
+
+func (c *commonConfig) operationAutocommit(override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return c.autocommit || c.multiShardAutocommit
+}
+
+// initOperationAutocommit sets the autocommit of each write operation of the
+// lookup, from the autocommit_create, autocommit_delete and autocommit_update
+// params, falling back to autocommit.
+func (lkp *lookupInternal) initOperationAutocommit(c *commonConfig) {
+	lkp.createAutocommit = c.operationAutocommit(c.autocommitCreate)
+	lkp.deleteAutocommit = c.operationAutocommit(c.autocommitDelete)
+	lkp.updateAutocommit = c.operationAutocommit(c.autocommitUpdate)
+}
+
 func boolFromMap(m map[string]string, key string) (bool, error) {
 	val, ok := m[key]
 	if !ok {
@@ -52,12 +52,18 @@ type vcursor struct {
 	autocommits int
 	pre, post   int
 	keys        []sqltypes.Value
+	tabletTypes []topodatapb.TabletType
+	inTx        bool
 }
 
 func (vc *vcursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	panic("implement me")
 }
 
+func (vc *vcursor) InTransaction() bool {
+	return vc.inTx
+}
+
 func (vc *vcursor) InTransactionAndIsDML() bool {
 	return false
 }
@@ -78,6 +84,11 @@ func (vc *vcursor) ExecuteKeyspaceID(ctx context.Context, keyspace string, ksid
 	return vc.execute(query, bindVars)
 }
 
+func (vc *vcursor) ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error) {
+	vc.tabletTypes = append(vc.tabletTypes, tabletType)
+	return vc.execute(query, bindVars)
+}
+
 func (vc *vcursor) execute(query string, bindvars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	vc.queries = append(vc.queries, &querypb.BoundQuery{
 		Sql:           query,
@@ -207,6 +218,48 @@ func testLookupCreateVindexCommonCases(t *testing.T, testCaseF func(string, map[
 			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "multi_shard_autocommit value must be 'true' or 'false': 'hello'"),
 			nil,
 		),
+		testCaseF(
+			"autocommit per operation",
+			map[string]string{"autocommit": "true", "autocommit_create": "true", "autocommit_delete": "false", "autocommit_update": "false"},
+			nil,
+			nil,
+		),
+		testCaseF(
+			"autocommit_create reject not bool",
+			map[string]string{"autocommit_create": "hello"},
+			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "autocommit_create value must be 'true' or 'false': 'hello'"),
+			nil,
+		),
+		testCaseF(
+			"autocommit_delete reject false with multi_shard_autocommit",
+			map[string]string{"multi_shard_autocommit": "true", "autocommit_delete": "false"},
+			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "autocommit_delete cannot be 'false' when multi_shard_autocommit is 'true'"),
+			nil,
+		),
+		testCaseF(
+			"autocommit_update with multi_shard_autocommit",
+			map[string]string{"multi_shard_autocommit": "true", "autocommit_update": "true"},
+			nil,
+			nil,
+		),
+		testCaseF(
+			"read_target primary",
+			map[string]string{"read_target": "primary"},
+			nil,
+			nil,
+		),
+		testCaseF(
+			"read_target replica",
+			map[string]string{"read_target": "replica"},
+			nil,
+			nil,
+		),
+		testCaseF(
+			"read_target reject unknown values",
+			map[string]string{"read_target": "hello"},
+			vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "read_target value must be 'primary', 'replica' or 'rdonly': 'hello'"),
+			nil,
+		),
 	}
 
 	testCreateVindexes(t, cases)
@@ -487,15 +540,28 @@ func TestLookupNonUniqueVerify(t *testing.T) {
 	require.NoError(t, err)
 
 	wantqueries := []*querypb.BoundQuery{{
+		Sql: "select fromc, toc from t where fromc in ::fromc and toc in ::toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc": sqltypes.TestBindVariable([]any{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}),
+			"toc":   sqltypes.TestBindVariable([]any{[]byte("test1"), []byte("test2")}),
+		},
+	}}
+	utils.MustMatch(t, wantqueries, vc.queries)
+
+	// non-integral ids are verified one query at a time.
+	vc.queries = nil
+	_, err = lnu.Verify(context.Background(), vc, []sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewVarChar("b")}, [][]byte{[]byte("test1"), []byte("test2")})
+	require.NoError(t, err)
+	wantqueries = []*querypb.BoundQuery{{
 		Sql: "select fromc from t where fromc = :fromc and toc = :toc",
 		BindVariables: map[string]*querypb.BindVariable{
-			"fromc": sqltypes.Int64BindVariable(1),
+			"fromc": sqltypes.StringBindVariable("a"),
 			"toc":   sqltypes.BytesBindVariable([]byte("test1")),
 		},
 	}, {
 		Sql: "select fromc from t where fromc = :fromc and toc = :toc",
 		BindVariables: map[string]*querypb.BindVariable{
-			"fromc": sqltypes.Int64BindVariable(2),
+			"fromc": sqltypes.StringBindVariable("b"),
 			"toc":   sqltypes.BytesBindVariable([]byte("test2")),
 		},
 	}}
@@ -581,21 +647,15 @@ func TestLookupNonUniqueVerifyAutocommit(t *testing.T) {
 	require.NoError(t, err)
 
 	wantqueries := []*querypb.BoundQuery{{
-		Sql: "select fromc from t where fromc = :fromc and toc = :toc",
-		BindVariables: map[string]*querypb.BindVariable{
-			"fromc": sqltypes.Int64BindVariable(1),
-			"toc":   sqltypes.BytesBindVariable([]byte("test1")),
-		},
-	}, {
-		Sql: "select fromc from t where fromc = :fromc and toc = :toc",
+		Sql: "select fromc, toc from t where fromc in ::fromc and toc in ::toc",
 		BindVariables: map[string]*querypb.BindVariable{
-			"fromc": sqltypes.Int64BindVariable(2),
-			"toc":   sqltypes.BytesBindVariable([]byte("test2")),
+			"fromc": sqltypes.TestBindVariable([]any{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}),
+			"toc":   sqltypes.TestBindVariable([]any{[]byte("test1"), []byte("test2")}),
 		},
 	}}
 
 	utils.MustMatch(t, wantqueries, vc.queries)
-	assert.Equal(t, 2, vc.autocommits, "autocommits")
+	assert.Equal(t, 1, vc.autocommits, "autocommits")
 }
 
 func TestLookupNonUniqueCreate(t *testing.T) {
@@ -857,3 +917,81 @@ func createLookup(t *testing.T, name string, writeOnly bool) SingleColumn {
 	require.Empty(t, l.(ParamValidating).UnknownParams())
 	return l.(SingleColumn)
 }
+
+func TestLookupNonUniqueStats(t *testing.T) {
+	vindex, err := CreateVindex("lookup", "lookup_stats", map[string]string{
+		"table": "t",
+		"from":  "fromc",
+		"to":    "toc",
+	})
+	require.NoError(t, err)
+	lnu := vindex.(SingleColumn)
+	vc := &vcursor{numRows: 2}
+
+	_, err = lnu.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	_, err = lnu.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	// planned queries read the lookup table themselves, and only call MapResult.
+	_, err = vindex.(LookupPlanable).MapResult([]sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewInt64(5)}, []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("toc", "varbinary"), "ksid4"),
+		{},
+	})
+	require.NoError(t, err)
+
+	calls := lookupCalls.Counts()
+	assert.EqualValues(t, 3, calls["lookup_stats.Map"])
+	assert.Zero(t, calls["lookup_stats.Verify"])
+	rows := lookupRows.Counts()
+	assert.EqualValues(t, 4, rows["lookup_stats.Found"])
+	assert.EqualValues(t, 2, rows["lookup_stats.Missing"])
+}
+
+func TestLookupNonUniqueAutocommitPerOperation(t *testing.T) {
+	lnu, err := CreateVindex("lookup", "lookup", map[string]string{
+		"table":             "t",
+		"from":              "fromc",
+		"to":                "toc",
+		"autocommit":        "true",
+		"autocommit_delete": "false",
+		"autocommit_update": "false",
+	})
+	require.NoError(t, err)
+	require.Empty(t, lnu.(ParamValidating).UnknownParams())
+	vc := &vcursor{}
+
+	// creates fall back to autocommit, with upserts.
+	err = lnu.(Lookup).Create(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("test1")}, false /* ignoreMode */)
+	require.NoError(t, err)
+	assert.Equal(t, 1, vc.autocommits)
+	assert.Equal(t, "insert into t(fromc, toc) values(:fromc_0, :toc_0) on duplicate key update fromc=values(fromc), toc=values(toc)", vc.queries[0].Sql)
+
+	// deletes and updates are transactional.
+	vc = &vcursor{}
+	err = lnu.(Lookup).Delete(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, []byte("test1"))
+	require.NoError(t, err)
+	err = lnu.(Lookup).Update(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)}, []byte("test1"), []sqltypes.Value{sqltypes.NewInt64(2)})
+	require.NoError(t, err)
+	require.Len(t, vc.queries, 3)
+	assert.Equal(t, "delete from t where fromc = :fromc and toc = :toc", vc.queries[0].Sql)
+	assert.Equal(t, "delete from t where fromc = :fromc and toc = :toc", vc.queries[1].Sql)
+	assert.True(t, strings.HasPrefix(vc.queries[2].Sql, "insert into t(fromc, toc) values(:fromc_0, :toc_0)"))
+	assert.Zero(t, vc.autocommits)
+
+	// only creates are autocommitted.
+	lnu, err = CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+		"table":             "t",
+		"from":              "fromc",
+		"to":                "toc",
+		"autocommit_create": "true",
+	})
+	require.NoError(t, err)
+	vc = &vcursor{}
+	err = lnu.(Lookup).Create(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("test1")}, false /* ignoreMode */)
+	require.NoError(t, err)
+	assert.Equal(t, 1, vc.autocommits)
+	err = lnu.(Lookup).Update(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)}, []byte("test1"), []sqltypes.Value{sqltypes.NewInt64(2)})
+	require.NoError(t, err)
+	require.Len(t, vc.queries, 3)
+	assert.Equal(t, 1, vc.autocommits)
+}
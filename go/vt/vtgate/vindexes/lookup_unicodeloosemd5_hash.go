@@ -33,12 +33,14 @@ const (
 )
 
 var (
-	_ SingleColumn    = (*LookupUnicodeLooseMD5Hash)(nil)
-	_ Lookup          = (*LookupUnicodeLooseMD5Hash)(nil)
-	_ ParamValidating = (*LookupUnicodeLooseMD5Hash)(nil)
-	_ SingleColumn    = (*LookupUnicodeLooseMD5HashUnique)(nil)
-	_ Lookup          = (*LookupUnicodeLooseMD5HashUnique)(nil)
-	_ ParamValidating = (*LookupUnicodeLooseMD5HashUnique)(nil)
+	_ SingleColumn     = (*LookupUnicodeLooseMD5Hash)(nil)
+	_ Lookup           = (*LookupUnicodeLooseMD5Hash)(nil)
+	_ ParamValidating  = (*LookupUnicodeLooseMD5Hash)(nil)
+	_ LookupReadTarget = (*LookupUnicodeLooseMD5Hash)(nil)
+	_ SingleColumn     = (*LookupUnicodeLooseMD5HashUnique)(nil)
+	_ Lookup           = (*LookupUnicodeLooseMD5HashUnique)(nil)
+	_ ParamValidating  = (*LookupUnicodeLooseMD5HashUnique)(nil)
+	_ LookupReadTarget = (*LookupUnicodeLooseMD5HashUnique)(nil)
 
 	lookupUnicodeLooseMD5HashParams = append(
 		append(make([]string, 0), lookupCommonParams...),
@@ -90,10 +92,11 @@ func newLookupUnicodeLooseMD5Hash(name string, m map[string]string) (Vindex, err
 		return nil, err
 	}
 
-	// if autocommit is on for non-unique lookup, upsert should also be on.
-	if err := lh.lkp.Init(m, cc.autocommit, cc.autocommit || cc.multiShardAutocommit, cc.multiShardAutocommit); err != nil {
+	// if creates are autocommitted for non-unique lookup, upsert should also be on.
+	if err := lh.lkp.Init(m, cc.autocommit, cc.createAutocommit(), cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lh.lkp.initOperationAutocommit(cc)
 	return lh, nil
 }
 
@@ -236,6 +239,11 @@ func (lh *LookupUnicodeLooseMD5Hash) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lh.lkp)
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (lh *LookupUnicodeLooseMD5Hash) ReadTabletType() topodatapb.TabletType {
+	return lh.lkp.readTabletType
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (lh *LookupUnicodeLooseMD5Hash) UnknownParams() []string {
 	return lh.unknownParams
@@ -284,6 +292,7 @@ func newLookupUnicodeLooseMD5HashUnique(name string, m map[string]string) (Vinde
 	if err := lhu.lkp.Init(m, cc.autocommit, false /* upsert */, cc.multiShardAutocommit); err != nil {
 		return nil, err
 	}
+	lhu.lkp.initOperationAutocommit(cc)
 	return lhu, nil
 }
 
@@ -416,6 +425,11 @@ func (lhu *LookupUnicodeLooseMD5HashUnique) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lhu.lkp)
 }
 
+// ReadTabletType implements the LookupReadTarget interface.
+func (lhu *LookupUnicodeLooseMD5HashUnique) ReadTabletType() topodatapb.TabletType {
+	return lhu.lkp.readTabletType
+}
+
 // IsBackfilling implements the LookupBackfill interface
 func (lhu *LookupUnicodeLooseMD5HashUnique) IsBackfilling() bool {
 	return lhu.writeOnly
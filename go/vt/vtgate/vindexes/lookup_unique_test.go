@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/key"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -59,6 +60,117 @@ func TestLookupUniqueNew(t *testing.T) {
 	if err == nil || err.Error() != want {
 		t.Errorf("Create(bad_scatter): %v, want %s", err, want)
 	}
+
+	_, err = CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+		"table":       "t",
+		"from":        "fromc",
+		"to":          "toc",
+		"read_target": "spare",
+	})
+	require.EqualError(t, err, "read_target value must be 'primary', 'replica' or 'rdonly': 'spare'")
+
+	_, err = CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+		"table":        "t",
+		"from":         "fromc",
+		"to":           "toc",
+		"on_duplicate": "last",
+	})
+	require.EqualError(t, err, "on_duplicate value must be 'error' or 'first': 'last'")
+
+	vindex, err = CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+		"table":        "t",
+		"from":         "fromc",
+		"to":           "toc",
+		"on_duplicate": "first",
+	})
+	require.NoError(t, err)
+	require.Empty(t, vindex.(ParamValidating).UnknownParams())
+
+	// on_duplicate only applies to unique lookups.
+	vindex, err = CreateVindex("lookup", "lookup", map[string]string{
+		"table":        "t",
+		"from":         "fromc",
+		"to":           "toc",
+		"on_duplicate": "first",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"on_duplicate"}, vindex.(ParamValidating).UnknownParams())
+}
+
+func TestLookupUniqueMapOnDuplicateFirst(t *testing.T) {
+	vindex, err := CreateVindex("lookup_unique", "lookup_unique_dup", map[string]string{
+		"table":        "t",
+		"from":         "fromc",
+		"to":           "toc",
+		"on_duplicate": "first",
+	})
+	require.NoError(t, err)
+	lookupUnique := vindex.(SingleColumn)
+	vc := &vcursor{
+		result: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("fromc|toc", "int64|varbinary"),
+			"1|ksid2",
+			"1|ksid1",
+			"2|ksid3",
+		),
+	}
+
+	got, err := lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	want := []key.Destination{
+		key.DestinationKeyspaceID("ksid1"),
+		key.DestinationKeyspaceID("ksid3"),
+		key.DestinationNone{},
+	}
+	require.Equal(t, want, got)
+	require.EqualValues(t, 1, lookupDuplicates.Counts()["lookup_unique_dup"])
+}
+
+func TestLookupUniqueMapReadTarget(t *testing.T) {
+	testCases := []struct {
+		readTarget string
+		want       topodatapb.TabletType
+	}{
+		{"primary", topodatapb.TabletType_PRIMARY},
+		{"replica", topodatapb.TabletType_REPLICA},
+		{"rdonly", topodatapb.TabletType_RDONLY},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.readTarget, func(t *testing.T) {
+			vindex, err := CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+				"table":       "ks.t",
+				"from":        "fromc",
+				"to":          "toc",
+				"read_target": tc.readTarget,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, vindex.(LookupReadTarget).ReadTabletType())
+			vc := &vcursor{numRows: 1}
+
+			_, err = vindex.(SingleColumn).Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+			require.NoError(t, err)
+			require.Len(t, vc.queries, 1)
+			require.Equal(t, "select fromc, toc from ks.t where fromc in ::fromc", vc.queries[0].Sql)
+			require.Equal(t, []topodatapb.TabletType{tc.want}, vc.tabletTypes)
+
+			// in a transaction, the lookup runs in the transaction to see its writes.
+			vc = &vcursor{numRows: 1, inTx: true}
+			_, err = vindex.(SingleColumn).Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+			require.NoError(t, err)
+			require.Len(t, vc.queries, 1)
+			require.Empty(t, vc.tabletTypes)
+		})
+	}
+
+	// without a read target, the lookup runs with the session target.
+	vindex := createLookup(t, "lookup_unique", false)
+	require.Equal(t, topodatapb.TabletType_UNKNOWN, vindex.(LookupReadTarget).ReadTabletType())
+	vc := &vcursor{numRows: 1}
+	_, err := vindex.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	require.Len(t, vc.queries, 1)
+	require.Empty(t, vc.tabletTypes)
 }
 
 func TestLookupUniqueMap(t *testing.T) {
@@ -103,6 +215,87 @@ func TestLookupUniqueMap(t *testing.T) {
 	vc.mustFail = false
 }
 
+func TestLookupUniqueMapDuplicateIDs(t *testing.T) {
+	lookupUnique := createLookup(t, "lookup_unique", false)
+	vc := &vcursor{numRows: 1}
+
+	// non-integral ids are looked up one query at a time.
+	got, err := lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewVarChar("1"), sqltypes.NewVarChar("1"), sqltypes.NewVarChar("2")})
+	require.NoError(t, err)
+	want := []key.Destination{
+		key.DestinationKeyspaceID([]byte("1")),
+		key.DestinationKeyspaceID([]byte("1")),
+		key.DestinationKeyspaceID([]byte("1")),
+	}
+	require.Equal(t, want, got)
+	require.Len(t, vc.queries, 2)
+	require.Equal(t, sqltypes.TestBindVariable([]any{sqltypes.NewVarChar("1")}), vc.queries[0].BindVariables["fromc"])
+	require.Equal(t, sqltypes.TestBindVariable([]any{sqltypes.NewVarChar("2")}), vc.queries[1].BindVariables["fromc"])
+
+	vc.queries = nil
+	vc.numRows = 0
+	got, err = lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewVarChar("1"), sqltypes.NewVarChar("2"), sqltypes.NewVarChar("1")})
+	require.NoError(t, err)
+	want = []key.Destination{
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	require.Equal(t, want, got)
+	require.Len(t, vc.queries, 2)
+
+	vc.numRows = 2
+	_, err = lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewVarChar("1"), sqltypes.NewVarChar("1")})
+	require.EqualError(t, err, "Lookup.Map: unexpected multiple results from vindex t: VARCHAR(\"1\")")
+
+	// integral ids are looked up in a single query with the distinct ids.
+	vc.queries = nil
+	vc.numRows = 1
+	got, err = lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(1), sqltypes.NewInt64(2)})
+	require.NoError(t, err)
+	want = []key.Destination{
+		key.DestinationKeyspaceID([]byte("1")),
+		key.DestinationKeyspaceID([]byte("1")),
+		key.DestinationNone{},
+	}
+	require.Equal(t, want, got)
+	require.Len(t, vc.queries, 1)
+	require.Equal(t, sqltypes.TestBindVariable([]any{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}), vc.queries[0].BindVariables["fromc"])
+}
+
+func TestLookupUniqueMapToValues(t *testing.T) {
+	lookupUnique := createLookup(t, "lookup_unique", false).(*LookupUnique)
+	vc := &vcursor{
+		result: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("fromc|toc", "int64|varbinary"),
+			"1|ksid1",
+			"3|ksid3",
+			"3|ksid4",
+		),
+	}
+
+	got, err := lookupUnique.MapToValues(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	want := [][]sqltypes.Value{
+		{sqltypes.NewVarBinary("ksid1")},
+		{},
+		{sqltypes.NewVarBinary("ksid3"), sqltypes.NewVarBinary("ksid4")},
+	}
+	require.Equal(t, want, got)
+	require.Len(t, vc.queries, 1)
+
+	vc.mustFail = true
+	_, err = lookupUnique.MapToValues(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.EqualError(t, err, "lookup.Map: execute failed")
+
+	// the lookup table is read even in write only mode.
+	lookupUnique = createLookup(t, "lookup_unique", true).(*LookupUnique)
+	vc.mustFail = false
+	got, err = lookupUnique.MapToValues(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	require.Equal(t, [][]sqltypes.Value{{sqltypes.NewVarBinary("ksid1")}}, got)
+}
+
 func TestLookupUniqueMapWriteOnly(t *testing.T) {
 	lookupUnique := createLookup(t, "lookup_unique", true)
 	vc := &vcursor{numRows: 0}
@@ -133,6 +326,28 @@ func TestLookupUniqueVerify(t *testing.T) {
 	}
 }
 
+func TestLookupUniqueVerifyBatch(t *testing.T) {
+	lookupUnique := createLookup(t, "lookup_unique", false)
+	vc := &vcursor{
+		result: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("fromc|toc", "int64|varbinary"),
+			"1|ksid1",
+			"2|ksid2",
+			"3|other",
+		),
+	}
+
+	ids := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3), sqltypes.NewInt64(4)}
+	ksids := [][]byte{[]byte("ksid1"), []byte("ksid2"), []byte("ksid3"), []byte("ksid4")}
+	got, err := lookupUnique.Verify(context.Background(), vc, ids, ksids)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, false, false}, got)
+	require.Len(t, vc.queries, 1)
+	require.Equal(t, "select fromc, toc from t where fromc in ::fromc and toc in ::toc", vc.queries[0].Sql)
+	require.Equal(t, sqltypes.TestBindVariable([]any{ids[0], ids[1], ids[2], ids[3]}), vc.queries[0].BindVariables["fromc"])
+	require.Equal(t, sqltypes.TestBindVariable([]any{ksids[0], ksids[1], ksids[2], ksids[3]}), vc.queries[0].BindVariables["toc"])
+}
+
 func TestLookupUniqueVerifyWriteOnly(t *testing.T) {
 	lookupUnique := createLookup(t, "lookup_unique", true)
 	vc := &vcursor{numRows: 0}
@@ -212,3 +427,95 @@ func TestLookupUniqueUpdate(t *testing.T) {
 		t.Errorf("vc.queries length: %v, want %v", got, want)
 	}
 }
+
+func TestLookupUniqueMultiColumn(t *testing.T) {
+	lookupUnique, err := CreateVindex("lookup_unique", "lookup_unique", map[string]string{
+		"table": "t",
+		"from":  "from1, from2",
+		"to":    "toc",
+	})
+	require.NoError(t, err)
+	require.Empty(t, lookupUnique.(ParamValidating).UnknownParams())
+	vc := &vcursor{}
+
+	err = lookupUnique.(Lookup).Create(context.Background(), vc, [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		{sqltypes.NewInt64(2), sqltypes.NewVarChar("b")},
+	}, [][]byte{[]byte("test1"), []byte("test2")}, false /* ignoreMode */)
+	require.NoError(t, err)
+	err = lookupUnique.(Lookup).Delete(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")}}, []byte("test1"))
+	require.NoError(t, err)
+	err = lookupUnique.(Lookup).Update(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarChar("b")}, []byte("test2"), []sqltypes.Value{sqltypes.NewInt64(3), sqltypes.NewVarChar("c")})
+	require.NoError(t, err)
+
+	wantqueries := []*querypb.BoundQuery{{
+		Sql: "insert into t(from1, from2, toc) values(:from1_0, :from2_0, :toc_0), (:from1_1, :from2_1, :toc_1)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"from1_0": sqltypes.Int64BindVariable(1),
+			"from2_0": sqltypes.StringBindVariable("a"),
+			"toc_0":   sqltypes.BytesBindVariable([]byte("test1")),
+			"from1_1": sqltypes.Int64BindVariable(2),
+			"from2_1": sqltypes.StringBindVariable("b"),
+			"toc_1":   sqltypes.BytesBindVariable([]byte("test2")),
+		},
+	}, {
+		Sql: "delete from t where from1 = :from1 and from2 = :from2 and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"from1": sqltypes.Int64BindVariable(1),
+			"from2": sqltypes.StringBindVariable("a"),
+			"toc":   sqltypes.BytesBindVariable([]byte("test1")),
+		},
+	}, {
+		Sql: "delete from t where from1 = :from1 and from2 = :from2 and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"from1": sqltypes.Int64BindVariable(2),
+			"from2": sqltypes.StringBindVariable("b"),
+			"toc":   sqltypes.BytesBindVariable([]byte("test2")),
+		},
+	}, {
+		Sql: "insert into t(from1, from2, toc) values(:from1_0, :from2_0, :toc_0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"from1_0": sqltypes.Int64BindVariable(3),
+			"from2_0": sqltypes.StringBindVariable("c"),
+			"toc_0":   sqltypes.BytesBindVariable([]byte("test2")),
+		},
+	}}
+	utils.MustMatch(t, wantqueries, vc.queries)
+
+	// the number of columns must match the from columns.
+	err = lookupUnique.(Lookup).Create(context.Background(), vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("test1")}, false /* ignoreMode */)
+	require.ErrorContains(t, err, "VT03030")
+}
+
+func TestLookupUniqueStats(t *testing.T) {
+	vindex, err := CreateVindex("lookup_unique", "lookup_unique_stats", map[string]string{
+		"table": "t",
+		"from":  "fromc",
+		"to":    "toc",
+	})
+	require.NoError(t, err)
+	lookupUnique := vindex.(SingleColumn)
+	vc := &vcursor{
+		result: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("fromc|toc", "int64|varbinary"),
+			"1|ksid1",
+		),
+	}
+
+	_, err = lookupUnique.Map(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)})
+	require.NoError(t, err)
+	_, err = lookupUnique.Verify(context.Background(), vc, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}, [][]byte{[]byte("ksid1"), []byte("ksid2")})
+	require.NoError(t, err)
+	// planned queries read the lookup table themselves, and only call MapResult.
+	_, err = vindex.(LookupPlanable).MapResult([]sqltypes.Value{sqltypes.NewInt64(4)}, []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("toc", "varbinary"), "ksid4"),
+	})
+	require.NoError(t, err)
+
+	calls := lookupCalls.Counts()
+	require.EqualValues(t, 2, calls["lookup_unique_stats.Map"])
+	require.EqualValues(t, 1, calls["lookup_unique_stats.Verify"])
+	rows := lookupRows.Counts()
+	require.EqualValues(t, 3, rows["lookup_unique_stats.Found"])
+	require.EqualValues(t, 3, rows["lookup_unique_stats.Missing"])
+}
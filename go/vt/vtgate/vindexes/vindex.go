@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -42,6 +43,10 @@ type (
 	VCursor interface {
 		Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		ExecuteKeyspaceID(ctx context.Context, keyspace string, ksid []byte, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError, autocommit bool) (*sqltypes.Result, error)
+		// ExecuteOnTabletType executes the query outside of the session's transaction,
+		// on the tablet type instead of the tablet type of the session target.
+		ExecuteOnTabletType(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, tabletType topodatapb.TabletType) (*sqltypes.Result, error)
+		InTransaction() bool
 		InTransactionAndIsDML() bool
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 		ConnCollation() collations.ID
@@ -167,6 +172,14 @@ type (
 		AutoCommitEnabled() bool
 	}
 
+	// LookupReadTarget interfaces lookup vindexes that can read their lookup table
+	// from a fixed tablet type, which is UNKNOWN if they read it from the tablet
+	// type of the session. The lookup query of such vindexes cannot be planned,
+	// as it does not run with the session target.
+	LookupReadTarget interface {
+		ReadTabletType() topodatapb.TabletType
+	}
+
 	// LookupBackfill interfaces all lookup vindexes that can backfill rows, such as LookupUnique.
 	LookupBackfill interface {
 		IsBackfilling() bool
@@ -52,6 +52,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	vtschema "vitess.io/vitess/go/vt/vtgate/schema"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -76,6 +77,9 @@ var (
 	noScatter          bool
 	enableShardRouting bool
 
+	// enableProjectionStats instruments the evalengine expressions of the planned projections
+	enableProjectionStats bool
+
 	// healthCheckRetryDelay is the time to wait before retrying healthcheck
 	healthCheckRetryDelay = 2 * time.Millisecond
 	// healthCheckTimeout is the timeout on the RPC call to tablets
@@ -101,6 +105,9 @@ var (
 	enableViews              bool
 	enableUdfs               bool
 
+	// preferVTGateEvaluation makes the planner evaluate expressions on already fetched columns on vtgate
+	preferVTGateEvaluation bool
+
 	// vtgate views flags
 	queryTimeout int
 
@@ -131,6 +138,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
 	fs.BoolVar(&noScatter, "no_scatter", noScatter, "when set to true, the planner will fail instead of producing a plan that includes scatter queries")
+	fs.BoolVar(&enableProjectionStats, "enable-projection-stats", enableProjectionStats, "Record the number of rows evaluated and the evaluation time of vtgate-side projections, in the query log, the plan stats and the ProjectionEvalRows and ProjectionEvalTime stats.")
 	fs.BoolVar(&enableShardRouting, "enable-partial-keyspace-migration", enableShardRouting, "(Experimental) Follow shard routing rules: enable only while migrating a keyspace shard by shard. See documentation on Partial MoveTables for more. (default false)")
 	fs.DurationVar(&healthCheckRetryDelay, "healthcheck_retry_delay", healthCheckRetryDelay, "health check retry delay")
 	fs.DurationVar(&healthCheckTimeout, "healthcheck_timeout", healthCheckTimeout, "the health check timeout period")
@@ -150,6 +158,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&messageStreamGracePeriod, "message_stream_grace_period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&enableUdfs, "track-udfs", enableUdfs, "Track UDFs in vtgate.")
+	fs.BoolVar(&preferVTGateEvaluation, "planner-prefer-vtgate-evaluation", preferVTGateEvaluation, "Evaluate expressions that only depend on columns already fetched from a route on vtgate, instead of adding them as extra columns to the route.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
@@ -314,6 +323,7 @@ func Init(
 		si = st
 	}
 
+	engine.SetProjectionStatsEnabled(enableProjectionStats)
 	plans := DefaultPlanCache()
 
 	executor := NewExecutor(
@@ -57,6 +57,7 @@ var (
 	topoInformationRefreshDuration = 15 * time.Second
 	recoveryPollDuration           = 1 * time.Second
 	ersEnabled                     = true
+	ersAllowNonServing             = true
 	convertTabletsWithErrantGTIDs  = false
 )
 
@@ -78,6 +79,7 @@ func RegisterFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&topoInformationRefreshDuration, "topo-information-refresh-duration", topoInformationRefreshDuration, "Timer duration on which VTOrc refreshes the keyspace and vttablet records from the topology server")
 	fs.DurationVar(&recoveryPollDuration, "recovery-poll-duration", recoveryPollDuration, "Timer duration on which VTOrc polls its database to run a recovery")
 	fs.BoolVar(&ersEnabled, "allow-emergency-reparent", ersEnabled, "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
+	fs.BoolVar(&ersAllowNonServing, "allow-emergency-reparent-non-serving", ersAllowNonServing, "Whether VTOrc should be allowed to run emergency reparent operation on a shard whose primary is not serving, like the target shards of a reshard")
 	fs.BoolVar(&convertTabletsWithErrantGTIDs, "change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs, "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
 }
 
@@ -142,6 +144,16 @@ func SetERSEnabled(val bool) {
 	ersEnabled = val
 }
 
+// ERSAllowNonServing reports whether VTOrc is allowed to run ERS on a shard whose primary is not serving.
+func ERSAllowNonServing() bool {
+	return ersAllowNonServing
+}
+
+// SetERSAllowNonServing sets the value for the ersAllowNonServing variable. This should only be used from tests.
+func SetERSAllowNonServing(val bool) {
+	ersAllowNonServing = val
+}
+
 // ConvertTabletWithErrantGTIDs reports whether VTOrc is allowed to change the tablet type of tablets with errant GTIDs to DRAINED.
 func ConvertTabletWithErrantGTIDs() bool {
 	return convertTabletsWithErrantGTIDs